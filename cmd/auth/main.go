@@ -33,6 +33,21 @@ func main() {
 
 	reflection.Register(server)
 
+	// Admin RPCs are kept off the public port.
+	adminServer := grpc.NewServer()
+	proto.RegisterUserAdminServiceServer(adminServer, auth.NewAdminService(db))
+
+	adminLis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", config.AdminHost, config.AuthAdminPort))
+	if err != nil {
+		log.Fatalf("Failed to listen on admin port %d: %v", config.AuthAdminPort, err)
+	}
+	go func() {
+		log.Printf("Starting Auth admin service on %s:%d", config.AdminHost, config.AuthAdminPort)
+		if err := adminServer.Serve(adminLis); err != nil {
+			log.Fatalf("Failed to serve admin: %v", err)
+		}
+	}()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.AuthServicePort))
 	if err != nil {
 		log.Fatalf("Failed to listen on port %d: %v", config.AuthServicePort, err)
@@ -6,8 +6,12 @@ import (
 	"log"
 	"net"
 
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunkstore"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/gateway"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/retention"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
 
 	"google.golang.org/grpc"
@@ -26,14 +30,42 @@ func main() {
 	}
 	defer sqlDB.Close()
 
-	s3Client, err := utils.NewS3Client(context.Background(), config.AWSRegion, config.AWSBucketName)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage, err := utils.NewBlobStore(ctx, config)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
+
+	keyManager, err := utils.NewKeyManager(ctx, config)
 	if err != nil {
-		log.Fatalf("Failed to initialize S3 client: %v", err)
+		log.Fatalf("Failed to initialize key manager: %v", err)
+	}
+	if keyManager != nil {
+		encrypted := utils.NewEncryptedBlobStore(storage, keyManager, db)
+		go encrypted.StartKeyRotation(ctx, config.KeyRotationInterval, config.MasterKeyMaxAge)
+		storage = encrypted
 	}
 
+	if err := events.SetWriteVersion(config.EventSchemaVersion); err != nil {
+		log.Fatalf("Failed to configure event schema: %v", err)
+	}
+	bus, err := utils.NewEventBus(config)
+	if err != nil {
+		log.Fatalf("Failed to initialize event bus: %v", err)
+	}
+	defer bus.Close()
+
+	relay := outbox.NewRelay(db, bus, config.OutboxRetention)
+	go relay.Start(ctx, config.OutboxPollInterval)
+
+	pruner := retention.NewPruner(db, chunkstore.New(db, storage))
+	go pruner.Start(ctx, config.RetentionPruneInterval)
+
 	server := grpc.NewServer()
 
-	fileService := gateway.NewFileGatewayService(db, s3Client)
+	fileService := gateway.NewFileGatewayService(db, storage, config.JWTSecret)
 	proto.RegisterFileServiceServer(server, fileService)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.GatewayServicePort))
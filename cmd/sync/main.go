@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/anomaly"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/sync"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
@@ -25,17 +29,63 @@ func main() {
 	}
 	defer sqlDB.Close()
 
-	kafka, err := utils.NewKafkaClient(config.KafkaBrokers)
+	if err := events.SetWriteVersion(config.EventSchemaVersion); err != nil {
+		log.Fatalf("Failed to configure event schema: %v", err)
+	}
+	bus, err := utils.NewEventBus(config)
+	if err != nil {
+		log.Fatalf("Failed to initialize event bus: %v", err)
+	}
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage, err := utils.NewBlobStore(ctx, config)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
+
+	keyManager, err := utils.NewKeyManager(ctx, config)
 	if err != nil {
-		log.Fatalf("Failed to initialize Kafka client: %v", err)
+		log.Fatalf("Failed to initialize key manager: %v", err)
 	}
-	defer kafka.Close()
+	if keyManager != nil {
+		storage = utils.NewEncryptedBlobStore(storage, keyManager, db)
+	}
+
+	relay := outbox.NewRelay(db, bus, config.OutboxRetention)
+	go relay.Start(ctx, config.OutboxPollInterval)
 
 	server := grpc.NewServer()
 
-	syncService := sync.NewSyncService(db, kafka)
+	detector := anomaly.NewDetector(anomaly.Thresholds{
+		Window:           config.AnomalyWindow,
+		MaxModifications: config.AnomalyMaxModifications,
+		MaxDeletions:     config.AnomalyMaxDeletions,
+		MaxEntropyJumps:  config.AnomalyMaxEntropyJumps,
+	})
+
+	syncService := sync.NewSyncService(db, bus, storage, detector)
 	proto.RegisterSyncServiceServer(server, syncService)
 
+	go syncService.Start(ctx, config.EventGroupID, config.EventMaxAttempts)
+
+	// Admin RPCs are kept off the public port.
+	adminServer := grpc.NewServer()
+	proto.RegisterEventAdminServiceServer(adminServer, sync.NewAdminService(db))
+
+	adminLis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", config.AdminHost, config.SyncAdminPort))
+	if err != nil {
+		log.Fatalf("Failed to listen on admin port %d: %v", config.SyncAdminPort, err)
+	}
+	go func() {
+		log.Printf("Starting Sync admin service on %s:%d", config.AdminHost, config.SyncAdminPort)
+		if err := adminServer.Serve(adminLis); err != nil {
+			log.Fatalf("Failed to serve admin: %v", err)
+		}
+	}()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.SyncServicePort))
 	if err != nil {
 		log.Fatalf("Failed to listen on port %d: %v", config.SyncServicePort, err)
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/agent"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+func main() {
+	config, err := utils.LoadAgentConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	syncAgent, err := agent.New(config)
+	if err != nil {
+		log.Fatalf("Failed to start sync agent: %v", err)
+	}
+	defer syncAgent.Close()
+
+	if err := syncAgent.Run(ctx); err != nil {
+		log.Fatalf("Sync agent failed: %v", err)
+	}
+}
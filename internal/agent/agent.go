@@ -0,0 +1,248 @@
+// Package agent is the sync client. It mirrors a local folder with a
+// user's files on the servers: local changes are uploaded through the
+// gateway, and changes made on other devices are applied as they are
+// streamed from the sync service.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunker"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+)
+
+// reconnectDelay is how long the agent waits before reopening a change
+// stream that broke.
+const reconnectDelay = 5 * time.Second
+
+type Agent struct {
+	config     *utils.AgentConfig
+	root       string
+	state      *gorm.DB
+	conns      []*grpc.ClientConn
+	auth       proto.AuthServiceClient
+	files      proto.FileServiceClient
+	sync       proto.SyncServiceClient
+	watcher    *FileWatcher
+	reconciler *Reconciler
+	chunker    *chunker.Chunker
+
+	userID   string
+	deviceID string
+}
+
+func New(config *utils.AgentConfig) (*Agent, error) {
+	root, err := filepath.Abs(config.SyncRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	state, err := OpenState(config.StatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %v", err)
+	}
+
+	a := &Agent{
+		config:     config,
+		root:       root,
+		state:      state,
+		reconciler: NewReconciler(root, state, config.ScanHashRate),
+		chunker:    chunker.NewDefault(),
+	}
+
+	dial := func(addr string) (*grpc.ClientConn, error) {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+		}
+		a.conns = append(a.conns, conn)
+		return conn, nil
+	}
+
+	authConn, err := dial(config.AuthAddr)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	gatewayConn, err := dial(config.GatewayAddr)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	syncConn, err := dial(config.SyncAddr)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	a.auth = proto.NewAuthServiceClient(authConn)
+	a.files = proto.NewFileServiceClient(gatewayConn)
+	a.sync = proto.NewSyncServiceClient(syncConn)
+
+	if a.watcher, err = NewFileWatcher(); err != nil {
+		a.Close()
+		return nil, fmt.Errorf("failed to create file watcher: %v", err)
+	}
+	a.watcher.PairByContent(a.syncedHash)
+
+	return a, nil
+}
+
+func (a *Agent) Close() error {
+	if a.watcher != nil {
+		a.watcher.Close()
+	}
+	for _, conn := range a.conns {
+		conn.Close()
+	}
+	sqlDB, err := a.state.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Run signs in and syncs until the context is cancelled. Local and remote
+// changes are handled one at a time, so they never race on a file.
+func (a *Agent) Run(ctx context.Context) error {
+	ctx, err := a.signIn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.loadDeviceID(); err != nil {
+		return err
+	}
+
+	if err := a.watcher.AddPath(a.root); err != nil {
+		return fmt.Errorf("failed to watch %s: %v", a.root, err)
+	}
+	go a.watcher.Start(ctx)
+
+	// Scanning once the watcher is running leaves no gap for changes to
+	// slip through.
+	scanned := make(chan Change, 256)
+	go a.reconciler.Start(ctx, a.config.ScanInterval, scanned)
+
+	// On the first run the whole journal is replayed, which downloads the
+	// user's existing files.
+	cursor, err := getSetting(a.state, cursorSetting)
+	if err != nil {
+		return err
+	}
+	if cursor == "" {
+		cursor = journal.FormatCursor(0)
+	}
+	remote := make(chan remoteChange)
+	go a.watchRemote(ctx, cursor, remote)
+
+	stats := a.watcher.Stats()
+	log.Printf("Syncing %s as device %s, watching %d directories and polling %d trees",
+		a.root, a.deviceID, stats.Watches, stats.Polled)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case change := <-a.watcher.Changes():
+			if err := a.handleLocal(ctx, change); err != nil {
+				log.Printf("Failed to sync %s: %v", change.Path, err)
+			}
+
+		case change := <-scanned:
+			if err := a.handleLocal(ctx, change); err != nil {
+				log.Printf("Failed to sync %s: %v", change.Path, err)
+			}
+
+		case change := <-remote:
+			// The cursor only moves past changes that were applied.
+			err := a.applyRemote(ctx, change.event)
+			if err == nil && change.event.Cursor != "" {
+				if err := setSetting(a.state, cursorSetting, change.event.Cursor); err != nil {
+					return fmt.Errorf("failed to save cursor: %v", err)
+				}
+			}
+			change.applied <- err
+		}
+	}
+}
+
+// signIn returns a context that carries the user's token.
+func (a *Agent) signIn(ctx context.Context) (context.Context, error) {
+	resp, err := a.auth.SignIn(ctx, &proto.SignInRequest{
+		Email:    a.config.Email,
+		Password: a.config.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign in: %v", err)
+	}
+
+	a.userID = resp.UserId
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+resp.Token), nil
+}
+
+func (a *Agent) loadDeviceID() error {
+	if a.config.DeviceID != "" {
+		a.deviceID = a.config.DeviceID
+		return nil
+	}
+
+	deviceID, err := getSetting(a.state, deviceIDSetting)
+	if err != nil {
+		return err
+	}
+	if deviceID == "" {
+		deviceID = uuid.New().String()
+		if err := setSetting(a.state, deviceIDSetting, deviceID); err != nil {
+			return err
+		}
+	}
+	a.deviceID = deviceID
+	return nil
+}
+
+// relative returns the slash-separated path of a file under the root.
+func (a *Agent) relative(path string) (string, error) {
+	rel, err := filepath.Rel(a.root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside %s", path, a.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// syncedHash returns the hash of the file last synced at a path, or "".
+func (a *Agent) syncedHash(path string) string {
+	rel, err := a.relative(path)
+	if err != nil {
+		return ""
+	}
+	synced, err := localFileAt(a.state, rel)
+	if err != nil || synced == nil {
+		return ""
+	}
+	return synced.Hash
+}
+
+// absolute returns the local path of a slash-separated path, refusing
+// paths that would leave the root.
+func (a *Agent) absolute(rel string) (string, error) {
+	path := filepath.FromSlash(rel)
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("invalid path %q", rel)
+	}
+	return filepath.Join(a.root, path), nil
+}
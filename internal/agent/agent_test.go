@@ -0,0 +1,228 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/agent"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/anomaly"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/auth"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/gateway"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/sync"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+const (
+	testEmail    = "alice@example.com"
+	testPassword = "correct horse battery staple"
+
+	// syncTimeout bounds every wait for a change to reach another device.
+	// It covers a reconnect after a failed change.
+	syncTimeout = 20 * time.Second
+)
+
+func init() {
+	// Immediate transactions already keep one relay at a time, which the
+	// advisory lock does on Postgres.
+	gosqlite.MustRegisterScalarFunction("pg_try_advisory_xact_lock", 1,
+		func(*gosqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+			return true, nil
+		})
+}
+
+// startServices runs the auth, gateway and sync services on one listener,
+// backed by sqlite, a local blob store and an in-memory event bus, with a
+// user to sign in as. It returns the listener's address.
+func startServices(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(dir, "sync.db")+"?_txlock=immediate&_pragma=busy_timeout(10000)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.UserKey{}, &models.File{}, &models.FileKey{}, &models.FileVersion{},
+		&models.Chunk{}, &models.ChunkOwner{}, &models.VersionChunk{}, &models.RetentionPolicy{},
+		&models.DeviceAlert{}, &models.DeviceActivity{}, &models.FileEntropy{}, &models.HeldChange{}, &models.OutboxEvent{}, &models.ProcessedEvent{},
+		&models.DeadLetter{}, &models.ChangeEntry{}, &models.JournalHead{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	storage, err := utils.NewLocalBlobStore(filepath.Join(dir, "blobs"))
+	if err != nil {
+		t.Fatalf("create blob store: %v", err)
+	}
+	bus := utils.NewMemoryEventBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go outbox.NewRelay(db, bus, time.Hour).Start(ctx, 20*time.Millisecond)
+	syncService := sync.NewSyncService(db, bus, storage, anomaly.NewDetector(anomaly.Thresholds{
+		Window:           time.Minute,
+		MaxModifications: 1000,
+		MaxDeletions:     1000,
+		MaxEntropyJumps:  1000,
+	}))
+	go syncService.Start(ctx, "sync", 3)
+
+	authService := auth.NewAuthService(db, "test-secret")
+	server := grpc.NewServer()
+	proto.RegisterAuthServiceServer(server, authService)
+	proto.RegisterFileServiceServer(server, gateway.NewFileGatewayService(db, storage, "test-secret"))
+	proto.RegisterSyncServiceServer(server, syncService)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	if _, err := authService.SignUp(ctx, &proto.SignUpRequest{Email: testEmail, Password: testPassword, Username: "alice"}); err != nil {
+		t.Fatalf("sign up: %v", err)
+	}
+	return lis.Addr().String()
+}
+
+// startAgent runs an agent as syncd does, mirroring a new folder, and
+// returns the folder.
+func startAgent(t *testing.T, addr, device string) string {
+	t.Helper()
+	root := t.TempDir()
+	syncAgent, err := agent.New(&utils.AgentConfig{
+		SyncRoot:     root,
+		StatePath:    filepath.Join(t.TempDir(), "state.db"),
+		DeviceID:     device,
+		Email:        testEmail,
+		Password:     testPassword,
+		AuthAddr:     addr,
+		GatewayAddr:  addr,
+		SyncAddr:     addr,
+		ScanInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("start agent: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := syncAgent.Run(ctx); err != nil {
+			t.Errorf("agent %s: %v", device, err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		syncAgent.Close()
+	})
+	return root
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(syncTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func hasContent(path string, content []byte) func() bool {
+	return func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && bytes.Equal(data, content)
+	}
+}
+
+func missing(path string) func() bool {
+	return func() bool {
+		_, err := os.Lstat(path)
+		return os.IsNotExist(err)
+	}
+}
+
+func write(t *testing.T, path string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncBetweenDevices(t *testing.T) {
+	addr := startServices(t)
+	laptop := startAgent(t, addr, "laptop")
+	phone := startAgent(t, addr, "phone")
+
+	write(t, filepath.Join(laptop, "notes.txt"), []byte("first draft\n"))
+	eventually(t, "the new file on the phone", hasContent(filepath.Join(phone, "notes.txt"), []byte("first draft\n")))
+
+	write(t, filepath.Join(phone, "notes.txt"), []byte("second draft\n"))
+	eventually(t, "the edit on the laptop", hasContent(filepath.Join(laptop, "notes.txt"), []byte("second draft\n")))
+
+	// Several chunks, streamed from disk.
+	large := make([]byte, 3<<20)
+	rand.New(rand.NewSource(1)).Read(large)
+	write(t, filepath.Join(laptop, "docs", "large.bin"), large)
+	eventually(t, "the large file on the phone", hasContent(filepath.Join(phone, "docs", "large.bin"), large))
+
+	if err := os.Rename(filepath.Join(laptop, "notes.txt"), filepath.Join(laptop, "docs", "notes.txt")); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the move on the phone", hasContent(filepath.Join(phone, "docs", "notes.txt"), []byte("second draft\n")))
+	eventually(t, "the old path gone on the phone", missing(filepath.Join(phone, "notes.txt")))
+
+	if err := os.RemoveAll(filepath.Join(phone, "docs")); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the deletion on the laptop", missing(filepath.Join(laptop, "docs", "large.bin")))
+	eventually(t, "the deletion on the laptop", missing(filepath.Join(laptop, "docs", "notes.txt")))
+}
+
+// A change that fails to apply is retried, rather than skipped by moving
+// the cursor past it.
+func TestFailedChangeIsRetried(t *testing.T) {
+	addr := startServices(t)
+	laptop := startAgent(t, addr, "laptop")
+	phone := startAgent(t, addr, "phone")
+
+	// A directory on the phone is in the way of the download.
+	blocker := filepath.Join(phone, "report.txt")
+	if err := os.Mkdir(blocker, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write(t, filepath.Join(laptop, "report.txt"), []byte("quarterly numbers\n"))
+	time.Sleep(time.Second)
+	if err := os.Remove(blocker); err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "the retried download", hasContent(filepath.Join(phone, "report.txt"), []byte("quarterly numbers\n")))
+}
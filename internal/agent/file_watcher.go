@@ -0,0 +1,637 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	gosync "sync"
+	"syscall"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// pollInterval is how often trees that could not be watched are polled.
+	pollInterval = 2 * time.Second
+	// settleInterval is how often pending changes are checked.
+	settleInterval = 250 * time.Millisecond
+	// quietPeriod is how long a file must go without events to be settled.
+	quietPeriod = 500 * time.Millisecond
+	// maxSettleTime is how long a file held open for writing is waited on
+	// before its change is reported anyway.
+	maxSettleTime = 30 * time.Second
+	// renameWindow is how soon after a file is renamed away a file that
+	// appears elsewhere may be taken for it, when the two ends of the
+	// rename are not reported together.
+	renameWindow = quietPeriod
+)
+
+// Change is a change to a local file, named by its absolute path. A
+// DELETED or RENAMED change may name a directory, for everything in it.
+type Change struct {
+	Type string
+	Path string
+	// OldPath is where a RENAMED file or directory was before, when the
+	// watcher saw both ends of the rename; Path is then where it is now.
+	// Otherwise Path is where it was, and where it went is unknown.
+	OldPath string
+}
+
+// WatchStats reports how the watcher covers its trees.
+type WatchStats struct {
+	// Watches is how many directories are watched for events.
+	Watches int
+	// Polled is how many trees are polled instead, having run out of
+	// watches.
+	Polled int
+}
+
+// FileWatcher watches whole directory trees, skipping hidden files and
+// directories. Directories are watched as they appear and forgotten as
+// they go; once the system runs out of watches, the trees that did not get
+// one are polled instead.
+//
+// Changes are held until a file settles, so the burst of events from one
+// save is reported as a single change once the file is complete.
+type FileWatcher struct {
+	watcher *fsnotify.Watcher
+	changes chan Change
+	// pending holds the changes not yet settled, by path, and renamed the
+	// path of the previous event when it was a rename. Both belong to the
+	// goroutine running Start.
+	pending map[string]*pendingChange
+	renamed string
+	// hashOf returns the hash of the content last seen at a path, or "",
+	// for pairing renames by content. It is set before Start.
+	hashOf func(path string) string
+
+	mu    gosync.Mutex
+	roots map[string]bool
+	dirs  map[string]bool
+	// polled holds the file states last seen in each polled tree.
+	polled map[string]map[string]fileState
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// fileID identifies a file across renames by its device and inode.
+type fileID struct {
+	dev, ino uint64
+}
+
+// pendingChange is a burst of events on one path.
+type pendingChange struct {
+	// first is the burst's first change, and last its latest.
+	first, last string
+	firstAt     time.Time
+	lastAt      time.Time
+	// saved is set when a temporary file was renamed over the path.
+	saved bool
+	// from is the path this one was renamed from, and to the path this
+	// one was renamed to.
+	from, to string
+	// The file as of the latest event, to tell when it stops changing.
+	state fileState
+	id    fileID
+}
+
+func NewFileWatcher() (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileWatcher{
+		watcher: watcher,
+		changes: make(chan Change, 256),
+		pending: make(map[string]*pendingChange),
+		roots:   make(map[string]bool),
+		dirs:    make(map[string]bool),
+		polled:  make(map[string]map[string]fileState),
+	}, nil
+}
+
+// AddPath watches a directory and everything under it. Files already
+// there are not reported.
+func (fw *FileWatcher) AddPath(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if err := fw.addTree(context.Background(), absPath, false); err != nil {
+		return err
+	}
+
+	fw.mu.Lock()
+	fw.roots[absPath] = true
+	fw.mu.Unlock()
+	return nil
+}
+
+func (fw *FileWatcher) RemovePath(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	fw.mu.Lock()
+	delete(fw.roots, absPath)
+	fw.mu.Unlock()
+
+	fw.removeTree(absPath)
+	return nil
+}
+
+// PairByContent lets the watcher pair the two ends of a rename by content
+// where it cannot by identity. hashOf returns the SHA-256 of what was last
+// seen at a path, hex-encoded, or "" when nothing was. It must be called
+// before Start.
+func (fw *FileWatcher) PairByContent(hashOf func(path string) string) {
+	fw.hashOf = hashOf
+}
+
+// Changes returns the changes the watcher has seen, in order.
+func (fw *FileWatcher) Changes() <-chan Change {
+	return fw.changes
+}
+
+// Stats reports how many watches are in use.
+func (fw *FileWatcher) Stats() WatchStats {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return WatchStats{Watches: len(fw.dirs), Polled: len(fw.polled)}
+}
+
+func (fw *FileWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	settle := time.NewTicker(settleInterval)
+	defer settle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// The system reports a rename as a RENAME of the old path
+			// directly followed by a CREATE of the new one.
+			renamedFrom := fw.renamed
+			fw.renamed = ""
+
+			var changeType string
+			switch {
+			case event.Op&fsnotify.Create == fsnotify.Create:
+				info, err := os.Lstat(event.Name)
+				if err == nil && info.IsDir() {
+					if hidden(event.Name) {
+						continue
+					}
+					// Files can land in a new directory before it is
+					// watched, so they are reported as it is added,
+					// unless they moved along with it.
+					moved := renamedFrom != ""
+					if err := fw.addTree(ctx, event.Name, !moved); err != nil {
+						log.Printf("Failed to watch %s: %v", event.Name, err)
+					}
+					if moved {
+						fw.note(events.Created, event.Name)
+						fw.pairRename(renamedFrom, event.Name)
+					}
+					continue
+				}
+				changeType = events.Created
+			case event.Op&fsnotify.Write == fsnotify.Write:
+				changeType = events.Modified
+			case event.Op&fsnotify.Remove == fsnotify.Remove:
+				changeType = events.Deleted
+			case event.Op&fsnotify.Rename == fsnotify.Rename:
+				changeType = events.Renamed
+			default:
+				continue
+			}
+
+			if changeType == events.Deleted || changeType == events.Renamed {
+				// A watch on a renamed directory would keep reporting its
+				// files under the old name.
+				fw.mu.Lock()
+				watched := fw.dirs[event.Name] || fw.polled[event.Name] != nil
+				fw.mu.Unlock()
+				if watched {
+					fw.removeTree(event.Name)
+				}
+			}
+
+			// Hidden files are followed too, as editors save through them,
+			// but never reported.
+			fw.note(changeType, event.Name)
+			switch changeType {
+			case events.Created:
+				if renamedFrom == "" {
+					renamedFrom = fw.renamedFrom(event.Name)
+				}
+				if renamedFrom != "" {
+					fw.pairRename(renamedFrom, event.Name)
+				}
+			case events.Renamed:
+				fw.renamed = event.Name
+			}
+
+		case err, ok := <-fw.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("File watcher error: %v", err)
+
+		case <-ticker.C:
+			fw.poll()
+
+		case <-settle.C:
+			if !fw.flush(ctx) {
+				return nil
+			}
+		}
+	}
+}
+
+func (fw *FileWatcher) Close() error {
+	return fw.watcher.Close()
+}
+
+// emit sends a change, and reports false once the context is cancelled.
+func (fw *FileWatcher) emit(ctx context.Context, change Change) bool {
+	select {
+	case fw.changes <- change:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// addTree watches a directory and the ones under it, reporting the files
+// it finds when report is set. A subtree that gets no watch is polled.
+func (fw *FileWatcher) addTree(ctx context.Context, root string, report bool) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Gone before it could be watched; its removal is reported by
+			// the parent's watch.
+			if os.IsNotExist(err) && path != root {
+				return nil
+			}
+			return err
+		}
+		if path != root && hidden(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.IsDir() {
+			if report && entry.Type().IsRegular() {
+				fw.note(events.Created, path)
+			}
+			return nil
+		}
+
+		fw.mu.Lock()
+		watched := fw.dirs[path] || fw.polled[path] != nil
+		fw.mu.Unlock()
+		if watched {
+			return nil
+		}
+
+		if err := fw.watcher.Add(path); err != nil {
+			if !errors.Is(err, syscall.ENOSPC) {
+				return err
+			}
+			fw.startPolling(ctx, path, report)
+			return filepath.SkipDir
+		}
+
+		fw.mu.Lock()
+		fw.dirs[path] = true
+		fw.mu.Unlock()
+		return nil
+	})
+}
+
+// removeTree forgets the directories at and under path.
+func (fw *FileWatcher) removeTree(path string) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	for dir := range fw.dirs {
+		if within(dir, path) {
+			// The system drops the watches of deleted directories itself.
+			fw.watcher.Remove(dir)
+			delete(fw.dirs, dir)
+		}
+	}
+	for dir := range fw.polled {
+		if within(dir, path) {
+			delete(fw.polled, dir)
+		}
+	}
+}
+
+// startPolling polls a tree that got no watch. The files already there are
+// reported on the first poll when report is set.
+func (fw *FileWatcher) startPolling(ctx context.Context, root string, report bool) {
+	fw.mu.Lock()
+	watches := len(fw.dirs)
+	fw.mu.Unlock()
+	log.Printf("Out of file watches with %d in use; polling %s every %v", watches, root, pollInterval)
+
+	snapshot := make(map[string]fileState)
+	if !report {
+		snapshot = scanTree(root)
+	}
+
+	fw.mu.Lock()
+	fw.polled[root] = snapshot
+	fw.mu.Unlock()
+}
+
+// poll notes the changes in the polled trees since they were last seen.
+func (fw *FileWatcher) poll() {
+	fw.mu.Lock()
+	roots := make([]string, 0, len(fw.polled))
+	for root := range fw.polled {
+		roots = append(roots, root)
+	}
+	fw.mu.Unlock()
+
+	for _, root := range roots {
+		current := scanTree(root)
+
+		fw.mu.Lock()
+		previous, ok := fw.polled[root]
+		if ok {
+			fw.polled[root] = current
+		}
+		fw.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		for path, state := range current {
+			old, seen := previous[path]
+			switch {
+			case !seen:
+				fw.note(events.Created, path)
+			case old.size != state.size || !old.modTime.Equal(state.modTime):
+				fw.note(events.Modified, path)
+			}
+		}
+		for path := range previous {
+			if _, ok := current[path]; !ok {
+				fw.note(events.Deleted, path)
+			}
+		}
+	}
+}
+
+// note adds an event to the pending change at its path.
+func (fw *FileWatcher) note(changeType, path string) {
+	now := time.Now()
+	change, ok := fw.pending[path]
+	if !ok {
+		change = &pendingChange{first: changeType, firstAt: now}
+		fw.pending[path] = change
+	}
+	change.last = changeType
+	change.lastAt = now
+
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	change.state = fileState{size: info.Size(), modTime: info.ModTime()}
+	change.id, _ = identify(info)
+}
+
+// pairRename joins the two ends of a rename. A temporary file written in
+// the same burst and renamed into place is how editors save atomically, so
+// it is forgotten and the save reported as one change; anything else is a
+// move, reported once it settles.
+func (fw *FileWatcher) pairRename(from, to string) {
+	origin, ok := fw.pending[from]
+	if !ok || from == to {
+		return
+	}
+	change := fw.pending[to]
+	// Files already gone by the time their events arrive have no identity
+	// to compare.
+	if origin.id != (fileID{}) && change.id != (fileID{}) && origin.id != change.id {
+		return
+	}
+
+	if origin.first == events.Created && origin.from == "" {
+		delete(fw.pending, from)
+		change.saved = true
+		return
+	}
+	origin.to = to
+	change.from = from
+}
+
+// renamedFrom returns the pending path a created file was renamed from
+// when the two ends of the rename were not reported together. They are
+// matched by identity, or by content where either identity is unknown,
+// and only within renameWindow, so that a reused inode or a copy made
+// later is not taken for the file.
+func (fw *FileWatcher) renamedFrom(path string) string {
+	change := fw.pending[path]
+	var unknown []string
+	for other, origin := range fw.pending {
+		if other == path || origin.last != events.Renamed || origin.to != "" ||
+			change.lastAt.Sub(origin.lastAt) > renameWindow {
+			continue
+		}
+		if origin.id == (fileID{}) || change.id == (fileID{}) {
+			unknown = append(unknown, other)
+			continue
+		}
+		if origin.id == change.id {
+			return other
+		}
+	}
+	if len(unknown) == 0 || fw.hashOf == nil {
+		return ""
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return ""
+	}
+	// The latest rename is the likeliest.
+	sort.Slice(unknown, func(i, j int) bool {
+		return fw.pending[unknown[i]].lastAt.After(fw.pending[unknown[j]].lastAt)
+	})
+	for _, other := range unknown {
+		if fw.hashOf(other) == hash {
+			return other
+		}
+	}
+	return ""
+}
+
+// flush reports the pending changes that have settled, in the order they
+// began, and reports false once the context is cancelled. A file has
+// settled once it has gone a quiet period without events, its size and
+// modification time have held, and no process has it open for writing.
+func (fw *FileWatcher) flush(ctx context.Context) bool {
+	now := time.Now()
+	var ready []string
+	for path, change := range fw.pending {
+		if now.Sub(change.lastAt) >= quietPeriod {
+			ready = append(ready, path)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		return fw.pending[ready[i]].firstAt.Before(fw.pending[ready[j]].firstAt)
+	})
+
+	var writers map[string]bool
+	for _, path := range ready {
+		change, ok := fw.pending[path]
+		if !ok {
+			// Reported along with where it was renamed to.
+			continue
+		}
+		if target, ok := fw.pending[change.to]; ok && target.from == path {
+			// Reported along with where it was renamed to, once that
+			// settles.
+			continue
+		}
+		if hidden(path) && change.from == "" {
+			delete(fw.pending, path)
+			continue
+		}
+
+		info, err := os.Lstat(path)
+		exists := err == nil
+		if exists && info.Mode().IsRegular() {
+			state := fileState{size: info.Size(), modTime: info.ModTime()}
+			if state.size != change.state.size || !state.modTime.Equal(change.state.modTime) {
+				change.state = state
+				change.lastAt = now
+				continue
+			}
+			if now.Sub(change.firstAt) < maxSettleTime {
+				if writers == nil {
+					writers = openForWriting()
+				}
+				if writers[path] {
+					change.lastAt = now
+					continue
+				}
+			}
+		}
+		delete(fw.pending, path)
+
+		if change.from != "" {
+			_, err := os.Lstat(change.from)
+			if exists && os.IsNotExist(err) {
+				delete(fw.pending, change.from)
+				moved := Change{Type: events.Renamed, Path: path, OldPath: change.from}
+				switch {
+				case hidden(path) && hidden(change.from):
+					continue
+				case hidden(path):
+					// Moved out of sight, as good as deleted.
+					moved = Change{Type: events.Renamed, Path: change.from}
+				}
+				if !fw.emit(ctx, moved) {
+					return false
+				}
+				continue
+			}
+			// Something took the old path's place, so this is a copy
+			// left behind, and the old path reports for itself.
+			if origin, ok := fw.pending[change.from]; ok {
+				origin.to = ""
+			}
+		}
+		if hidden(path) {
+			continue
+		}
+
+		var changeType string
+		switch {
+		case !exists && change.first == events.Created && !change.saved:
+			// Came and went, like a temporary file.
+			continue
+		case !exists && change.last == events.Renamed:
+			changeType = events.Renamed
+		case !exists:
+			changeType = events.Deleted
+		case change.saved || change.first != events.Created:
+			// Saved over, or replaced after it was removed or renamed.
+			changeType = events.Modified
+		default:
+			changeType = events.Created
+		}
+		if !fw.emit(ctx, Change{Type: changeType, Path: path}) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanTree returns the states of the regular files in a tree, skipping
+// hidden ones.
+func scanTree(root string) map[string]fileState {
+	files := make(map[string]fileState)
+	filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != root && hidden(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files
+}
+
+func hidden(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".")
+}
+
+// within reports whether path is dir or under it.
+func within(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
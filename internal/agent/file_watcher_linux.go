@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// openForWriting returns the paths of the files that processes have open
+// for writing. Only the processes the agent may inspect are seen, which are
+// at least the user's own.
+func openForWriting() map[string]bool {
+	paths := make(map[string]bool)
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return paths
+	}
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !filepath.IsAbs(target) || paths[target] {
+				continue
+			}
+			if writable(filepath.Join("/proc", proc.Name(), "fdinfo", fd.Name())) {
+				paths[target] = true
+			}
+		}
+	}
+	return paths
+}
+
+// writable reports whether the flags in an fdinfo file open it for writing.
+func writable(fdinfo string) bool {
+	data, err := os.ReadFile(fdinfo)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(line, "flags:")
+		if !ok {
+			continue
+		}
+		flags, err := strconv.ParseUint(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return false
+		}
+		return flags&syscall.O_ACCMODE != syscall.O_RDONLY
+	}
+	return false
+}
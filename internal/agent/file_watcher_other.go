@@ -0,0 +1,9 @@
+//go:build !linux
+
+package agent
+
+// openForWriting cannot tell which files are open elsewhere, so files are
+// taken as settled once their size and modification time hold.
+func openForWriting() map[string]bool {
+	return nil
+}
@@ -0,0 +1,315 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+)
+
+// settleWait is how long a test waits for the watcher to go quiet. It is
+// well past the quiet period, so anything still to come would have come.
+const settleWait = 2 * time.Second
+
+// watch starts a watcher on dirs under a new folder, creating them first,
+// and returns the folder.
+func watch(t *testing.T, dirs ...string) (string, *FileWatcher) {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fw, err := NewFileWatcher()
+	if err != nil {
+		t.Fatalf("create watcher: %v", err)
+	}
+	if err := fw.AddPath(root); err != nil {
+		t.Fatalf("watch %s: %v", root, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fw.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		fw.Close()
+	})
+	return root, fw
+}
+
+// collect returns the changes the watcher reports until it has been quiet
+// for settleWait.
+func collect(fw *FileWatcher) []Change {
+	var changes []Change
+	for {
+		select {
+		case change := <-fw.Changes():
+			changes = append(changes, change)
+		case <-time.After(settleWait):
+			return changes
+		}
+	}
+}
+
+func expectChanges(t *testing.T, got []Change, want ...Change) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes %+v, want %+v", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherReportsNewFile(t *testing.T) {
+	root, fw := watch(t)
+	path := filepath.Join(root, "notes.txt")
+
+	writeFile(t, path, "first draft\n")
+
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: path})
+}
+
+// A file written in several bursts is reported once, after the last one.
+func TestWatcherCoalescesWrites(t *testing.T) {
+	root, fw := watch(t)
+	path := filepath.Join(root, "log.txt")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := file.WriteString("line\n"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: path})
+}
+
+// Editors save by writing a temporary file and renaming it over the
+// original, which is one change to the original.
+func TestWatcherReportsAtomicSaveAsModified(t *testing.T) {
+	for _, temp := range []string{".notes.txt.swp", "notes.txt.tmp"} {
+		t.Run(temp, func(t *testing.T) {
+			root, fw := watch(t)
+			path := filepath.Join(root, "notes.txt")
+			writeFile(t, path, "first draft\n")
+			expectChanges(t, collect(fw), Change{Type: events.Created, Path: path})
+
+			writeFile(t, filepath.Join(root, temp), "second draft\n")
+			if err := os.Rename(filepath.Join(root, temp), path); err != nil {
+				t.Fatal(err)
+			}
+
+			expectChanges(t, collect(fw), Change{Type: events.Modified, Path: path})
+		})
+	}
+}
+
+// A file held open for writing is not reported until it is closed, even
+// when it goes quiet.
+func TestWatcherWaitsForWriters(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("open files are only seen on Linux")
+	}
+	root, fw := watch(t)
+	path := filepath.Join(root, "download.iso")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString("partial"); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(t, collect(fw))
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: path})
+}
+
+// A move within the folder is one change naming both paths, so the file
+// can be moved on the server rather than uploaded again.
+func TestWatcherReportsMove(t *testing.T) {
+	root, fw := watch(t, "inbox", "archive")
+	from := filepath.Join(root, "inbox", "report.pdf")
+	to := filepath.Join(root, "archive", "report.pdf")
+	writeFile(t, from, "quarterly numbers\n")
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: from})
+
+	if err := os.Rename(from, to); err != nil {
+		t.Fatal(err)
+	}
+
+	expectChanges(t, collect(fw), Change{Type: events.Renamed, Path: to, OldPath: from})
+}
+
+// A moved directory is one change for the directory, and its files are
+// still watched under their new names.
+func TestWatcherReportsDirectoryMove(t *testing.T) {
+	root, fw := watch(t, "projects/draft")
+	from := filepath.Join(root, "projects", "draft")
+	to := filepath.Join(root, "projects", "final")
+	writeFile(t, filepath.Join(from, "notes.txt"), "first draft\n")
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: filepath.Join(from, "notes.txt")})
+
+	if err := os.Rename(from, to); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(t, collect(fw), Change{Type: events.Renamed, Path: to, OldPath: from})
+
+	writeFile(t, filepath.Join(to, "notes.txt"), "second draft\n")
+	expectChanges(t, collect(fw), Change{Type: events.Modified, Path: filepath.Join(to, "notes.txt")})
+}
+
+// Removing a directory reports everything in it as deleted, and nothing
+// else.
+func TestWatcherReportsSubtreeDelete(t *testing.T) {
+	root, fw := watch(t, "docs/drafts")
+	files := []string{
+		filepath.Join(root, "docs", "index.md"),
+		filepath.Join(root, "docs", "drafts", "one.md"),
+		filepath.Join(root, "docs", "drafts", "two.md"),
+	}
+	for _, path := range files {
+		writeFile(t, path, path)
+	}
+	collect(fw)
+
+	if err := os.RemoveAll(filepath.Join(root, "docs")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := collect(fw)
+	deleted := make(map[string]bool)
+	for _, change := range changes {
+		if change.Type != events.Deleted {
+			t.Fatalf("changes %+v, want only deletions", changes)
+		}
+		deleted[change.Path] = true
+	}
+	for _, path := range files {
+		covered := false
+		for dir := path; within(dir, root) && dir != root; dir = filepath.Dir(dir) {
+			covered = covered || deleted[dir]
+		}
+		if !covered {
+			t.Errorf("deletion of %s not reported in %+v", path, changes)
+		}
+	}
+
+	// The directory can come back and is watched again.
+	path := filepath.Join(root, "docs", "index.md")
+	if err := os.Mkdir(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "back again\n")
+	expectChanges(t, collect(fw), Change{Type: events.Created, Path: path})
+}
+
+// A rename whose two ends arrive apart, from a file the watcher never
+// identified, is paired by content, but only within renameWindow.
+func TestWatcherPairsRenamesByContent(t *testing.T) {
+	root := t.TempDir()
+	from := filepath.Join(root, "inbox", "report.pdf")
+	to := filepath.Join(root, "archive", "report.pdf")
+	copied := filepath.Join(root, "archive", "copy.pdf")
+	if err := os.Mkdir(filepath.Dir(to), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, to, "quarterly numbers\n")
+	writeFile(t, copied, "quarterly numbers\n")
+	hash, err := hashFile(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		hashOf func(string) string
+		since  time.Duration
+		want   string
+	}{
+		{"same content", func(path string) string { return map[string]string{from: hash}[path] }, 0, from},
+		{"other content", func(string) string { return "other" }, 0, ""},
+		{"no hashes", nil, 0, ""},
+		{"outside the window", func(string) string { return hash }, 2 * renameWindow, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw := &FileWatcher{pending: make(map[string]*pendingChange), changes: make(chan Change, 8)}
+			fw.PairByContent(tt.hashOf)
+			fw.note(events.Renamed, from)
+			fw.pending[from].lastAt = fw.pending[from].lastAt.Add(-tt.since)
+			fw.note(events.Created, to)
+
+			if got := fw.renamedFrom(to); got != tt.want {
+				t.Fatalf("renamedFrom = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Known identities are compared instead of content, within the
+	// window too.
+	for _, since := range []time.Duration{0, 2 * renameWindow} {
+		fw := &FileWatcher{pending: make(map[string]*pendingChange)}
+		fw.PairByContent(func(string) string { return hash })
+		fw.note(events.Renamed, from)
+		fw.note(events.Created, to)
+		fw.pending[from].lastAt = fw.pending[from].lastAt.Add(-since)
+		fw.pending[from].id = fileID{dev: 1, ino: 1}
+		fw.pending[to].id = fileID{dev: 1, ino: 2}
+		if got := fw.renamedFrom(to); got != "" {
+			t.Fatalf("renamedFrom another inode %v after = %q", since, got)
+		}
+		fw.pending[to].id = fw.pending[from].id
+		if got, want := fw.renamedFrom(to), map[time.Duration]string{0: from}[since]; got != want {
+			t.Fatalf("renamedFrom the same inode %v after = %q, want %q", since, got, want)
+		}
+	}
+
+	// Paired, the two ends are reported as one move once they settle.
+	fw := &FileWatcher{pending: make(map[string]*pendingChange), changes: make(chan Change, 8)}
+	fw.PairByContent(func(path string) string { return map[string]string{from: hash}[path] })
+	fw.note(events.Renamed, from)
+	fw.note(events.Created, copied)
+	fw.note(events.Created, to)
+	fw.pairRename(fw.renamedFrom(to), to)
+	for _, change := range fw.pending {
+		change.lastAt = change.lastAt.Add(-quietPeriod)
+	}
+	if !fw.flush(context.Background()) {
+		t.Fatal("flush stopped")
+	}
+	close(fw.changes)
+	var got []Change
+	for change := range fw.changes {
+		got = append(got, change)
+	}
+	expectChanges(t, got, Change{Type: events.Created, Path: copied}, Change{Type: events.Renamed, Path: to, OldPath: from})
+}
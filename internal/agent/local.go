@@ -0,0 +1,537 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handleLocal uploads a local change, or deletes the file on the server
+// when it is gone.
+func (a *Agent) handleLocal(ctx context.Context, change Change) error {
+	rel, err := a.relative(change.Path)
+	if err != nil {
+		return err
+	}
+
+	switch change.Type {
+	case events.Deleted, events.Renamed:
+		if change.OldPath != "" {
+			from, err := a.relative(change.OldPath)
+			if err != nil {
+				return err
+			}
+			return a.moveRemote(ctx, from, rel)
+		}
+		return a.syncRemoved(ctx, rel)
+	default:
+		return a.syncLocal(ctx, rel)
+	}
+}
+
+// syncRemoved deletes a path removed locally on the server, unless
+// something took its place since.
+func (a *Agent) syncRemoved(ctx context.Context, rel string) error {
+	path, err := a.absolute(rel)
+	if err != nil {
+		return err
+	}
+	// Editors that save by renaming over a file leave one in place.
+	if _, err := os.Lstat(path); err == nil {
+		return a.syncLocal(ctx, rel)
+	}
+	return a.deleteRemote(ctx, rel)
+}
+
+// syncLocal uploads a file unless it is what was last synced, which is
+// also how the agent recognizes the files it downloaded itself.
+func (a *Agent) syncLocal(ctx context.Context, rel string) error {
+	path, err := a.absolute(rel)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		// Gone again; the removal has its own event.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	scan, err := a.scanFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hash := scan.hash
+
+	synced, err := localFileAt(a.state, rel)
+	if err != nil {
+		return err
+	}
+	if synced != nil && synced.Hash == hash {
+		return nil
+	}
+	if synced == nil {
+		// A synced file gone from elsewhere with the same content was
+		// moved here while nobody watched.
+		moved, err := a.movedAway(hash)
+		if err != nil {
+			return err
+		}
+		if moved != nil {
+			ok, err := a.moveSynced(ctx, moved, rel)
+			if err != nil {
+				return err
+			}
+			if ok {
+				moved.Path = rel
+				moved.ModTime = info.ModTime()
+				moved.Device, moved.Inode = identity(info)
+				return saveLocalFile(a.state, moved)
+			}
+		}
+	}
+
+	fileID := ""
+	var base vclock.Vector
+	if synced != nil {
+		fileID, base = synced.FileID, synced.Vector
+
+		// A file changed elsewhere since it was synced would lose that
+		// change if uploaded over; both are kept instead.
+		meta, err := a.files.GetFileMetadata(ctx, &proto.FileMetadataRequest{FileId: fileID, UserId: a.userID})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil && meta.VersionId != synced.VersionID {
+			return a.keepBoth(ctx, rel, path)
+		}
+	}
+	resp, err := a.upload(ctx, rel, path, fileID, base, scan)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Uploaded %s", rel)
+	uploaded := &models.LocalFile{
+		Path:      rel,
+		FileID:    resp.FileId,
+		VersionID: resp.VersionId,
+		Vector:    resp.VersionVector,
+		Hash:      hash,
+		Size:      scan.size,
+		ModTime:   info.ModTime(),
+	}
+	uploaded.Device, uploaded.Inode = identity(info)
+	return saveLocalFile(a.state, uploaded)
+}
+
+// keepBoth moves a local file aside as a conflicted copy, uploaded as a
+// new file, and puts the server's version in its place.
+func (a *Agent) keepBoth(ctx context.Context, rel, path string) error {
+	conflict := conflictedCopyPath(path, a.deviceID, time.Now())
+	if err := os.Rename(path, conflict); err != nil {
+		return err
+	}
+	conflictRel, err := a.relative(conflict)
+	if err != nil {
+		return err
+	}
+	log.Printf("Kept local changes to %s as %s", rel, filepath.Base(conflict))
+
+	synced, err := localFileAt(a.state, rel)
+	if err != nil {
+		return err
+	}
+	if err := a.download(ctx, synced.FileID); err != nil {
+		return err
+	}
+	return a.syncLocal(ctx, conflictRel)
+}
+
+// fileScan is what a pass over a file finds: its hash, its size and the
+// hashes of its chunks.
+type fileScan struct {
+	hash   string
+	size   int64
+	chunks []string
+}
+
+// scanFile hashes and chunks a file without holding it in memory.
+func (a *Agent) scanFile(path string) (*fileScan, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	reader := a.chunker.NewReader(io.TeeReader(file, hasher))
+	scan := &fileScan{}
+	for {
+		chunk, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		scan.chunks = append(scan.chunks, chunk.Hash)
+		scan.size += int64(len(chunk.Data))
+	}
+	scan.hash = hex.EncodeToString(hasher.Sum(nil))
+	return scan, nil
+}
+
+// upload sends a scanned file as content-defined chunks, with content only
+// for the chunks the server is missing, reading the file again as it goes.
+// An empty file ID uploads a new file; base is the vector of the version
+// it was synced at. A file that no longer matches its scan is not
+// uploaded; the change has an event of its own.
+func (a *Agent) upload(ctx context.Context, rel, path, fileID string, base vclock.Vector, scan *fileScan) (*proto.FileUploadResponse, error) {
+	missing := make(map[string]bool)
+	if len(scan.chunks) > 0 {
+		resp, err := a.files.HasChunks(ctx, &proto.HasChunksRequest{UserId: a.userID, ChunkHashes: scan.chunks})
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range resp.MissingHashes {
+			missing[hash] = true
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Cancelling the stream abandons the upload.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := a.files.UploadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newRequest := func() *proto.FileUploadRequest {
+		return &proto.FileUploadRequest{
+			UserId:     a.userID,
+			DeviceId:   a.deviceID,
+			FileName:   rel,
+			FileId:     fileID,
+			BaseVector: base,
+		}
+	}
+
+	// An empty file is one message without content.
+	if len(scan.chunks) == 0 {
+		if err := stream.Send(newRequest()); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+	reader := a.chunker.NewReader(file)
+	for i := 0; ; i++ {
+		chunk, err := reader.Next()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF && i == len(scan.chunks) {
+			break
+		}
+		if err == io.EOF || i == len(scan.chunks) || chunk.Hash != scan.chunks[i] {
+			return nil, fmt.Errorf("%s changed while it was uploaded", rel)
+		}
+
+		req := newRequest()
+		req.ChunkHash = chunk.Hash
+		if missing[chunk.Hash] {
+			req.Content = chunk.Data
+			delete(missing, chunk.Hash)
+		}
+		if err := stream.Send(req); err != nil {
+			// The server ended the stream; its error comes with the
+			// response.
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	if resp.ConflictingVersionId != "" {
+		// The change that landed first stays in the file's history.
+		log.Printf("Uploaded %s concurrently with version %s", rel, resp.ConflictingVersionId)
+	}
+	return resp, nil
+}
+
+// deleteRemote deletes the synced files at a path that is gone locally:
+// the file there, or every file under the directory that was there.
+func (a *Agent) deleteRemote(ctx context.Context, rel string) error {
+	synced, err := localFilesUnder(a.state, rel)
+	if err != nil {
+		return err
+	}
+
+	for i := range synced {
+		_, err := a.files.DeleteFile(ctx, &proto.DeleteFileRequest{
+			FileId:   synced[i].FileID,
+			UserId:   a.userID,
+			DeviceId: a.deviceID,
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		log.Printf("Deleted %s", synced[i].Path)
+		if err := a.state.Delete(&synced[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveRemote follows a local rename on the server. A file still the one
+// synced at the old path is moved there without uploading it again;
+// anything else is deleted at the old path and uploaded at the new one.
+func (a *Agent) moveRemote(ctx context.Context, from, to string) error {
+	path, err := a.absolute(to)
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		// Gone again; its later events name only the new path.
+		return a.syncRemoved(ctx, from)
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return a.moveTree(ctx, from, to)
+	}
+
+	synced, err := localFileAt(a.state, from)
+	if err != nil {
+		return err
+	}
+	if synced != nil {
+		same, err := sameFile(synced, path, info)
+		if err != nil {
+			return err
+		}
+		if !same {
+			if err := a.syncRemoved(ctx, from); err != nil {
+				return err
+			}
+		} else {
+			moved, err := a.moveSynced(ctx, synced, to)
+			if err != nil {
+				return err
+			}
+			if moved && unchanged(synced, info) {
+				return nil
+			}
+		}
+	}
+	return a.syncLocal(ctx, to)
+}
+
+// moveTree follows a renamed directory, moving the synced files that went
+// with it and syncing whatever else is in it now.
+func (a *Agent) moveTree(ctx context.Context, from, to string) error {
+	synced, err := localFilesUnder(a.state, from)
+	if err != nil {
+		return err
+	}
+	for i := range synced {
+		rel := to + strings.TrimPrefix(synced[i].Path, from)
+		path, err := a.absolute(rel)
+		if err != nil {
+			return err
+		}
+		if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+			same, err := sameFile(&synced[i], path, info)
+			if err != nil {
+				return err
+			}
+			if same {
+				if _, err := a.moveSynced(ctx, &synced[i], rel); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// Left behind, or removed on the way.
+		old, err := a.absolute(synced[i].Path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(old); os.IsNotExist(err) {
+			if err := a.deleteRemote(ctx, synced[i].Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	root, err := a.absolute(to)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if hidden(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := a.relative(path)
+		if err != nil {
+			return err
+		}
+		synced, err := localFileAt(a.state, rel)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err == nil && synced != nil && unchanged(synced, info) {
+			return nil
+		}
+		return a.syncLocal(ctx, rel)
+	})
+}
+
+// moveSynced moves a synced file to a new path on the server and in the
+// index, replacing any other file synced there. It reports false, having
+// forgotten the file, when the server no longer has it.
+func (a *Agent) moveSynced(ctx context.Context, synced *models.LocalFile, to string) (bool, error) {
+	replaced, err := localFileAt(a.state, to)
+	if err != nil {
+		return false, err
+	}
+	if replaced != nil && replaced.FileID != synced.FileID {
+		if err := a.deleteRemote(ctx, to); err != nil {
+			return false, err
+		}
+	}
+
+	_, err = a.files.MoveFile(ctx, &proto.MoveFileRequest{
+		FileId:   synced.FileID,
+		UserId:   a.userID,
+		DeviceId: a.deviceID,
+		NewName:  to,
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, a.state.Delete(synced).Error
+	}
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("Moved %s to %s", synced.Path, to)
+	moved := *synced
+	moved.Path = to
+	return true, saveLocalFile(a.state, &moved)
+}
+
+// movedAway returns a synced file with the given content that is no longer
+// at its path, or nil.
+func (a *Agent) movedAway(hash string) (*models.LocalFile, error) {
+	var synced []models.LocalFile
+	if err := a.state.Where("hash = ?", hash).Find(&synced).Error; err != nil {
+		return nil, err
+	}
+	for i := range synced {
+		path, err := a.absolute(synced[i].Path)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return &synced[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameFile reports whether the file at path is the one synced: the same
+// file on disk, or one with the same content.
+func sameFile(synced *models.LocalFile, path string, info os.FileInfo) (bool, error) {
+	device, inode := identity(info)
+	if inode != 0 && device == synced.Device && inode == synced.Inode {
+		return true, nil
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return false, err
+	}
+	return hash == synced.Hash, nil
+}
+
+// unchanged reports whether a file looks as it did when it was synced.
+func unchanged(synced *models.LocalFile, info os.FileInfo) bool {
+	return info.Size() == synced.Size && info.ModTime().Equal(synced.ModTime)
+}
+
+// identity returns the device and inode of a file as the index keeps them,
+// or zeros where the system does not expose them.
+func identity(info os.FileInfo) (device, inode int64) {
+	id, ok := identify(info)
+	if !ok {
+		return 0, 0
+	}
+	return int64(id.dev), int64(id.ino)
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,228 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// hashBufferSize is how much a scan reads at a time while hashing.
+const hashBufferSize = 64 * 1024
+
+// Reconciler finds the changes a watcher missed, such as those made while
+// the agent was not running, by comparing the folder with the index of
+// synced files. Files whose size and modification time match the index are
+// taken as unchanged; the others are hashed to tell.
+type Reconciler struct {
+	root     string
+	state    *gorm.DB
+	throttle *throttle
+}
+
+// NewReconciler returns a reconciler that hashes at most rate bytes per
+// second, or without limit when rate is 0.
+func NewReconciler(root string, state *gorm.DB, rate int) *Reconciler {
+	return &Reconciler{
+		root:     root,
+		state:    state,
+		throttle: &throttle{rate: int64(rate)},
+	}
+}
+
+// Start scans now and then on the given interval until the context is
+// cancelled, sending the changes it finds.
+func (r *Reconciler) Start(ctx context.Context, interval time.Duration, changes chan<- Change) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		found, err := r.Scan(ctx, changes)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Scan of %s failed: %v", r.root, err)
+		} else if found > 0 {
+			log.Printf("Scan of %s found %d changes", r.root, found)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan walks the folder once and sends a CREATED change for each file
+// missing from the index, MODIFIED for each whose content differs from it
+// and DELETED for each indexed file that is gone. It returns how many
+// changes it sent.
+func (r *Reconciler) Scan(ctx context.Context, changes chan<- Change) (int, error) {
+	var indexed []models.LocalFile
+	if err := r.state.Find(&indexed).Error; err != nil {
+		return 0, err
+	}
+	index := make(map[string]*models.LocalFile, len(indexed))
+	for i := range indexed {
+		index[indexed[i].Path] = &indexed[i]
+	}
+
+	found := 0
+	send := func(changeType, path string) error {
+		select {
+		case changes <- Change{Type: changeType, Path: path}:
+			found++
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	err := filepath.WalkDir(r.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// A file that vanished mid-walk is picked up as deleted.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if path == r.root {
+			return nil
+		}
+		// Hidden files are skipped like the watcher skips them, which
+		// includes the agent's own state and temporary files.
+		if hidden(path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		synced, ok := index[rel]
+		if !ok {
+			return send(events.Created, path)
+		}
+		delete(index, rel)
+
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Size() == synced.Size && info.ModTime().Equal(synced.ModTime) {
+			return nil
+		}
+
+		hash, err := r.hash(ctx, path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hash != synced.Hash {
+			return send(events.Modified, path)
+		}
+
+		// Touched but unchanged; remembering the new time saves hashing it
+		// again next time.
+		return r.state.Model(&models.LocalFile{}).
+			Where("path = ? AND hash = ?", rel, hash).
+			Update("mod_time", info.ModTime()).Error
+	})
+	if err != nil {
+		return found, err
+	}
+
+	for rel := range index {
+		if err := send(events.Deleted, filepath.Join(r.root, filepath.FromSlash(rel))); err != nil {
+			return found, err
+		}
+	}
+	return found, nil
+}
+
+// hash hashes a file no faster than the throttle allows.
+func (r *Reconciler) hash(ctx context.Context, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	buffer := make([]byte, hashBufferSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			hasher.Write(buffer[:n])
+			if err := r.throttle.wait(ctx, n); err != nil {
+				return "", err
+			}
+		}
+		if err == io.EOF {
+			return hex.EncodeToString(hasher.Sum(nil)), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// throttle is a token bucket of bytes that refills at rate bytes per
+// second and holds at most a second's worth.
+type throttle struct {
+	rate   int64
+	tokens int64
+	last   time.Time
+}
+
+// wait takes n bytes from the bucket, sleeping while it is in debt.
+func (t *throttle) wait(ctx context.Context, n int) error {
+	if t.rate <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if t.last.IsZero() {
+		t.tokens = t.rate
+	} else {
+		t.tokens += int64(now.Sub(t.last).Seconds() * float64(t.rate))
+		if t.tokens > t.rate {
+			t.tokens = t.rate
+		}
+	}
+	t.last = now
+	t.tokens -= int64(n)
+
+	if t.tokens >= 0 {
+		return nil
+	}
+	delay := time.Duration(float64(-t.tokens) / float64(t.rate) * float64(time.Second))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// indexFile records a file in the folder as synced as it is now.
+func indexFile(t *testing.T, state *gorm.DB, root, rel string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := saveLocalFile(state, &models.LocalFile{
+		Path:    rel,
+		FileID:  "file-" + rel,
+		Hash:    hash,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scan(t *testing.T, reconciler *Reconciler) []Change {
+	t.Helper()
+	changes := make(chan Change, 64)
+	found, err := reconciler.Scan(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	close(changes)
+
+	var got []Change
+	for change := range changes {
+		got = append(got, change)
+	}
+	if found != len(got) {
+		t.Errorf("scan counted %d changes, sent %d", found, len(got))
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+	return got
+}
+
+func TestReconcilerFindsMissedChanges(t *testing.T) {
+	root := t.TempDir()
+	state, err := OpenState(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("open state: %v", err)
+	}
+
+	for _, rel := range []string{"unchanged.txt", "touched.txt", "edited.txt", "docs/removed.txt"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, rel)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(root, rel), "synced "+rel+"\n")
+		indexFile(t, state, root, rel)
+	}
+
+	// Changes made while the agent was not running.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "touched.txt"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "edited.txt"), "edited offline\n")
+	if err := os.Remove(filepath.Join(root, "docs", "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "docs", "added.txt"), "new offline\n")
+	writeFile(t, filepath.Join(root, ".hidden"), "never synced\n")
+
+	reconciler := NewReconciler(root, state, 0)
+	want := []Change{
+		{Type: events.Created, Path: filepath.Join(root, "docs", "added.txt")},
+		{Type: events.Deleted, Path: filepath.Join(root, "docs", "removed.txt")},
+		{Type: events.Modified, Path: filepath.Join(root, "edited.txt")},
+	}
+	if got := scan(t, reconciler); !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes %+v, want %+v", got, want)
+	}
+
+	// A touched file keeps its new time, so it is not hashed again.
+	touched, err := localFileAt(state, "touched.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !touched.ModTime.Equal(later) {
+		t.Errorf("touched file recorded at %v, want %v", touched.ModTime, later)
+	}
+}
+
+func TestReconcilerFindsNothingInSyncedFolder(t *testing.T) {
+	root := t.TempDir()
+	state, err := OpenState(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("open state: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"top.txt", "a/middle.txt", "a/b/bottom.txt"} {
+		writeFile(t, filepath.Join(root, rel), rel)
+		indexFile(t, state, root, rel)
+	}
+
+	if got := scan(t, NewReconciler(root, state, 0)); len(got) != 0 {
+		t.Fatalf("changes %+v in a synced folder", got)
+	}
+}
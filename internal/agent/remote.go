@@ -0,0 +1,323 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// remoteChange is a change streamed from the sync service. The stream
+// moves past it only once applied reports success.
+type remoteChange struct {
+	event   *proto.FileChangeEvent
+	applied chan<- error
+}
+
+// watchRemote streams the changes other devices make after the cursor,
+// reopening the stream where it broke until the context is cancelled. A
+// change that fails to apply breaks the stream, so it is retried when the
+// stream reopens.
+func (a *Agent) watchRemote(ctx context.Context, cursor string, changes chan<- remoteChange) {
+	for {
+		err := a.streamChanges(ctx, &cursor, changes)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Change stream ended, reconnecting: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (a *Agent) streamChanges(ctx context.Context, cursor *string, changes chan<- remoteChange) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := a.sync.WatchFileChanges(ctx, &proto.WatchRequest{
+		UserId:   a.userID,
+		DeviceId: a.deviceID,
+		Cursor:   *cursor,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		applied := make(chan error, 1)
+		select {
+		case changes <- remoteChange{event: event, applied: applied}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case err := <-applied:
+			if err != nil {
+				return fmt.Errorf("failed to apply change to file %s: %v", event.FileId, err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if event.Cursor != "" {
+			*cursor = event.Cursor
+		}
+	}
+}
+
+// applyRemote applies a change another device made.
+func (a *Agent) applyRemote(ctx context.Context, event *proto.FileChangeEvent) error {
+	switch event.ChangeType {
+	case proto.FileChangeEvent_ALERT:
+		log.Printf("Device %s was paused: %s; confirm or reject its changes to resume it (alert %s)",
+			event.DeviceId, event.Reason, event.AlertId)
+		return nil
+	case proto.FileChangeEvent_SHARED:
+		// Sharing leaves the file as it is.
+		return nil
+	case proto.FileChangeEvent_DELETED:
+		return a.removeLocal(ctx, event.FileId)
+	default:
+		return a.download(ctx, event.FileId)
+	}
+}
+
+// removeLocal deletes the local copy of a file deleted elsewhere. A copy
+// changed since it was synced is kept, and uploaded as a new file.
+func (a *Agent) removeLocal(ctx context.Context, fileID string) error {
+	synced, err := localFileByID(a.state, fileID)
+	if err != nil || synced == nil {
+		return err
+	}
+	path, err := a.absolute(synced.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := a.state.Delete(synced).Error; err != nil {
+		return err
+	}
+
+	hash, err := hashFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hash != synced.Hash {
+		log.Printf("Keeping %s, deleted on another device but changed here", synced.Path)
+		return a.syncLocal(ctx, synced.Path)
+	}
+
+	log.Printf("Removing %s, deleted on another device", synced.Path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// download brings the local copy of a file up to date with the server,
+// moving it first if the file was renamed. A local file in the way that
+// was changed since it was synced is kept as a conflicted copy.
+func (a *Agent) download(ctx context.Context, fileID string) error {
+	meta, err := a.files.GetFileMetadata(ctx, &proto.FileMetadataRequest{FileId: fileID, UserId: a.userID})
+	if status.Code(err) == codes.NotFound {
+		// Deleted since; the deletion follows.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if meta.Encrypted {
+		log.Printf("Skipping end-to-end encrypted file %s", fileID)
+		return nil
+	}
+
+	rel := meta.FileName
+	path, err := a.absolute(rel)
+	if err != nil {
+		return err
+	}
+
+	synced, err := localFileByID(a.state, fileID)
+	if err != nil {
+		return err
+	}
+	if synced != nil && synced.Path != rel {
+		if synced, err = a.moveLocal(synced, rel); err != nil {
+			return err
+		}
+	}
+	if synced != nil && synced.VersionID == meta.VersionId {
+		return nil
+	}
+
+	current, err := hashFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	atPath, err := localFileAt(a.state, rel)
+	if err != nil {
+		return err
+	}
+	dirty := exists && (atPath == nil || atPath.FileID != fileID || atPath.Hash != current)
+
+	tmp, hash, size, err := a.fetch(ctx, fileID, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	if dirty && hash != current {
+		conflict := conflictedCopyPath(path, a.deviceID, time.Now())
+		if err := os.Rename(path, conflict); err != nil {
+			return err
+		}
+		log.Printf("Kept local changes to %s as %s", rel, filepath.Base(conflict))
+	}
+
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	// The file is recorded before it appears, so the watcher's event for it
+	// is recognized as this download.
+	downloaded := &models.LocalFile{
+		Path:      rel,
+		FileID:    fileID,
+		VersionID: meta.VersionId,
+		Vector:    meta.VersionVector,
+		Hash:      hash,
+		Size:      size,
+		ModTime:   info.ModTime(),
+	}
+	downloaded.Device, downloaded.Inode = identity(info)
+	if err := saveLocalFile(a.state, downloaded); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	log.Printf("Downloaded %s", rel)
+	return nil
+}
+
+// moveLocal follows a rename made elsewhere. A local copy changed since
+// it was synced stays where it is, and the file is downloaded anew.
+func (a *Agent) moveLocal(synced *models.LocalFile, rel string) (*models.LocalFile, error) {
+	from, err := a.absolute(synced.Path)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.absolute(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashFile(from)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	_, err = os.Lstat(to)
+	if hash != synced.Hash || err == nil {
+		return nil, a.state.Delete(synced).Error
+	}
+
+	moved := *synced
+	moved.Path = rel
+	if err := saveLocalFile(a.state, &moved); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Moved %s to %s", synced.Path, rel)
+	return &moved, nil
+}
+
+// fetch downloads the current version of a file into a hidden temporary
+// file in dir, which the watcher ignores, and returns its path, hash and
+// size.
+func (a *Agent) fetch(ctx context.Context, fileID, dir string) (string, string, int64, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", 0, err
+	}
+
+	stream, err := a.files.DownloadFile(ctx, &proto.FileDownloadRequest{FileId: fileID, UserId: a.userID})
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".syncd-*.tmp")
+	if err != nil {
+		return "", "", 0, err
+	}
+	hasher := sha256.New()
+	size, err := receive(stream, io.MultiWriter(tmp, hasher))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", "", 0, err
+	}
+
+	return tmp.Name(), hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+func receive(stream proto.FileService_DownloadFileClient, w io.Writer) (int64, error) {
+	var size int64
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		if resp.Error != "" {
+			return size, fmt.Errorf("download failed: %s", resp.Error)
+		}
+
+		n, err := w.Write(resp.Content)
+		size += int64(n)
+		if err != nil {
+			return size, err
+		}
+	}
+}
+
+// conflictedCopyPath names a conflicted copy like the sync service does,
+// turning "notes.md" into "notes (conflicted copy from laptop 2024-05-01).md".
+func conflictedCopyPath(path, deviceID string, at time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return fmt.Sprintf("%s (conflicted copy from %s %s)%s", base, deviceID, at.Format("2006-01-02"), ext)
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Settings kept in the state database.
+const (
+	deviceIDSetting = "device_id"
+	cursorSetting   = "cursor"
+)
+
+// OpenState opens the agent's state database, creating it if needed.
+func OpenState(path string) (*gorm.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows one writer at a time, and the agent's scans write
+	// alongside its main loop.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&models.LocalFile{}, &models.AgentSetting{}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// getSetting returns a setting, or the empty string when it is not set.
+func getSetting(db *gorm.DB, key string) (string, error) {
+	var setting models.AgentSetting
+	err := db.First(&setting, "key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return setting.Value, err
+}
+
+func setSetting(db *gorm.DB, key, value string) error {
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&models.AgentSetting{Key: key, Value: value}).Error
+}
+
+// localFileAt returns the synced file at a path, or nil.
+func localFileAt(db *gorm.DB, path string) (*models.LocalFile, error) {
+	var file models.LocalFile
+	err := db.First(&file, "path = ?", path).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &file, err
+}
+
+// localFileByID returns the synced copy of a server file, or nil.
+func localFileByID(db *gorm.DB, fileID string) (*models.LocalFile, error) {
+	var file models.LocalFile
+	err := db.First(&file, "file_id = ?", fileID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &file, err
+}
+
+// localFilesUnder returns the synced files at a path and, when it is a
+// directory, under it.
+func localFilesUnder(db *gorm.DB, path string) ([]models.LocalFile, error) {
+	// Paths under path sort between "path/" and "path0", as '0' follows '/'.
+	var files []models.LocalFile
+	err := db.Where("path = ? OR (path > ? AND path < ?)", path, path+"/", path+"0").
+		Order("path").Find(&files).Error
+	return files, err
+}
+
+// saveLocalFile records a file as synced at its path, replacing whatever
+// was recorded there or for the same server file.
+func saveLocalFile(db *gorm.DB, file *models.LocalFile) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("file_id = ? AND path <> ?", file.FileID, file.Path).
+			Delete(&models.LocalFile{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(file).Error
+	})
+}
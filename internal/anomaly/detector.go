@@ -0,0 +1,202 @@
+package anomaly
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// A file jumps in entropy when it gains at least entropyJump bits per
+	// byte and ends up looking random, which is what encrypting it in place
+	// does.
+	entropyJump = 2.0
+	highEntropy = 7.5
+
+	// entropyMemory is how long the last entropy of a file is remembered.
+	entropyMemory = 24 * time.Hour
+)
+
+type Kind string
+
+const (
+	KindModifyBurst Kind = "modify_burst"
+	KindDeleteBurst Kind = "delete_burst"
+	KindEntropyJump Kind = "entropy_jump"
+)
+
+// Thresholds are the most changes of each kind one device may make within
+// Window before it is flagged. A zero threshold disables that check.
+type Thresholds struct {
+	Window           time.Duration
+	MaxModifications int
+	MaxDeletions     int
+	MaxEntropyJumps  int
+}
+
+type Alert struct {
+	UserID   string
+	DeviceID string
+	Kind     Kind
+	Count    int
+	Reason   string
+}
+
+// Detector watches the changes coming from each device and flags devices
+// that behave like ransomware or a runaway script: bursts of modifications
+// or deletions, or many files suddenly turning into random-looking data.
+//
+// Its windows and the entropy of recently seen files are kept in the
+// database, so every sync instance sees all of a device's changes and
+// nothing is forgotten on restart.
+type Detector struct {
+	thresholds Thresholds
+}
+
+func NewDetector(thresholds Thresholds) *Detector {
+	return &Detector{thresholds: thresholds}
+}
+
+// Observe records a change in the transaction handling it and returns an
+// alert when it pushes its device over a threshold. The device's window
+// starts over after an alert.
+func (d *Detector) Observe(tx *gorm.DB, env *proto.EventEnvelope) (*Alert, error) {
+	now, err := time.Parse(time.RFC3339Nano, env.OccurredAt)
+	if err != nil {
+		now = time.Now()
+	}
+	now = now.UTC()
+
+	device := tx.Where("user_id = ? AND device_id = ?", env.UserId, env.DeviceId)
+	if err := device.Session(&gorm.Session{}).Where("occurred_at < ?", now.Add(-d.thresholds.Window)).
+		Delete(&models.DeviceActivity{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ? AND seen_at < ?", env.UserId, now.Add(-entropyMemory)).
+		Delete(&models.FileEntropy{}).Error; err != nil {
+		return nil, err
+	}
+
+	var activity []string
+	// Files are keyed by path, which stays the same across versions even
+	// when a device does not know the file's ID.
+	change := events.Describe(env)
+	switch change.Type {
+	case events.Created, events.Modified:
+		activity = append(activity, models.DeviceActivityModification)
+	case events.Renamed:
+		activity = append(activity, models.DeviceActivityModification)
+		// A moved file is compared with the entropy it had before the
+		// move, so encrypting and renaming files still shows.
+		if renamed := env.GetFileRenamed(); renamed != nil && renamed.OldPath != "" && renamed.OldPath != change.Path {
+			if err := moveEntropy(tx, env.UserId, renamed.OldPath, change.Path); err != nil {
+				return nil, err
+			}
+		}
+	case events.Deleted:
+		activity = append(activity, models.DeviceActivityDeletion)
+		if err := tx.Where("user_id = ? AND path = ?", env.UserId, change.Path).
+			Delete(&models.FileEntropy{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if change.Entropy > 0 {
+		var previous models.FileEntropy
+		err := tx.Where("user_id = ? AND path = ?", env.UserId, change.Path).Limit(1).Find(&previous).Error
+		if err != nil {
+			return nil, err
+		}
+		seen := previous.Path != ""
+		if seen && change.Entropy >= highEntropy && change.Entropy-previous.Entropy >= entropyJump {
+			activity = append(activity, models.DeviceActivityEntropyJump)
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "path"}},
+			DoUpdates: clause.AssignmentColumns([]string{"entropy", "seen_at"}),
+		}).Create(&models.FileEntropy{UserID: env.UserId, Path: change.Path, Entropy: change.Entropy, SeenAt: now}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, kind := range activity {
+		if err := tx.Create(&models.DeviceActivity{
+			UserID:     env.UserId,
+			DeviceID:   env.DeviceId,
+			Kind:       kind,
+			OccurredAt: now,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var counts []struct {
+		Kind  string
+		Count int
+	}
+	if err := device.Session(&gorm.Session{}).Model(&models.DeviceActivity{}).
+		Select("kind, COUNT(*) AS count").
+		Group("kind").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	count := make(map[string]int, len(counts))
+	for _, c := range counts {
+		count[c.Kind] = c.Count
+	}
+
+	var alert *Alert
+	switch jumps, deletions, modifications := count[models.DeviceActivityEntropyJump],
+		count[models.DeviceActivityDeletion], count[models.DeviceActivityModification]; {
+	case over(jumps, d.thresholds.MaxEntropyJumps):
+		alert = &Alert{Kind: KindEntropyJump, Count: jumps,
+			Reason: fmt.Sprintf("%d files became random-looking within %s", jumps, d.thresholds.Window)}
+	case over(deletions, d.thresholds.MaxDeletions):
+		alert = &Alert{Kind: KindDeleteBurst, Count: deletions,
+			Reason: fmt.Sprintf("%d files deleted within %s", deletions, d.thresholds.Window)}
+	case over(modifications, d.thresholds.MaxModifications):
+		alert = &Alert{Kind: KindModifyBurst, Count: modifications,
+			Reason: fmt.Sprintf("%d files modified within %s", modifications, d.thresholds.Window)}
+	default:
+		return nil, nil
+	}
+
+	alert.UserID = env.UserId
+	alert.DeviceID = env.DeviceId
+	return alert, d.Reset(tx, env.UserId, env.DeviceId)
+}
+
+// Reset forgets the recent changes of a device.
+func (d *Detector) Reset(tx *gorm.DB, userID, deviceID string) error {
+	return tx.Where("user_id = ? AND device_id = ?", userID, deviceID).
+		Delete(&models.DeviceActivity{}).Error
+}
+
+// moveEntropy carries the entropy of a file over to where it was moved.
+func moveEntropy(tx *gorm.DB, userID, from, to string) error {
+	var previous models.FileEntropy
+	if err := tx.Where("user_id = ? AND path = ?", userID, from).Limit(1).Find(&previous).Error; err != nil {
+		return err
+	}
+	if previous.Path == "" {
+		return nil
+	}
+	if err := tx.Where("user_id = ? AND path = ?", userID, from).Delete(&models.FileEntropy{}).Error; err != nil {
+		return err
+	}
+	previous.Path = to
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entropy", "seen_at"}),
+	}).Create(&previous).Error
+}
+
+func over(count, limit int) bool {
+	return limit > 0 && count > limit
+}
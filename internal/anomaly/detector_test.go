@@ -0,0 +1,164 @@
+package anomaly
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "anomaly.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.DeviceActivity{}, &models.FileEntropy{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// start is when the changes in these tests happen, a second apart.
+var start = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func at(second int) string {
+	return start.Add(time.Duration(second) * time.Second).Format(time.RFC3339Nano)
+}
+
+func modified(second int, path string, entropy float64) *proto.EventEnvelope {
+	return &proto.EventEnvelope{UserId: "alice", DeviceId: "laptop", OccurredAt: at(second),
+		Payload: &proto.EventEnvelope_FileModified{FileModified: &proto.FileModified{Path: path, Entropy: entropy}}}
+}
+
+func deleted(second int, path string) *proto.EventEnvelope {
+	return &proto.EventEnvelope{UserId: "alice", DeviceId: "laptop", OccurredAt: at(second),
+		Payload: &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{Path: path}}}
+}
+
+func renamed(second int, from, to string) *proto.EventEnvelope {
+	return &proto.EventEnvelope{UserId: "alice", DeviceId: "laptop", OccurredAt: at(second),
+		Payload: &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{OldPath: from, NewPath: to}}}
+}
+
+func observe(t *testing.T, d *Detector, db *gorm.DB, env *proto.EventEnvelope) *Alert {
+	t.Helper()
+	alert, err := d.Observe(db, env)
+	if err != nil {
+		t.Fatalf("observe: %v", err)
+	}
+	return alert
+}
+
+func TestDeleteBurst(t *testing.T) {
+	db := newTestDB(t)
+	d := NewDetector(Thresholds{Window: 10 * time.Second, MaxDeletions: 3})
+
+	for i := 0; i < 3; i++ {
+		if alert := observe(t, d, db, deleted(i, "file")); alert != nil {
+			t.Fatalf("deletion %d raised %+v", i+1, alert)
+		}
+	}
+	alert := observe(t, d, db, deleted(3, "file"))
+	if alert == nil || alert.Kind != KindDeleteBurst || alert.Count != 4 || alert.DeviceID != "laptop" {
+		t.Fatalf("fourth deletion raised %+v, want a delete burst of 4", alert)
+	}
+
+	// The window starts over after an alert, and older changes fall out
+	// of it.
+	for i := 4; i < 7; i++ {
+		if alert := observe(t, d, db, deleted(i, "file")); alert != nil {
+			t.Fatalf("deletion after the alert raised %+v", alert)
+		}
+	}
+	if alert := observe(t, d, db, deleted(30, "file")); alert != nil {
+		t.Fatalf("deletion after the window raised %+v", alert)
+	}
+}
+
+func TestModifyBurst(t *testing.T) {
+	db := newTestDB(t)
+	d := NewDetector(Thresholds{Window: time.Minute, MaxModifications: 2})
+
+	observe(t, d, db, modified(0, "a", 0))
+	observe(t, d, db, renamed(1, "a", "b"))
+	alert := observe(t, d, db, modified(2, "b", 0))
+	if alert == nil || alert.Kind != KindModifyBurst || alert.Count != 3 {
+		t.Fatalf("third change raised %+v, want a modify burst of 3", alert)
+	}
+}
+
+// Instances sharing the database count a device's changes together, and
+// a restarted instance carries on where the others left off.
+func TestBurstAcrossInstances(t *testing.T) {
+	db := newTestDB(t)
+	thresholds := Thresholds{Window: time.Minute, MaxDeletions: 3}
+	instances := []*Detector{NewDetector(thresholds), NewDetector(thresholds)}
+
+	for i := 0; i < 3; i++ {
+		if alert := observe(t, instances[i%2], db, deleted(i, "file")); alert != nil {
+			t.Fatalf("deletion %d raised %+v", i+1, alert)
+		}
+	}
+	if alert := observe(t, NewDetector(thresholds), db, deleted(3, "file")); alert == nil {
+		t.Fatal("fourth deletion, on a restarted instance, raised no alert")
+	}
+}
+
+func TestEntropyJump(t *testing.T) {
+	db := newTestDB(t)
+	d := NewDetector(Thresholds{Window: time.Minute, MaxEntropyJumps: 1})
+
+	for i, path := range []string{"a.txt", "b.txt"} {
+		observe(t, d, db, modified(i, path, 4.5))
+	}
+	// Files first seen random-looking, and small changes in entropy, are
+	// not jumps.
+	observe(t, d, db, modified(2, "c.bin", 7.9))
+	observe(t, d, db, modified(3, "a.txt", 5.0))
+
+	if alert := observe(t, d, db, modified(4, "a.txt", 7.9)); alert != nil {
+		t.Fatalf("first jump raised %+v", alert)
+	}
+	alert := observe(t, d, db, modified(5, "b.txt", 7.9))
+	if alert == nil || alert.Kind != KindEntropyJump || alert.Count != 2 {
+		t.Fatalf("second jump raised %+v, want an entropy jump of 2", alert)
+	}
+}
+
+// A file encrypted and renamed in one go is compared with the entropy it
+// had under its old name.
+func TestEntropyCarriesOverRenames(t *testing.T) {
+	db := newTestDB(t)
+	d := NewDetector(Thresholds{Window: time.Minute, MaxEntropyJumps: 1})
+
+	observe(t, d, db, modified(0, "a.docx", 4.0))
+	observe(t, d, db, modified(1, "b.docx", 4.0))
+	observe(t, d, db, renamed(2, "a.docx", "a.docx.locked"))
+	observe(t, d, db, renamed(3, "b.docx", "b.docx.locked"))
+	if alert := observe(t, d, db, modified(4, "a.docx.locked", 7.9)); alert != nil {
+		t.Fatalf("first jump raised %+v", alert)
+	}
+	alert := observe(t, d, db, modified(5, "b.docx.locked", 7.9))
+	if alert == nil || alert.Kind != KindEntropyJump {
+		t.Fatalf("second renamed file turning random raised %+v, want an entropy jump", alert)
+	}
+
+	// Deleted files are forgotten.
+	observe(t, d, db, deleted(6, "a.docx.locked"))
+	var remembered []models.FileEntropy
+	if err := db.Order("path").Find(&remembered).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(remembered) != 1 || remembered[0].Path != "b.docx.locked" {
+		t.Fatalf("remembered entropy of %+v, want only b.docx.locked", remembered)
+	}
+}
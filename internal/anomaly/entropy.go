@@ -0,0 +1,49 @@
+package anomaly
+
+import (
+	"io"
+	"math"
+	"os"
+)
+
+// SampleSize is how much of a file is read to estimate its entropy.
+const SampleSize = 64 * 1024
+
+// Entropy returns the Shannon entropy of data in bits per byte, from 0 for
+// constant data to 8 for random or encrypted data.
+func Entropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+
+	entropy := 0.0
+	total := float64(len(data))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// FileEntropy estimates the entropy of a file from its first bytes.
+func FileEntropy(path string) (float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, SampleSize))
+	if err != nil {
+		return 0, err
+	}
+	return Entropy(data), nil
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// AdminService manages user accounts for operators. It is served on the
+// admin listener only.
+type AdminService struct {
+	proto.UnimplementedUserAdminServiceServer
+	db *gorm.DB
+}
+
+func NewAdminService(db *gorm.DB) *AdminService {
+	return &AdminService{db: db}
+}
+
+// SetUserOrganization moves a user into an organization, which makes its
+// retention policy apply to the user's files.
+func (s *AdminService) SetUserOrganization(ctx context.Context, req *proto.SetUserOrganizationRequest) (*proto.SetUserOrganizationResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id is required")
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", req.UserId).
+		Update("org_id", req.OrgId)
+	if result.Error != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update user: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+
+	message := "User added to organization"
+	if req.OrgId == "" {
+		message = "User removed from organization"
+	}
+	return &proto.SetUserOrganizationResponse{
+		Success: true,
+		Message: message,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/retention"
+)
+
+func TestSetUserOrganizationAppliesOrgRetention(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "admin.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.RetentionPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+
+	user := &models.User{Email: "carol@example.com", Username: "carol", Password: "x"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.RetentionPolicy{Scope: models.RetentionScopeOrg, ScopeID: "acme", KeepLast: 3}).Error; err != nil {
+		t.Fatal(err)
+	}
+	file := &models.File{OwnerID: user.ID, Path: "carol/laptop/notes.txt"}
+
+	policy, err := retention.Resolve(db, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy != nil {
+		t.Fatalf("policy %+v applies before the user joined the organization", policy)
+	}
+
+	admin := NewAdminService(db)
+	if _, err := admin.SetUserOrganization(ctx, &proto.SetUserOrganizationRequest{UserId: user.ID, OrgId: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err = retention.Resolve(db, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy == nil || policy.ScopeID != "acme" {
+		t.Fatalf("got policy %+v, want the acme policy", policy)
+	}
+
+	_, err = admin.SetUserOrganization(ctx, &proto.SetUserOrganizationRequest{UserId: "missing", OrgId: "acme"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v, want NotFound", err)
+	}
+}
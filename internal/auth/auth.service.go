@@ -5,12 +5,16 @@ import (
 	"errors"
 
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/e2ee"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/middleware"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthService struct {
@@ -101,3 +105,63 @@ func (s *AuthService) ValidateToken(ctx context.Context, req *proto.ValidateToke
 		UserId: claims.UserID,
 	}, nil
 }
+
+func (s *AuthService) RegisterPublicKey(ctx context.Context, req *proto.RegisterPublicKeyRequest) (*proto.RegisterPublicKeyResponse, error) {
+	if req.Algorithm != e2ee.Algorithm {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported key algorithm: %q", req.Algorithm)
+	}
+	if len(req.PublicKey) != e2ee.KeySize {
+		return nil, status.Errorf(codes.InvalidArgument, "public key must be %d bytes, got %d", e2ee.KeySize, len(req.PublicKey))
+	}
+
+	// Only the user themselves may replace the key files are shared with.
+	claims, err := middleware.ValidateToken(req.Token, s.jwtSecret)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	if claims.UserID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot register a key for another user")
+	}
+
+	var user models.User
+	if err := s.db.First(&user, "id = ?", req.UserId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	key := &models.UserKey{
+		UserID:    user.ID,
+		PublicKey: req.PublicKey,
+		Algorithm: req.Algorithm,
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"public_key", "algorithm", "updated_at"}),
+	}).Create(key).Error; err != nil {
+		return nil, err
+	}
+
+	return &proto.RegisterPublicKeyResponse{
+		Success: true,
+		Message: "Public key registered successfully",
+	}, nil
+}
+
+func (s *AuthService) GetPublicKey(ctx context.Context, req *proto.GetPublicKeyRequest) (*proto.GetPublicKeyResponse, error) {
+	var key models.UserKey
+	if err := s.db.First(&key, "user_id = ?", req.UserId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no public key registered for user")
+		}
+		return nil, err
+	}
+
+	return &proto.GetPublicKeyResponse{
+		UserId:    key.UserID,
+		PublicKey: key.PublicKey,
+		Algorithm: key.Algorithm,
+	}, nil
+}
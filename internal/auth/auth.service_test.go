@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/e2ee"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/middleware"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+)
+
+const testSecret = "test-secret"
+
+func TestRegisterPublicKey(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "auth.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.UserKey{}); err != nil {
+		t.Fatal(err)
+	}
+	service := NewAuthService(db, testSecret)
+
+	alice := &models.User{Email: "alice@example.com", Username: "alice", Password: "x"}
+	bob := &models.User{Email: "bob@example.com", Username: "bob", Password: "x"}
+	for _, user := range []*models.User{alice, bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	aliceToken, _ := middleware.GenerateToken(alice.ID, testSecret)
+	bobToken, _ := middleware.GenerateToken(bob.ID, testSecret)
+	key := bytes.Repeat([]byte{7}, e2ee.KeySize)
+
+	tests := []struct {
+		name string
+		req  *proto.RegisterPublicKeyRequest
+		code codes.Code
+	}{
+		{"short key", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: key[:16], Algorithm: e2ee.Algorithm, Token: aliceToken}, codes.InvalidArgument},
+		{"long key", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: append(key, 0), Algorithm: e2ee.Algorithm, Token: aliceToken}, codes.InvalidArgument},
+		{"missing key", &proto.RegisterPublicKeyRequest{UserId: alice.ID, Algorithm: e2ee.Algorithm, Token: aliceToken}, codes.InvalidArgument},
+		{"unknown algorithm", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: key, Algorithm: "rsa", Token: aliceToken}, codes.InvalidArgument},
+		{"no token", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: key, Algorithm: e2ee.Algorithm}, codes.Unauthenticated},
+		{"another user", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: key, Algorithm: e2ee.Algorithm, Token: bobToken}, codes.PermissionDenied},
+		{"own key", &proto.RegisterPublicKeyRequest{UserId: alice.ID, PublicKey: key, Algorithm: e2ee.Algorithm, Token: aliceToken}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RegisterPublicKey(context.Background(), tt.req)
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("got %v (%v), want %v", got, err, tt.code)
+			}
+		})
+	}
+
+	resp, err := service.GetPublicKey(context.Background(), &proto.GetPublicKeyRequest{UserId: alice.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(resp.PublicKey, key) {
+		t.Fatalf("registered key %x, want %x", resp.PublicKey, key)
+	}
+}
@@ -0,0 +1,194 @@
+package chunker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+const (
+	DefaultMinSize = 16 * 1024
+	DefaultAvgSize = 64 * 1024
+	DefaultMaxSize = 256 * 1024
+)
+
+// gear is the rolling hash table shared by every client and server. It is
+// derived from a fixed seed so that identical content always produces
+// identical cut points, no matter where it is chunked.
+var gear = func() [256]uint64 {
+	var table [256]uint64
+	seed := uint64(0x9e3779b97f4a7c15)
+	for i := range table {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+type Chunk struct {
+	Offset int64
+	Data   []byte
+	Hash   string
+}
+
+// Chunker splits content into content-defined chunks using the FastCDC
+// algorithm with normalized chunking.
+type Chunker struct {
+	minSize int
+	avgSize int
+	maxSize int
+	maskS   uint64
+	maskL   uint64
+}
+
+func New(minSize, avgSize, maxSize int) *Chunker {
+	bits := 0
+	for (1 << bits) < avgSize {
+		bits++
+	}
+
+	return &Chunker{
+		minSize: minSize,
+		avgSize: avgSize,
+		maxSize: maxSize,
+		maskS:   mask(bits + 1),
+		maskL:   mask(bits - 1),
+	}
+}
+
+func NewDefault() *Chunker {
+	return New(DefaultMinSize, DefaultAvgSize, DefaultMaxSize)
+}
+
+// mask spreads the given number of one bits evenly over the upper 48 bits,
+// as recommended by the FastCDC paper.
+func mask(bits int) uint64 {
+	if bits < 1 {
+		bits = 1
+	}
+	if bits > 48 {
+		bits = 48
+	}
+
+	var m uint64
+	step := 48 / bits
+	for i := 0; i < bits; i++ {
+		m |= 1 << (63 - uint(i*step))
+	}
+	return m
+}
+
+// Cut returns the length of the next chunk at the start of data.
+func (c *Chunker) Cut(data []byte) int {
+	n := len(data)
+	if n <= c.minSize {
+		return n
+	}
+	if n > c.maxSize {
+		n = c.maxSize
+	}
+
+	normal := c.avgSize
+	if n < normal {
+		normal = n
+	}
+
+	var fp uint64
+	i := c.minSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// Split divides data into chunks. The returned chunks reference data
+// instead of copying it.
+func (c *Chunker) Split(data []byte) []Chunk {
+	var chunks []Chunk
+	offset := 0
+	for offset < len(data) {
+		size := c.Cut(data[offset:])
+		content := data[offset : offset+size]
+		chunks = append(chunks, Chunk{
+			Offset: int64(offset),
+			Data:   content,
+			Hash:   Hash(content),
+		})
+		offset += size
+	}
+	return chunks
+}
+
+// Reader splits a stream into the chunks Split would find in the whole
+// content, holding no more than one maximum-size chunk in memory.
+type Reader struct {
+	chunker    *Chunker
+	r          io.Reader
+	buf        []byte
+	start, end int
+	offset     int64
+	eof        bool
+}
+
+func (c *Chunker) NewReader(r io.Reader) *Reader {
+	return &Reader{chunker: c, r: r, buf: make([]byte, c.maxSize)}
+}
+
+// Next returns the next chunk, or io.EOF after the last one. The chunk's
+// data is only valid until the next call.
+func (r *Reader) Next() (Chunk, error) {
+	if err := r.fill(); err != nil {
+		return Chunk{}, err
+	}
+	if r.start == r.end {
+		return Chunk{}, io.EOF
+	}
+
+	content := r.buf[r.start:r.end]
+	content = content[:r.chunker.Cut(content)]
+	chunk := Chunk{
+		Offset: r.offset,
+		Data:   content,
+		Hash:   Hash(content),
+	}
+	r.start += len(content)
+	r.offset += int64(len(content))
+	return chunk, nil
+}
+
+// fill buffers a maximum-size chunk, or the rest of the stream, since Cut
+// looks no further.
+func (r *Reader) fill() error {
+	if r.eof || r.end-r.start == len(r.buf) {
+		return nil
+	}
+
+	r.end = copy(r.buf, r.buf[r.start:r.end])
+	r.start = 0
+	n, err := io.ReadFull(r.r, r.buf[r.end:])
+	r.end += n
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r.eof = true
+		return nil
+	}
+	return err
+}
+
+// Hash returns the content address used to identify a chunk.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
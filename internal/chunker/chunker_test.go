@@ -0,0 +1,57 @@
+package chunker
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderMatchesSplit(t *testing.T) {
+	c := New(64, 256, 1024)
+	rng := rand.New(rand.NewSource(1))
+
+	for _, size := range []int{0, 1, 64, 65, 1023, 1024, 1025, 10000, 100000} {
+		data := make([]byte, size)
+		rng.Read(data)
+		want := c.Split(data)
+
+		readers := map[string]io.Reader{
+			"whole":    bytes.NewReader(data),
+			"one byte": iotest.OneByteReader(bytes.NewReader(data)),
+			"half":     iotest.HalfReader(bytes.NewReader(data)),
+		}
+		for name, r := range readers {
+			reader := c.NewReader(r)
+			var got []Chunk
+			for {
+				chunk, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%d bytes, %s: %v", size, name, err)
+				}
+				chunk.Data = append([]byte(nil), chunk.Data...)
+				got = append(got, chunk)
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("%d bytes, %s: %d chunks, want %d", size, name, len(got), len(want))
+			}
+			for i := range want {
+				if got[i].Offset != want[i].Offset || got[i].Hash != want[i].Hash || !bytes.Equal(got[i].Data, want[i].Data) {
+					t.Fatalf("%d bytes, %s: chunk %d differs", size, name, i)
+				}
+			}
+		}
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	reader := NewDefault().NewReader(iotest.ErrReader(io.ErrClosedPipe))
+	if _, err := reader.Next(); err != io.ErrClosedPipe {
+		t.Fatalf("got %v, want %v", err, io.ErrClosedPipe)
+	}
+}
@@ -0,0 +1,411 @@
+package chunkstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunker"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store keeps file content as content-defined chunks so that identical
+// chunks are stored once and shared by every version and file.
+type Store struct {
+	db      *gorm.DB
+	storage utils.BlobStore
+	chunker *chunker.Chunker
+}
+
+func New(db *gorm.DB, storage utils.BlobStore) *Store {
+	return &Store{
+		db:      db,
+		storage: storage,
+		chunker: chunker.NewDefault(),
+	}
+}
+
+// WithTx returns a store that keeps its rows in the given transaction, so
+// that they go with it if it rolls back. Blobs are stored right away; a
+// rolled back chunk's blob is named by no row.
+func (s *Store) WithTx(tx *gorm.DB) *Store {
+	return &Store{db: tx, storage: s.storage, chunker: s.chunker}
+}
+
+// ChunkKey returns a new blob key for a chunk. Every write of a chunk goes
+// to its own blob, named on its row, so deleting a collected chunk's blob
+// can never remove one that a later upload of the same content wrote.
+func ChunkKey(hash string) string {
+	return fmt.Sprintf("chunks/%s/%s/%s", hash[:2], hash, uuid.New().String())
+}
+
+// Missing returns the hashes the user has not stored yet, in request
+// order. Chunks stored only by other users count as missing, so their
+// content has to be uploaded again to prove possession.
+func (s *Store) Missing(ctx context.Context, userID string, hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	var existing []string
+	if err := s.db.WithContext(ctx).Model(&models.ChunkOwner{}).
+		Where("user_id = ? AND chunk_hash IN ?", userID, hashes).
+		Pluck("chunk_hash", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(existing))
+	for _, hash := range existing {
+		present[hash] = true
+	}
+
+	var missing []string
+	for _, hash := range hashes {
+		if !present[hash] {
+			missing = append(missing, hash)
+			present[hash] = true
+		}
+	}
+	return missing, nil
+}
+
+// Reference marks an existing chunk owned by the user as used by an upload
+// in progress, which protects it from garbage collection until the upload
+// has committed. It reports false when the user has no such chunk.
+func (s *Store) Reference(ctx context.Context, userID, hash string) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.Chunk{}).
+		Where("hash = ?", hash).
+		Where("EXISTS (SELECT 1 FROM chunk_owners WHERE chunk_owners.chunk_hash = chunks.hash AND chunk_owners.user_id = ?)", userID).
+		Update("last_used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// PutChunk stores a single chunk for a user unless it is already present,
+// encoding it with the given codec. The object is uploaded before its row
+// is inserted so a row never points at a missing blob, and the row always
+// names the blob and codec its writer stored.
+func (s *Store) PutChunk(ctx context.Context, userID string, data []byte, codec string) (*models.Chunk, error) {
+	hash := chunker.Hash(data)
+
+	var existing models.Chunk
+	err := s.db.WithContext(ctx).First(&existing, "hash = ?", hash).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err == nil {
+		touched, err := s.touch(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if touched {
+			return &existing, s.own(ctx, userID, hash)
+		}
+		// Garbage collection removed the chunk after it was looked up, so
+		// it is stored again.
+	}
+
+	encoded, err := utils.Compress(codec, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the raw bytes when compression does not pay for itself.
+	if codec != utils.CodecIdentity && len(encoded) >= len(data) {
+		codec, encoded = utils.CodecIdentity, data
+	}
+
+	chunk := &models.Chunk{
+		Hash:       hash,
+		Size:       int64(len(data)),
+		StoredSize: int64(len(encoded)),
+		Codec:      codec,
+		S3Key:      ChunkKey(hash),
+		LastUsedAt: time.Now(),
+	}
+
+	if err := s.storage.Put(ctx, chunk.S3Key, bytes.NewReader(encoded)); err != nil {
+		return nil, fmt.Errorf("failed to upload chunk %s: %v", hash, err)
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(chunk)
+	if result.Error != nil {
+		s.storage.Delete(ctx, chunk.S3Key)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// A concurrent upload stored the chunk first, with its own blob
+		// and perhaps another codec. Nothing names this blob, so it goes
+		// and the chunk is taken as stored.
+		s.storage.Delete(ctx, chunk.S3Key)
+		return s.PutChunk(ctx, userID, data, codec)
+	}
+
+	return chunk, s.own(ctx, userID, hash)
+}
+
+// touch protects a chunk from garbage collection for the grace period. It
+// reports false when the chunk does not exist.
+func (s *Store) touch(ctx context.Context, hash string) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.Chunk{}).
+		Where("hash = ?", hash).
+		Update("last_used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// own records that the user has uploaded the content of a chunk, so may
+// refer to it from now on.
+func (s *Store) own(ctx context.Context, userID, hash string) error {
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ChunkOwner{ChunkHash: hash, UserID: userID}).Error
+}
+
+// Write splits data into chunks, stores the ones that are missing for the
+// user and returns the manifest for a version. VersionID is left for the
+// caller.
+func (s *Store) Write(ctx context.Context, userID string, data []byte, codec string) ([]models.VersionChunk, error) {
+	chunks := s.chunker.Split(data)
+	manifest := make([]models.VersionChunk, 0, len(chunks))
+
+	for i, c := range chunks {
+		if _, err := s.PutChunk(ctx, userID, c.Data, codec); err != nil {
+			return nil, err
+		}
+
+		manifest = append(manifest, models.VersionChunk{
+			Index:     i,
+			ChunkHash: c.Hash,
+			Offset:    c.Offset,
+			Size:      int64(len(c.Data)),
+		})
+	}
+
+	return manifest, nil
+}
+
+// StoredSize returns the number of bytes a manifest occupies in storage.
+func (s *Store) StoredSize(ctx context.Context, manifest []models.VersionChunk) (int64, error) {
+	hashes := make([]string, len(manifest))
+	for i, vc := range manifest {
+		hashes[i] = vc.ChunkHash
+	}
+
+	var chunks []models.Chunk
+	if err := s.db.WithContext(ctx).Where("hash IN ?", hashes).Find(&chunks).Error; err != nil {
+		return 0, err
+	}
+
+	sizes := make(map[string]int64, len(chunks))
+	for _, c := range chunks {
+		sizes[c.Hash] = c.StoredSize
+	}
+
+	total := int64(0)
+	for _, vc := range manifest {
+		total += sizes[vc.ChunkHash]
+	}
+	return total, nil
+}
+
+// ReadChunk returns the decoded content of a stored chunk.
+func (s *Store) ReadChunk(ctx context.Context, hash string) ([]byte, error) {
+	var chunk models.Chunk
+	if err := s.db.WithContext(ctx).First(&chunk, "hash = ?", hash).Error; err != nil {
+		return nil, err
+	}
+
+	reader, err := s.openChunk(ctx, &chunk, true)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (s *Store) openChunk(ctx context.Context, chunk *models.Chunk, decode bool) (io.ReadCloser, error) {
+	reader, err := s.storage.Get(ctx, chunk.S3Key, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download chunk %s: %v", chunk.Hash, err)
+	}
+	if !decode {
+		return reader, nil
+	}
+	return utils.NewDecompressReader(chunk.Codec, reader)
+}
+
+func (s *Store) manifest(ctx context.Context, versionID string) ([]models.VersionChunk, error) {
+	var manifest []models.VersionChunk
+	err := s.db.WithContext(ctx).
+		Preload("Chunk").
+		Where("version_id = ?", versionID).
+		// INDEX is reserved in some dialects, so the column is quoted.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "index"}}).
+		Find(&manifest).Error
+	return manifest, err
+}
+
+// Open returns the content of a version, reassembled from its chunk
+// manifest. Versions stored before chunking fall back to their S3 object.
+func (s *Store) Open(ctx context.Context, version *models.FileVersion) (io.ReadCloser, error) {
+	manifest, err := s.manifest(ctx, version.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(manifest) == 0 {
+		reader, err := s.storage.Get(ctx, version.S3Key, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		return utils.NewDecompressReader(version.Codec, reader)
+	}
+
+	return &manifestReader{ctx: ctx, store: s, manifest: manifest, decode: true}, nil
+}
+
+// OpenEncoded returns the stored bytes of a version without decoding them
+// when every object of the version uses the given codec. It reports false
+// when the content has to be decoded instead.
+func (s *Store) OpenEncoded(ctx context.Context, version *models.FileVersion, codec string) (io.ReadCloser, bool, error) {
+	manifest, err := s.manifest(ctx, version.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(manifest) == 0 {
+		if version.Codec != codec {
+			return nil, false, nil
+		}
+		reader, err := s.storage.Get(ctx, version.S3Key, 0, -1)
+		return reader, err == nil, err
+	}
+
+	for _, vc := range manifest {
+		if vc.Chunk.Codec != codec {
+			return nil, false, nil
+		}
+	}
+
+	return &manifestReader{ctx: ctx, store: s, manifest: manifest}, true, nil
+}
+
+// DeleteUnreferenced removes the given chunks when no version refers to
+// them any more and they have not been referenced by an upload within the
+// grace period. It returns the number of chunks deleted.
+func (s *Store) DeleteUnreferenced(ctx context.Context, hashes []string, grace time.Duration) (int, error) {
+	deleted := 0
+	cutoff := time.Now().Add(-grace)
+
+	for _, hash := range hashes {
+		var chunk models.Chunk
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Returning{}).
+				Where("hash = ? AND last_used_at < ?", hash, cutoff).
+				Where("NOT EXISTS (SELECT 1 FROM version_chunks WHERE version_chunks.chunk_hash = chunks.hash)").
+				Delete(&chunk).Error; err != nil {
+				return err
+			}
+			if chunk.S3Key == "" {
+				return nil
+			}
+			// An upload storing the chunk again waits on the deleted row
+			// until this commits, so only the old owners are removed.
+			return tx.Where("chunk_hash = ?", hash).Delete(&models.ChunkOwner{}).Error
+		})
+		if err != nil {
+			return deleted, err
+		}
+		if chunk.S3Key == "" {
+			continue
+		}
+
+		// Nothing else names this blob: a chunk stored again gets a blob of
+		// its own. A failure here only leaves an orphaned blob behind.
+		if err := s.storage.Delete(ctx, chunk.S3Key); err != nil {
+			return deleted, fmt.Errorf("failed to delete chunk %s: %v", hash, err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// ReleaseObject deletes a whole-object blob from before chunking once no
+// version points at it.
+func (s *Store) ReleaseObject(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.FileVersion{}).
+		Where("s3_key = ?", key).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return s.storage.Delete(ctx, key)
+}
+
+// manifestReader streams the chunks of a manifest one after another,
+// downloading each chunk only when the previous one has been consumed.
+type manifestReader struct {
+	ctx      context.Context
+	store    *Store
+	manifest []models.VersionChunk
+	decode   bool
+	current  io.ReadCloser
+}
+
+func (r *manifestReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.manifest) == 0 {
+				return 0, io.EOF
+			}
+
+			reader, err := r.store.openChunk(r.ctx, &r.manifest[0].Chunk, r.decode)
+			if err != nil {
+				return 0, err
+			}
+			r.current = reader
+			r.manifest = r.manifest[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *manifestReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
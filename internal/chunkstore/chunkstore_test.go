@@ -0,0 +1,177 @@
+package chunkstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunker"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+func newTestStore(t *testing.T) (*Store, utils.BlobStore) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "chunks.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Chunk{}, &models.ChunkOwner{}, &models.VersionChunk{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	storage := utils.NewMemoryBlobStore()
+	return New(db, storage), storage
+}
+
+func TestDedupIsScopedToUser(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+	data := []byte("content only alice has uploaded")
+	hash := chunker.Hash(data)
+
+	if _, err := store.PutChunk(ctx, "alice", data, utils.CodecIdentity); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		user    string
+		missing bool
+	}{
+		{"alice", false},
+		{"bob", true},
+	}
+	for _, tt := range tests {
+		missing, err := store.Missing(ctx, tt.user, []string{hash, hash})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(missing) == 1; got != tt.missing {
+			t.Errorf("%s: missing %v, want missing=%v", tt.user, missing, tt.missing)
+		}
+
+		referenced, err := store.Reference(ctx, tt.user, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if referenced == tt.missing {
+			t.Errorf("%s: Reference = %v, want %v", tt.user, referenced, !tt.missing)
+		}
+	}
+
+	// Uploading the content proves possession, without storing it twice.
+	if _, err := store.PutChunk(ctx, "bob", data, utils.CodecIdentity); err != nil {
+		t.Fatal(err)
+	}
+	if referenced, err := store.Reference(ctx, "bob", hash); err != nil || !referenced {
+		t.Fatalf("Reference after upload = %v, %v", referenced, err)
+	}
+	var chunks int64
+	store.db.Model(&models.Chunk{}).Count(&chunks)
+	if chunks != 1 {
+		t.Fatalf("stored %d chunks, want 1", chunks)
+	}
+}
+
+// A chunk stored again after collection gets a blob of its own, so the
+// collector deleting the old blob late cannot take the new content.
+func TestChunkStoredAgainAfterCollection(t *testing.T) {
+	ctx := context.Background()
+	store, storage := newTestStore(t)
+	data := []byte("content collected and then uploaded again")
+	hash := chunker.Hash(data)
+
+	old, err := store.PutChunk(ctx, "alice", data, utils.CodecIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.db.Model(&models.Chunk{}).Where("hash = ?", hash).Update("last_used_at", time.Now().Add(-time.Hour))
+	if deleted, err := store.DeleteUnreferenced(ctx, []string{hash}, time.Minute); err != nil || deleted != 1 {
+		t.Fatalf("DeleteUnreferenced = %d, %v, want 1", deleted, err)
+	}
+	if _, err := storage.Get(ctx, old.S3Key, 0, -1); err == nil {
+		t.Fatalf("blob %s of the collected chunk still stored", old.S3Key)
+	}
+
+	stored, err := store.PutChunk(ctx, "alice", data, utils.CodecIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.S3Key == old.S3Key {
+		t.Fatalf("chunk stored again under its old blob %s", old.S3Key)
+	}
+	// The collector's delete of the old blob, had it come late.
+	storage.Delete(ctx, old.S3Key)
+
+	content, err := store.ReadChunk(ctx, hash)
+	if err != nil || string(content) != string(data) {
+		t.Fatalf("ReadChunk = %q, %v", content, err)
+	}
+	if missing, err := store.Missing(ctx, "alice", []string{hash}); err != nil || len(missing) != 0 {
+		t.Fatalf("Missing = %v, %v after storing again", missing, err)
+	}
+}
+
+// racingStore runs a hook once, after the first blob is put, as a
+// concurrent writer would between an upload's Put and its insert.
+type racingStore struct {
+	utils.BlobStore
+	race func()
+}
+
+func (r *racingStore) Put(ctx context.Context, key string, reader io.Reader) error {
+	if err := r.BlobStore.Put(ctx, key, reader); err != nil {
+		return err
+	}
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return nil
+}
+
+// Of two uploads of a chunk with different codecs, the row names the
+// winner's blob and codec, and the loser's blob is dropped.
+func TestConcurrentPutsKeepRowAndBlobInStep(t *testing.T) {
+	ctx := context.Background()
+	store, storage := newTestStore(t)
+	racing := &racingStore{BlobStore: storage}
+	store.storage = racing
+	data := bytes.Repeat([]byte("compressible "), 1000)
+
+	racing.race = func() {
+		if _, err := store.PutChunk(ctx, "bob", data, utils.CodecIdentity); err != nil {
+			t.Errorf("concurrent PutChunk: %v", err)
+		}
+	}
+	chunk, err := store.PutChunk(ctx, "alice", data, utils.CodecZstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk.Codec != utils.CodecIdentity {
+		t.Errorf("PutChunk returned codec %s, want the stored row's %s", chunk.Codec, utils.CodecIdentity)
+	}
+
+	content, err := store.ReadChunk(ctx, chunk.Hash)
+	if err != nil || !bytes.Equal(content, data) {
+		t.Fatalf("ReadChunk = %d bytes, %v, want the uploaded content", len(content), err)
+	}
+	blobs, err := storage.List(ctx, "chunks/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 || blobs[0].Key != chunk.S3Key {
+		t.Fatalf("blobs %+v, want only %s", blobs, chunk.S3Key)
+	}
+	if missing, err := store.Missing(ctx, "alice", []string{chunk.Hash}); err != nil || len(missing) != 0 {
+		t.Fatalf("Missing = %v, %v for the losing uploader", missing, err)
+	}
+}
@@ -0,0 +1,108 @@
+// Package e2ee contains the client-side primitives for end-to-end
+// encrypted files. None of it runs on the servers, which only store the
+// ciphertext and wrapped keys produced here.
+package e2ee
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Algorithm identifies the key pair type registered with AuthService.
+const Algorithm = "x25519-xsalsa20-poly1305"
+
+const (
+	KeySize   = 32
+	nonceSize = 24
+)
+
+var ErrDecrypt = errors.New("e2ee: decryption failed")
+
+type KeyPair struct {
+	PublicKey  *[KeySize]byte
+	PrivateKey *[KeySize]byte
+}
+
+func GenerateKeyPair() (*KeyPair, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %v", err)
+	}
+	return &KeyPair{PublicKey: public, PrivateKey: private}, nil
+}
+
+// NewDataKey returns a random key for encrypting the content and metadata
+// of a single file.
+func NewDataKey() (*[KeySize]byte, error) {
+	var key [KeySize]byte
+	if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+	return &key, nil
+}
+
+// WrapKey encrypts a data key for the holder of the given public key.
+func WrapKey(dataKey *[KeySize]byte, recipient []byte) ([]byte, error) {
+	if len(recipient) != KeySize {
+		return nil, fmt.Errorf("invalid public key length: %d", len(recipient))
+	}
+
+	var publicKey [KeySize]byte
+	copy(publicKey[:], recipient)
+	return box.SealAnonymous(nil, dataKey[:], &publicKey, rand.Reader)
+}
+
+// UnwrapKey recovers a data key wrapped for the given key pair.
+func UnwrapKey(wrapped []byte, keys *KeyPair) (*[KeySize]byte, error) {
+	plain, ok := box.OpenAnonymous(nil, wrapped, keys.PublicKey, keys.PrivateKey)
+	if !ok || len(plain) != KeySize {
+		return nil, ErrDecrypt
+	}
+
+	var key [KeySize]byte
+	copy(key[:], plain)
+	return &key, nil
+}
+
+// Seal encrypts data with a data key. The random nonce is prepended to the
+// returned ciphertext.
+func Seal(dataKey *[KeySize]byte, data []byte) ([]byte, error) {
+	var nonce [nonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return secretbox.Seal(nonce[:], data, &nonce, dataKey), nil
+}
+
+func Open(dataKey *[KeySize]byte, sealed []byte) ([]byte, error) {
+	if len(sealed) < nonceSize+secretbox.Overhead {
+		return nil, ErrDecrypt
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], sealed[:nonceSize])
+
+	plain, ok := secretbox.Open(nil, sealed[nonceSize:], &nonce, dataKey)
+	if !ok {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// EncryptName encrypts a file name for the encrypted_name metadata field.
+func EncryptName(dataKey *[KeySize]byte, name string) ([]byte, error) {
+	return Seal(dataKey, []byte(name))
+}
+
+func DecryptName(dataKey *[KeySize]byte, encrypted []byte) (string, error) {
+	plain, err := Open(dataKey, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,122 @@
+package e2ee
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealRoundTrip(t *testing.T) {
+	key, err := NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{nil, []byte("a"), bytes.Repeat([]byte("file content "), 1000)} {
+		sealed, err := Seal(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) > 8 && bytes.Contains(sealed, data) {
+			t.Fatalf("sealed data contains the plaintext")
+		}
+		plain, err := Open(key, sealed)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if !bytes.Equal(plain, data) {
+			t.Fatalf("Open = %q, want %q", plain, data)
+		}
+	}
+
+	// Nonces are random, so sealing twice never gives the same bytes.
+	a, _ := Seal(key, []byte("same"))
+	b, _ := Seal(key, []byte("same"))
+	if bytes.Equal(a, b) {
+		t.Fatal("sealing twice gave identical ciphertexts")
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	key, _ := NewDataKey()
+	other, _ := NewDataKey()
+	sealed, err := Seal(key, []byte("quarterly numbers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flip := func(i int) []byte {
+		tampered := append([]byte(nil), sealed...)
+		tampered[i] ^= 1
+		return tampered
+	}
+	tests := []struct {
+		name   string
+		key    *[KeySize]byte
+		sealed []byte
+	}{
+		{"flipped nonce", key, flip(0)},
+		{"flipped tag", key, flip(nonceSize)},
+		{"flipped ciphertext", key, flip(len(sealed) - 1)},
+		{"truncated", key, sealed[:len(sealed)-1]},
+		{"too short", key, sealed[:nonceSize]},
+		{"wrong key", other, sealed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Open(tt.key, tt.sealed); !errors.Is(err, ErrDecrypt) {
+				t.Fatalf("Open = %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestWrapKeyRoundTrip(t *testing.T) {
+	recipient, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, _ := GenerateKeyPair()
+	key, _ := NewDataKey()
+
+	wrapped, err := WrapKey(key, recipient.PublicKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := UnwrapKey(wrapped, recipient)
+	if err != nil {
+		t.Fatalf("UnwrapKey: %v", err)
+	}
+	if *unwrapped != *key {
+		t.Fatal("unwrapped key differs from the data key")
+	}
+
+	if _, err := UnwrapKey(wrapped, stranger); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("UnwrapKey for another key pair = %v, want ErrDecrypt", err)
+	}
+	tampered := append([]byte(nil), wrapped...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := UnwrapKey(tampered, recipient); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("UnwrapKey of a tampered key = %v, want ErrDecrypt", err)
+	}
+	if _, err := WrapKey(key, recipient.PublicKey[:16]); err == nil {
+		t.Error("WrapKey accepted a short public key")
+	}
+}
+
+func TestNameRoundTrip(t *testing.T) {
+	key, _ := NewDataKey()
+	encrypted, err := EncryptName(key, "tax return 2025.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := DecryptName(key, encrypted)
+	if err != nil || name != "tax return 2025.pdf" {
+		t.Fatalf("DecryptName = %q, %v", name, err)
+	}
+
+	encrypted[len(encrypted)-1] ^= 1
+	if _, err := DecryptName(key, encrypted); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("DecryptName of a tampered name = %v, want ErrDecrypt", err)
+	}
+}
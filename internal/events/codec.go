@@ -0,0 +1,234 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// SchemaVersion is the newest schema version this build reads and writes.
+const SchemaVersion uint32 = 2
+
+// Headers that tell consumers how an event is encoded.
+const (
+	SchemaVersionHeader = "Schema-Version"
+	ContentTypeHeader   = "Content-Type"
+
+	jsonContentType     = "application/json"
+	protobufContentType = "application/x-protobuf"
+)
+
+// writeVersion is the schema version producers write.
+var writeVersion = SchemaVersion
+
+// SetWriteVersion makes producers write an older schema version, so that
+// consumers still on it keep working during a rolling deployment. Events
+// the older version cannot express are still written at the current one.
+func SetWriteVersion(version int) error {
+	if version < 1 || uint32(version) > SchemaVersion {
+		return fmt.Errorf("unsupported event schema version %d, want 1 to %d", version, SchemaVersion)
+	}
+	writeVersion = uint32(version)
+	return nil
+}
+
+// Encode serializes an envelope for the bus and returns the headers that
+// describe its encoding.
+func Encode(env *proto.EventEnvelope) ([]byte, map[string]string, error) {
+	if writeVersion == 1 {
+		if legacy, ok := downcastV1(env); ok {
+			data, err := json.Marshal(legacy)
+			if err != nil {
+				return nil, nil, err
+			}
+			return data, map[string]string{
+				SchemaVersionHeader: "1",
+				ContentTypeHeader:   jsonContentType,
+			}, nil
+		}
+	}
+
+	data, err := Marshal(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, map[string]string{
+		SchemaVersionHeader: strconv.FormatUint(uint64(SchemaVersion), 10),
+		ContentTypeHeader:   protobufContentType,
+	}, nil
+}
+
+// Marshal serializes an envelope at the current schema version, for
+// storage outside the bus. Unmarshal reads it back, like Decode.
+func Marshal(env *proto.EventEnvelope) ([]byte, error) {
+	env.SchemaVersion = SchemaVersion
+	return protobuf.Marshal(env)
+}
+
+// Unmarshal decodes an event stored without headers.
+func Unmarshal(data []byte) (*proto.EventEnvelope, error) {
+	return Decode(data, nil)
+}
+
+// upcasters turn an event of each old schema version into the encoding of
+// the next one.
+var upcasters = map[uint32]func(data []byte) ([]byte, error){
+	1: upcastV1,
+}
+
+// Decode reads an event of any supported schema version and upcasts it to
+// the current one. Without a version header, a JSON document is taken for
+// version 1 and anything else for the current version. Newer versions are
+// read as far as this build understands them; an event whose payload it
+// does not know is an error.
+func Decode(data []byte, headers map[string]string) (*proto.EventEnvelope, error) {
+	version := SchemaVersion
+	if value, ok := headers[SchemaVersionHeader]; ok {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || parsed == 0 {
+			return nil, fmt.Errorf("invalid schema version %q", value)
+		}
+		version = uint32(parsed)
+	} else if len(data) > 0 && data[0] == '{' {
+		version = 1
+	}
+
+	for ; version < SchemaVersion; version++ {
+		upcast, ok := upcasters[version]
+		if !ok {
+			return nil, fmt.Errorf("no upcaster for schema version %d", version)
+		}
+		var err error
+		if data, err = upcast(data); err != nil {
+			return nil, fmt.Errorf("failed to upcast schema version %d: %v", version, err)
+		}
+	}
+
+	env := &proto.EventEnvelope{}
+	if err := protobuf.Unmarshal(data, env); err != nil {
+		return nil, err
+	}
+	if env.Payload == nil {
+		return nil, fmt.Errorf("event %s of schema version %d has no payload this build understands", env.EventId, env.SchemaVersion)
+	}
+	env.SchemaVersion = SchemaVersion
+	return env, nil
+}
+
+// legacyChange is schema version 1: a JSON document with a string change
+// type.
+type legacyChange struct {
+	EventID    string    `json:"event_id,omitempty"`
+	FileID     string    `json:"file_id"`
+	FilePath   string    `json:"file_path"`
+	ChangeType string    `json:"change_type"`
+	Timestamp  time.Time `json:"timestamp"`
+	DeviceID   string    `json:"device_id"`
+	VersionID  string    `json:"version_id"`
+	UserID     string    `json:"user_id"`
+	Entropy    float64   `json:"entropy,omitempty"`
+	AlertID    string    `json:"alert_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Confirmed  bool      `json:"confirmed,omitempty"`
+}
+
+// upcastV1 turns a version 1 document into an envelope. Version 1 had no
+// tenant, causation or correlation, so the tenant is the user and the
+// event correlates only with itself.
+func upcastV1(data []byte) ([]byte, error) {
+	var legacy legacyChange
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	env := &proto.EventEnvelope{
+		EventId:       legacy.EventID,
+		SchemaVersion: 2,
+		TenantId:      legacy.UserID,
+		CorrelationId: legacy.EventID,
+		UserId:        legacy.UserID,
+		DeviceId:      legacy.DeviceID,
+		Confirmed:     legacy.Confirmed,
+	}
+	if !legacy.Timestamp.IsZero() {
+		env.OccurredAt = legacy.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+
+	switch legacy.ChangeType {
+	case Created, Modified, Deleted, Renamed:
+		setFileChange(env, legacy.ChangeType, legacy.FileID, legacy.FilePath, legacy.VersionID, legacy.Entropy)
+	case Anomaly:
+		env.Payload = &proto.EventEnvelope_DeviceAnomaly{DeviceAnomaly: &proto.DeviceAnomaly{
+			AlertId: legacy.AlertID,
+			Reason:  legacy.Reason,
+		}}
+	default:
+		return nil, fmt.Errorf("unknown change type %q", legacy.ChangeType)
+	}
+
+	return protobuf.Marshal(env)
+}
+
+// downcastV1 returns the version 1 form of an envelope, if it has one.
+// Events that would not upcast back to the same envelope have none: those
+// of another tenant than the user, caused by or correlated with another
+// event, renamed to a new path, or carrying an anomaly kind.
+func downcastV1(env *proto.EventEnvelope) (*legacyChange, bool) {
+	if env.TenantId != env.UserId || env.CausationId != "" || env.CorrelationId != env.EventId {
+		return nil, false
+	}
+
+	var timestamp time.Time
+	if env.OccurredAt != "" {
+		var err error
+		timestamp, err = time.Parse(time.RFC3339Nano, env.OccurredAt)
+		if err != nil || timestamp.UTC().Format(time.RFC3339Nano) != env.OccurredAt {
+			return nil, false
+		}
+	}
+
+	legacy := &legacyChange{
+		EventID:   env.EventId,
+		Timestamp: timestamp,
+		DeviceID:  env.DeviceId,
+		UserID:    env.UserId,
+		Confirmed: env.Confirmed,
+	}
+
+	if anomaly := env.GetDeviceAnomaly(); anomaly != nil {
+		if anomaly.Kind != "" {
+			return nil, false
+		}
+		legacy.ChangeType = Anomaly
+		legacy.AlertID = anomaly.AlertId
+		legacy.Reason = anomaly.Reason
+		return legacy, true
+	}
+
+	change := Describe(env)
+	switch change.Type {
+	case Created, Modified, Deleted, Renamed:
+	default:
+		return nil, false
+	}
+	if renamed := env.GetFileRenamed(); renamed != nil {
+		// Version 1 renames carried only the path the file was renamed
+		// from.
+		if renamed.NewPath != "" {
+			return nil, false
+		}
+		change.Path = renamed.OldPath
+	}
+
+	legacy.ChangeType = change.Type
+	legacy.FileID = change.FileID
+	legacy.FilePath = change.Path
+	legacy.VersionID = change.VersionID
+	legacy.Entropy = change.Entropy
+	return legacy, true
+}
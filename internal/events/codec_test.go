@@ -0,0 +1,122 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func envelope(payload func(env *proto.EventEnvelope)) *proto.EventEnvelope {
+	env := &proto.EventEnvelope{
+		EventId:       "event-1",
+		SchemaVersion: SchemaVersion,
+		TenantId:      "user-1",
+		CorrelationId: "event-1",
+		OccurredAt:    "2026-03-01T12:30:45.123456789Z",
+		UserId:        "user-1",
+		DeviceId:      "laptop",
+	}
+	payload(env)
+	return env
+}
+
+func TestEncodeV1RoundTrip(t *testing.T) {
+	defer SetWriteVersion(int(SchemaVersion))
+	if err := SetWriteVersion(1); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     *proto.EventEnvelope
+		version string
+	}{
+		{"created", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_FileCreated{FileCreated: &proto.FileCreated{FileId: "f", Path: "a.txt", VersionId: "v", Entropy: 4.5}}
+		}), "1"},
+		{"modified and confirmed", envelope(func(env *proto.EventEnvelope) {
+			env.Confirmed = true
+			env.Payload = &proto.EventEnvelope_FileModified{FileModified: &proto.FileModified{FileId: "f", Path: "a.txt", VersionId: "v"}}
+		}), "1"},
+		{"deleted", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "1"},
+		{"renamed from a path", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{FileId: "f", OldPath: "a.txt", VersionId: "v"}}
+		}), "1"},
+		{"anomaly without a kind", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_DeviceAnomaly{DeviceAnomaly: &proto.DeviceAnomaly{AlertId: "alert", Reason: "too many deletions"}}
+		}), "1"},
+		{"without a timestamp", envelope(func(env *proto.EventEnvelope) {
+			env.OccurredAt = ""
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "1"},
+
+		// Version 1 cannot express these, so they are written at version 2.
+		{"organization tenant", envelope(func(env *proto.EventEnvelope) {
+			env.TenantId = "org-1"
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "2"},
+		{"caused by another event", envelope(func(env *proto.EventEnvelope) {
+			env.CausationId = "event-0"
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "2"},
+		{"correlated with another event", envelope(func(env *proto.EventEnvelope) {
+			env.CorrelationId = "request-1"
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "2"},
+		{"renamed to a new path", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{FileId: "f", OldPath: "a.txt", NewPath: "b.txt", VersionId: "v"}}
+		}), "2"},
+		{"anomaly with a kind", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_DeviceAnomaly{DeviceAnomaly: &proto.DeviceAnomaly{AlertId: "alert", Kind: "mass_delete", Reason: "too many deletions"}}
+		}), "2"},
+		{"local time", envelope(func(env *proto.EventEnvelope) {
+			env.OccurredAt = "2026-03-01T13:30:45+01:00"
+			env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{FileId: "f", Path: "a.txt"}}
+		}), "2"},
+		{"shared", envelope(func(env *proto.EventEnvelope) {
+			env.Payload = &proto.EventEnvelope_FileShared{FileShared: &proto.FileShared{FileId: "f", Path: "a.txt", SharedWithUserId: "user-2", Permission: "read"}}
+		}), "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, headers, err := Encode(tt.env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := headers[SchemaVersionHeader]; got != tt.version {
+				t.Fatalf("written at version %s, want %s", got, tt.version)
+			}
+
+			decoded, err := Decode(data, headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !protobuf.Equal(decoded, tt.env) {
+				t.Fatalf("round trip changed the event:\n got %v\nwant %v", decoded, tt.env)
+			}
+		})
+	}
+}
+
+// Version 1 documents written before headers existed are still read.
+func TestDecodeV1WithoutHeaders(t *testing.T) {
+	data := []byte(`{"event_id":"event-1","file_id":"f","file_path":"a.txt","change_type":"RENAMED",
+		"timestamp":"2026-03-01T13:30:45+01:00","device_id":"laptop","version_id":"v","user_id":"user-1"}`)
+
+	env, err := Decode(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := envelope(func(env *proto.EventEnvelope) {
+		env.OccurredAt = "2026-03-01T12:30:45Z"
+		env.Payload = &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{FileId: "f", OldPath: "a.txt", VersionId: "v"}}
+	})
+	if !protobuf.Equal(env, want) {
+		t.Fatalf("got %v, want %v", env, want)
+	}
+}
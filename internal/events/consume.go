@@ -0,0 +1,31 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+// Consume passes every event delivered to the group to the handler until
+// the context is cancelled. Instances that share a group split the events
+// between them. An event is acknowledged once the handler succeeds; one
+// that still fails after maxAttempts deliveries, or does not decode, is
+// dead-lettered.
+func Consume(ctx context.Context, bus utils.EventBus, group string, maxAttempts int, handler func(*proto.EventEnvelope) error) error {
+	opts := utils.SubscribeOptions{Group: group, Start: utils.StartLatest}
+	return bus.Subscribe(ctx, Topic, opts, utils.WithDeadLetter(bus, group, maxAttempts, func(ctx context.Context, msg *utils.Message) error {
+		env, err := Decode(msg.Value, msg.Headers)
+		if err != nil {
+			return utils.Permanent(fmt.Errorf("undecodable event: %v", err))
+		}
+		// The relay's header wins over the payload, so that a replayed
+		// event is not mistaken for the original.
+		if eventID := msg.Headers[utils.EventIDHeader]; eventID != "" {
+			env.EventId = eventID
+		}
+
+		return handler(env)
+	}))
+}
@@ -0,0 +1,182 @@
+// Package events defines how file change events travel on the bus. Every
+// event is a proto.EventEnvelope; older schema versions are upcast when
+// they are decoded, and producers can be held at an older version until
+// every consumer understands the current one.
+package events
+
+import (
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+	"gorm.io/gorm"
+)
+
+// Topic carries every file change event, keyed by user.
+const Topic = "file-changes"
+
+// CorrelationHeader is the incoming gRPC metadata key whose value, when
+// set, becomes the correlation ID of the events a request produces.
+const CorrelationHeader = "x-correlation-id"
+
+// Change types, as recorded in the change journal.
+const (
+	Created           = "CREATED"
+	Modified          = "MODIFIED"
+	Deleted           = "DELETED"
+	Renamed           = "RENAMED"
+	Shared            = "SHARED"
+	PermissionChanged = "PERMISSION_CHANGED"
+	Anomaly           = "ANOMALY"
+)
+
+// New returns an envelope for a change by a user's device, without a
+// payload. The tenant is looked up within tx, and the correlation ID is
+// taken from the request in tx's context when it has one.
+func New(tx *gorm.DB, userID, deviceID string) (*proto.EventEnvelope, error) {
+	tenant, err := tenantOf(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &proto.EventEnvelope{
+		EventId:       uuid.New().String(),
+		SchemaVersion: SchemaVersion,
+		TenantId:      tenant,
+		OccurredAt:    time.Now().UTC().Format(time.RFC3339Nano),
+		UserId:        userID,
+		DeviceId:      deviceID,
+	}
+	env.CorrelationId = env.EventId
+	if tx.Statement != nil && tx.Statement.Context != nil {
+		if md, ok := metadata.FromIncomingContext(tx.Statement.Context); ok {
+			if values := md.Get(CorrelationHeader); len(values) > 0 && values[0] != "" {
+				env.CorrelationId = values[0]
+			}
+		}
+	}
+	return env, nil
+}
+
+// NewFileChange returns an envelope for a change of the given type to a
+// file. versionID is empty when the change has not been recorded yet.
+func NewFileChange(tx *gorm.DB, userID, deviceID, changeType, fileID, path, versionID string) (*proto.EventEnvelope, error) {
+	env, err := New(tx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	setFileChange(env, changeType, fileID, path, versionID, 0)
+	return env, nil
+}
+
+// NewFileRename returns an envelope for a file moved from oldPath to
+// newPath without a change to its content.
+func NewFileRename(tx *gorm.DB, userID, deviceID, fileID, oldPath, newPath, versionID string) (*proto.EventEnvelope, error) {
+	env, err := New(tx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	env.Payload = &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{
+		FileId: fileID, OldPath: oldPath, NewPath: newPath, VersionId: versionID,
+	}}
+	return env, nil
+}
+
+// CausedBy records that env was produced in response to cause.
+func CausedBy(env, cause *proto.EventEnvelope) {
+	env.CausationId = cause.EventId
+	env.CorrelationId = cause.CorrelationId
+	if env.CorrelationId == "" {
+		env.CorrelationId = cause.EventId
+	}
+}
+
+// tenantOf returns the user's organization, or the user when they have
+// none or are unknown here.
+func tenantOf(tx *gorm.DB, userID string) (string, error) {
+	var orgIDs []string
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).Pluck("org_id", &orgIDs).Error; err != nil {
+		return "", err
+	}
+	if len(orgIDs) > 0 && orgIDs[0] != "" {
+		return orgIDs[0], nil
+	}
+	return userID, nil
+}
+
+// Change is the file-level view of an event that consumers act on.
+type Change struct {
+	Type      string
+	FileID    string
+	Path      string
+	VersionID string
+	Entropy   float64
+}
+
+// Describe returns the file-level view of an event. Path is the new path
+// of a renamed file, or its old path when the new one is unknown.
+func Describe(env *proto.EventEnvelope) Change {
+	switch payload := env.Payload.(type) {
+	case *proto.EventEnvelope_FileCreated:
+		p := payload.FileCreated
+		return Change{Type: Created, FileID: p.FileId, Path: p.Path, VersionID: p.VersionId, Entropy: p.Entropy}
+	case *proto.EventEnvelope_FileModified:
+		p := payload.FileModified
+		return Change{Type: Modified, FileID: p.FileId, Path: p.Path, VersionID: p.VersionId, Entropy: p.Entropy}
+	case *proto.EventEnvelope_FileDeleted:
+		p := payload.FileDeleted
+		return Change{Type: Deleted, FileID: p.FileId, Path: p.Path}
+	case *proto.EventEnvelope_FileRenamed:
+		p := payload.FileRenamed
+		path := p.NewPath
+		if path == "" {
+			path = p.OldPath
+		}
+		return Change{Type: Renamed, FileID: p.FileId, Path: path, VersionID: p.VersionId}
+	case *proto.EventEnvelope_FileShared:
+		p := payload.FileShared
+		return Change{Type: Shared, FileID: p.FileId, Path: p.Path}
+	case *proto.EventEnvelope_PermissionChanged:
+		p := payload.PermissionChanged
+		return Change{Type: PermissionChanged, FileID: p.FileId, Path: p.Path}
+	case *proto.EventEnvelope_DeviceAnomaly:
+		return Change{Type: Anomaly}
+	default:
+		return Change{}
+	}
+}
+
+// SetEntropy records the entropy of the new content of a created or
+// modified file.
+func SetEntropy(env *proto.EventEnvelope, entropy float64) {
+	switch payload := env.Payload.(type) {
+	case *proto.EventEnvelope_FileCreated:
+		payload.FileCreated.Entropy = entropy
+	case *proto.EventEnvelope_FileModified:
+		payload.FileModified.Entropy = entropy
+	}
+}
+
+func setFileChange(env *proto.EventEnvelope, changeType, fileID, path, versionID string, entropy float64) {
+	switch changeType {
+	case Created:
+		env.Payload = &proto.EventEnvelope_FileCreated{FileCreated: &proto.FileCreated{
+			FileId: fileID, Path: path, VersionId: versionID, Entropy: entropy,
+		}}
+	case Modified:
+		env.Payload = &proto.EventEnvelope_FileModified{FileModified: &proto.FileModified{
+			FileId: fileID, Path: path, VersionId: versionID, Entropy: entropy,
+		}}
+	case Deleted:
+		env.Payload = &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{
+			FileId: fileID, Path: path,
+		}}
+	case Renamed:
+		env.Payload = &proto.EventEnvelope_FileRenamed{FileRenamed: &proto.FileRenamed{
+			FileId: fileID, OldPath: path, VersionId: versionID,
+		}}
+	}
+}
@@ -5,35 +5,72 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"hash"
 	"io"
 	"net/http"
-
+	"strings"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/anomaly"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunker"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunkstore"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/middleware"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/versioning"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const chunkSize = 1024 * 1024 // 1MB chunks
 
 type FileGatewayService struct {
 	proto.UnimplementedFileServiceServer
-	db       *gorm.DB
-	s3Client *utils.S3Client
+	db        *gorm.DB
+	storage   utils.BlobStore
+	chunks    *chunkstore.Store
+	jwtSecret string
 }
 
-func NewFileGatewayService(db *gorm.DB, s3Client *utils.S3Client) *FileGatewayService {
+func NewFileGatewayService(db *gorm.DB, storage utils.BlobStore, jwtSecret string) *FileGatewayService {
 	return &FileGatewayService{
-		db:       db,
-		s3Client: s3Client,
+		db:        db,
+		storage:   storage,
+		chunks:    chunkstore.New(db, storage),
+		jwtSecret: jwtSecret,
+	}
+}
+
+// errNotOwner is returned from a transaction that found the file belongs
+// to another user.
+var errNotOwner = errors.New("file belongs to another user")
+
+// authorize checks that a token was issued to the given user, for calls
+// that hand out or replace the keys of encrypted files.
+func (s *FileGatewayService) authorize(token, userID string) error {
+	claims, err := middleware.ValidateToken(token, s.jwtSecret)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	if claims.UserID != userID {
+		return status.Errorf(codes.PermissionDenied, "token does not belong to user %s", userID)
 	}
+	return nil
 }
 
 func (s *FileGatewayService) UploadFile(stream proto.FileService_UploadFileServer) error {
+	ctx := stream.Context()
+
 	firstChunk, err := stream.Recv()
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to receive file chunk: %v", err)
@@ -44,134 +81,435 @@ func (s *FileGatewayService) UploadFile(stream proto.FileService_UploadFileServe
 		fileID = uuid.New().String()
 	}
 
-	var buffer bytes.Buffer
-	totalSize := int64(0)
-	hasher := sha256.New()
-
-	buffer.Write(firstChunk.Content)
-	hasher.Write(firstChunk.Content)
-	totalSize += int64(len(firstChunk.Content))
-
-	for {
-		chunk, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
-		}
-
-		buffer.Write(chunk.Content)
-		hasher.Write(chunk.Content)
-		totalSize += int64(len(chunk.Content))
+	// An end-to-end encrypted file stays encrypted for its whole life, and
+	// its first upload must carry the owner's wrapped data key.
+	var existing models.File
+	err = s.db.WithContext(ctx).Unscoped().First(&existing, "id = ?", fileID).Error
+	isNew := err == gorm.ErrRecordNotFound
+	if err != nil && !isNew {
+		return status.Errorf(codes.Internal, "failed to look up file: %v", err)
+	}
+	if !isNew && existing.OwnerID != firstChunk.UserId {
+		return status.Errorf(codes.PermissionDenied, "only the owner can upload to a file")
+	}
+	if !isNew && existing.Encrypted != firstChunk.Encrypted {
+		return status.Errorf(codes.FailedPrecondition, "file encryption mode cannot change")
+	}
+	if firstChunk.Encrypted && isNew && len(firstChunk.WrappedKey) == 0 {
+		return status.Errorf(codes.InvalidArgument, "encrypted uploads require a wrapped data key")
 	}
 
-	// content type detection
-	contentType := http.DetectContentType(buffer.Bytes())
-
-	fileHash := hex.EncodeToString(hasher.Sum(nil))
+	// Clients that chunk locally send one message per chunk with its hash,
+	// leaving the content empty for chunks the server already has.
+	var manifest []models.VersionChunk
+	summary := newContentSummary(firstChunk.Encrypted)
+	if firstChunk.ChunkHash != "" {
+		manifest, err = s.receiveManifest(ctx, firstChunk, stream, summary)
+	} else {
+		manifest, err = s.receiveContent(ctx, firstChunk, stream, summary)
+	}
+	if err != nil {
+		return err
+	}
 
-	s3Key := utils.GenerateS3Key(firstChunk.UserId, firstChunk.DeviceId, firstChunk.FileName)
+	totalSize := summary.size
+	fileHash := summary.Hash()
+	contentType := summary.ContentType()
 
-	err = s.s3Client.UploadFile(context.Background(), s3Key, &buffer)
+	storedSize, err := s.chunks.StoredSize(ctx, manifest)
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to upload to S3: %v", err)
+		return status.Errorf(codes.Internal, "failed to compute stored size: %v", err)
+	}
+
+	fileName := firstChunk.FileName
+	if firstChunk.Encrypted {
+		// The server never learns the real name of an encrypted file.
+		fileName = fileID
 	}
 
+	s3Key := utils.GenerateS3Key(firstChunk.UserId, firstChunk.DeviceId, fileName)
+
 	file := &models.File{
-		ID:          fileID,
-		Name:        firstChunk.FileName,
-		Path:        s3Key,
-		Size:        totalSize,
-		ContentType: contentType,
-		OwnerID:     firstChunk.UserId,
+		ID:            fileID,
+		Name:          fileName,
+		Path:          s3Key,
+		Size:          totalSize,
+		ContentType:   contentType,
+		Encrypted:     firstChunk.Encrypted,
+		EncryptedName: firstChunk.EncryptedName,
+		OwnerID:       firstChunk.UserId,
+	}
+	if firstChunk.Encrypted && len(file.EncryptedName) == 0 {
+		file.EncryptedName = existing.EncryptedName
 	}
 
 	version := &models.FileVersion{
-		FileID:   fileID,
-		Hash:     fileHash,
-		Size:     totalSize,
-		S3Key:    s3Key,
-		DeviceID: firstChunk.DeviceId,
+		ID:         uuid.New().String(),
+		FileID:     fileID,
+		Hash:       fileHash,
+		Size:       totalSize,
+		StoredSize: storedSize,
+		Codec:      summary.Codec(),
+		DeviceID:   firstChunk.DeviceId,
+		Vector:     vclock.Vector(firstChunk.BaseVector).Copy(),
+	}
+
+	for i := range manifest {
+		manifest[i].VersionID = version.ID
 	}
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(file).Error; err != nil {
+		// Uploading to a deleted file brings it back. The upsert also
+		// keeps concurrent first uploads of a file from colliding, and
+		// only updates a file of the same owner.
+		result := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"name", "path", "size", "content_type", "encrypted",
+				"encrypted_name", "updated_at", "deleted_at",
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "files", Name: "owner_id"}, Value: file.OwnerID},
+			}},
+		}).Create(file)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNotOwner
+		}
+		if err := versioning.Append(tx, version); err != nil {
+			return err
+		}
+		changeType := events.Modified
+		if isNew || existing.DeletedAt.Valid {
+			changeType = events.Created
+		}
+		if _, err := journal.Record(tx, file, changeType, version.ID, version.DeviceID); err != nil {
+			return err
+		}
+		env, err := events.NewFileChange(tx, file.OwnerID, version.DeviceID, changeType, fileID, file.Path, version.ID)
+		if err != nil {
 			return err
 		}
-		if err := tx.Create(version).Error; err != nil {
+		events.SetEntropy(env, summary.Entropy())
+		if err := outbox.EnqueueEvent(tx, env); err != nil {
 			return err
 		}
+		if len(manifest) > 0 {
+			if err := tx.Create(&manifest).Error; err != nil {
+				return err
+			}
+		}
+		if len(firstChunk.WrappedKey) > 0 {
+			if err := saveFileKey(tx, fileID, firstChunk.UserId, firstChunk.WrappedKey); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
+	if errors.Is(err, errNotOwner) {
+		return status.Errorf(codes.PermissionDenied, "only the owner can upload to a file")
+	}
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to save metadata: %v", err)
 	}
 
-	return stream.SendAndClose(&proto.FileUploadResponse{
-		FileId:  fileID,
-		Message: "File uploaded successfully",
-	})
+	response := &proto.FileUploadResponse{
+		FileId:        fileID,
+		Message:       "File uploaded successfully",
+		VersionId:     version.ID,
+		VersionVector: version.Vector.Copy(),
+	}
+	if version.ConflictsWith != nil {
+		response.ConflictingVersionId = *version.ConflictsWith
+		response.Message = "File uploaded concurrently with another change"
+	}
+	return stream.SendAndClose(response)
+}
+
+// receiveContent buffers a raw upload and splits it into chunks server-side.
+func (s *FileGatewayService) receiveContent(ctx context.Context, firstChunk *proto.FileUploadRequest, stream proto.FileService_UploadFileServer, summary *contentSummary) ([]models.VersionChunk, error) {
+	var buffer bytes.Buffer
+	buffer.Write(firstChunk.Content)
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
+		}
+
+		buffer.Write(chunk.Content)
+	}
+
+	summary.Write(buffer.Bytes())
+
+	manifest, err := s.chunks.Write(ctx, firstChunk.UserId, buffer.Bytes(), summary.Codec())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store chunks: %v", err)
+	}
+	return manifest, nil
+}
+
+// receiveManifest reads a stream of client-defined chunks. Chunks sent
+// without content must already be stored.
+func (s *FileGatewayService) receiveManifest(ctx context.Context, firstChunk *proto.FileUploadRequest, stream proto.FileService_UploadFileServer, summary *contentSummary) ([]models.VersionChunk, error) {
+	var manifest []models.VersionChunk
+	offset := int64(0)
+	// The codec is chosen once, from the first chunk the upload stores,
+	// as the sniffed content type changes while the head fills up.
+	codec := ""
+
+	msg := firstChunk
+	for {
+		if msg.ChunkHash == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "chunk %d is missing its hash", len(manifest))
+		}
+
+		content := msg.Content
+		if len(content) > 0 {
+			if chunker.Hash(content) != msg.ChunkHash {
+				return nil, status.Errorf(codes.InvalidArgument, "chunk %d does not match hash %s", len(manifest), msg.ChunkHash)
+			}
+			summary.Write(content)
+			if codec == "" {
+				codec = summary.Codec()
+			}
+			if _, err := s.chunks.PutChunk(ctx, firstChunk.UserId, content, codec); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to store chunk: %v", err)
+			}
+		} else {
+			exists, err := s.chunks.Reference(ctx, firstChunk.UserId, msg.ChunkHash)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to look up chunk: %v", err)
+			}
+			if !exists {
+				return nil, status.Errorf(codes.FailedPrecondition, "chunk %s not found", msg.ChunkHash)
+			}
+
+			// The whole-file hash covers deduplicated chunks too, so they
+			// are read back from storage.
+			content, err = s.chunks.ReadChunk(ctx, msg.ChunkHash)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to read chunk: %v", err)
+			}
+			summary.Write(content)
+		}
+
+		size := int64(len(content))
+
+		manifest = append(manifest, models.VersionChunk{
+			Index:     len(manifest),
+			ChunkHash: msg.ChunkHash,
+			Offset:    offset,
+			Size:      size,
+		})
+		offset += size
+
+		next, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to receive chunk: %v", err)
+		}
+		msg = next
+	}
+
+	return manifest, nil
+}
+
+// contentSummary accumulates the whole-file hash and size of an upload and
+// keeps the leading bytes needed for content type and entropy estimates.
+type contentSummary struct {
+	hasher    hash.Hash
+	head      []byte
+	size      int64
+	encrypted bool
+}
+
+func newContentSummary(encrypted bool) *contentSummary {
+	return &contentSummary{hasher: sha256.New(), encrypted: encrypted}
+}
+
+func (c *contentSummary) Write(data []byte) {
+	if len(c.head) < anomaly.SampleSize {
+		c.head = append(c.head, data[:min(len(data), anomaly.SampleSize-len(c.head))]...)
+	}
+	c.hasher.Write(data)
+	c.size += int64(len(data))
+}
+
+func (c *contentSummary) Hash() string {
+	return hex.EncodeToString(c.hasher.Sum(nil))
+}
+
+// content type detection
+func (c *contentSummary) ContentType() string {
+	// Ciphertext looks like random bytes, so sniffing it is meaningless.
+	if c.encrypted {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(c.head)
+}
+
+// Entropy estimates the entropy of the content like anomaly.FileEntropy.
+// Ciphertext always looks random, so it is not estimated.
+func (c *contentSummary) Entropy() float64 {
+	if c.encrypted {
+		return 0
+	}
+	return anomaly.Entropy(c.head)
+}
+
+func (c *contentSummary) Codec() string {
+	if c.encrypted {
+		return utils.CodecIdentity
+	}
+	return utils.SelectCodec(c.ContentType())
 }
 
 func (s *FileGatewayService) DownloadFile(req *proto.FileDownloadRequest, stream proto.FileService_DownloadFileServer) error {
 	var file models.File
-	if err := s.db.Preload("Versions").First(&file, "id = ?", req.FileId).Error; err != nil {
+	if err := s.db.Scopes(versioning.PreloadVersions).First(&file, "id = ?", req.FileId).Error; err != nil {
 		return status.Errorf(codes.NotFound, "file not found: %v", err)
 	}
 
-	if len(file.Versions) == 0 {
+	current := versioning.Current(&file)
+	if current == nil {
 		return status.Errorf(codes.NotFound, "no versions found for file")
 	}
-	version := file.Versions[len(file.Versions)-1]
+	version := *current
 
-	reader, err := s.s3Client.DownloadFile(context.Background(), version.S3Key)
-	if err != nil {
-		return status.Errorf(codes.Internal, "failed to download from S3: %v", err)
+	ctx := stream.Context()
+
+	// Clients that can decode zstd receive the stored bytes as they are.
+	var reader io.ReadCloser
+	if acceptsEncoding(ctx, utils.CodecZstd) {
+		encoded, ok, err := s.chunks.OpenEncoded(ctx, &version, utils.CodecZstd)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to download from S3: %v", err)
+		}
+		if ok {
+			if err := stream.SetHeader(metadata.Pairs("content-encoding", utils.CodecZstd)); err != nil {
+				encoded.Close()
+				return status.Errorf(codes.Internal, "failed to set header: %v", err)
+			}
+			reader = encoded
+		}
+	}
+
+	if reader == nil {
+		decoded, err := s.chunks.Open(ctx, &version)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to download from S3: %v", err)
+		}
+		reader = decoded
 	}
 	defer reader.Close()
 
 	buffer := make([]byte, chunkSize)
 	for {
-		n, err := reader.Read(buffer)
-		if err == io.EOF {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			response := &proto.FileDownloadResponse{
+				Content: buffer[:n],
+			}
+
+			if err := stream.Send(response); err != nil {
+				return status.Errorf(codes.Internal, "failed to send chunk: %v", err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			break
 		}
 		if err != nil {
 			return status.Errorf(codes.Internal, "failed to read file: %v", err)
 		}
+	}
 
-		response := &proto.FileDownloadResponse{
-			Content: buffer[:n],
-		}
+	return nil
+}
+
+func acceptsEncoding(ctx context.Context, codec string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
 
-		if err := stream.Send(response); err != nil {
-			return status.Errorf(codes.Internal, "failed to send chunk: %v", err)
+	for _, value := range md.Get("accept-encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			if strings.TrimSpace(encoding) == codec {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	return nil
+func (s *FileGatewayService) HasChunks(ctx context.Context, req *proto.HasChunksRequest) (*proto.HasChunksResponse, error) {
+	missing, err := s.chunks.Missing(ctx, req.UserId, req.ChunkHashes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to look up chunks: %v", err)
+	}
+
+	return &proto.HasChunksResponse{
+		MissingHashes: missing,
+	}, nil
 }
 
 func (s *FileGatewayService) GetFileMetadata(ctx context.Context, req *proto.FileMetadataRequest) (*proto.FileMetadataResponse, error) {
 	var file models.File
-	if err := s.db.Preload("Versions").First(&file, "id = ?", req.FileId).Error; err != nil {
+	if err := s.db.Scopes(versioning.PreloadVersions).Preload("Keys").First(&file, "id = ?", req.FileId).Error; err != nil {
 		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
 	}
 
-	latestVersion := file.Versions[len(file.Versions)-1]
+	return toFileMetadataProto(&file), nil
+}
 
-	return &proto.FileMetadataResponse{
-		FileId:      file.ID,
-		FileName:    file.Name,
-		Size:        file.Size,
-		ContentType: file.ContentType,
-		CreatedAt:   file.CreatedAt.String(),
-		UpdatedAt:   file.UpdatedAt.String(),
-		VersionId:   latestVersion.ID,
-		OwnerId:     file.OwnerID,
-	}, nil
+func toFileMetadataProto(file *models.File) *proto.FileMetadataResponse {
+	metadata := &proto.FileMetadataResponse{
+		FileId:           file.ID,
+		FileName:         file.Name,
+		Size:             file.Size,
+		ContentType:      file.ContentType,
+		CreatedAt:        file.CreatedAt.String(),
+		UpdatedAt:        file.UpdatedAt.String(),
+		OwnerId:          file.OwnerID,
+		SharedWith:       sharedWith(file),
+		CompressionRatio: 1,
+		Encrypted:        file.Encrypted,
+		EncryptedName:    file.EncryptedName,
+	}
+	if current := versioning.Current(file); current != nil {
+		metadata.VersionId = current.ID
+		metadata.VersionVector = current.Vector.Copy()
+		metadata.CompressionRatio = compressionRatio(current)
+	}
+	return metadata
+}
+
+func sharedWith(file *models.File) []string {
+	var recipients []string
+	for _, key := range file.Keys {
+		if key.RecipientID != file.OwnerID {
+			recipients = append(recipients, key.RecipientID)
+		}
+	}
+	return recipients
+}
+
+// compressionRatio reports how many original bytes are stored per stored
+// byte, so 2.0 means the version takes half its size in storage.
+func compressionRatio(version *models.FileVersion) float64 {
+	if version.StoredSize == 0 || version.Size == 0 {
+		return 1
+	}
+	return float64(version.Size) / float64(version.StoredSize)
 }
 
 func (s *FileGatewayService) ListFiles(ctx context.Context, req *proto.ListFilesRequest) (*proto.ListFilesResponse, error) {
@@ -188,7 +526,10 @@ func (s *FileGatewayService) ListFiles(ctx context.Context, req *proto.ListFiles
 	}
 
 	offset := (req.Page - 1) * req.PageSize
-	if err := query.Offset(int(offset)).Limit(int(req.PageSize)).Preload("Versions").Find(&files).Error; err != nil {
+	if err := query.Order("path ASC").Order("id ASC").
+		Offset(int(offset)).Limit(int(req.PageSize)).
+		Scopes(versioning.PreloadVersions).Preload("Keys").
+		Find(&files).Error; err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list files: %v", err)
 	}
 
@@ -197,19 +538,192 @@ func (s *FileGatewayService) ListFiles(ctx context.Context, req *proto.ListFiles
 		Files:      make([]*proto.FileMetadataResponse, len(files)),
 	}
 
-	for i, file := range files {
-		latestVersion := file.Versions[len(file.Versions)-1]
-		response.Files[i] = &proto.FileMetadataResponse{
-			FileId:      file.ID,
-			FileName:    file.Name,
-			Size:        file.Size,
-			ContentType: file.ContentType,
-			CreatedAt:   file.CreatedAt.String(),
-			UpdatedAt:   file.UpdatedAt.String(),
-			VersionId:   latestVersion.ID,
-			OwnerId:     file.OwnerID,
-		}
+	for i := range files {
+		response.Files[i] = toFileMetadataProto(&files[i])
 	}
 
 	return response, nil
 }
+
+func saveFileKey(tx *gorm.DB, fileID, recipientID string, wrappedKey []byte) error {
+	key := &models.FileKey{
+		FileID:      fileID,
+		RecipientID: recipientID,
+		WrappedKey:  wrappedKey,
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "file_id"}, {Name: "recipient_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"wrapped_key"}),
+	}).Create(key).Error
+}
+
+// ShareFile grants a recipient access to an encrypted file. The owner's
+// client unwraps the data key and re-wraps it for the recipient's public
+// key, so the server only ever stores wrapped keys.
+func (s *FileGatewayService) ShareFile(ctx context.Context, req *proto.ShareFileRequest) (*proto.ShareFileResponse, error) {
+	if err := s.authorize(req.Token, req.UserId); err != nil {
+		return nil, err
+	}
+
+	var file models.File
+	if err := s.db.First(&file, "id = ?", req.FileId).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	if file.OwnerID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "only the owner can share a file")
+	}
+	if !file.Encrypted {
+		return nil, status.Errorf(codes.FailedPrecondition, "file is not end-to-end encrypted")
+	}
+	if len(req.WrappedKey) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "wrapped key is required")
+	}
+
+	var recipientKey models.UserKey
+	if err := s.db.First(&recipientKey, "user_id = ?", req.RecipientId).Error; err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "recipient has no registered public key")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveFileKey(tx, file.ID, req.RecipientId, req.WrappedKey); err != nil {
+			return err
+		}
+		if _, err := journal.Record(tx, &file, events.Shared, "", ""); err != nil {
+			return err
+		}
+		env, err := events.New(tx, file.OwnerID, "")
+		if err != nil {
+			return err
+		}
+		env.Payload = &proto.EventEnvelope_FileShared{FileShared: &proto.FileShared{
+			FileId:           file.ID,
+			Path:             file.Path,
+			SharedWithUserId: req.RecipientId,
+			Permission:       "read",
+		}}
+		return outbox.EnqueueEvent(tx, env)
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to share file: %v", err)
+	}
+
+	return &proto.ShareFileResponse{
+		Success: true,
+		Message: "File shared successfully",
+	}, nil
+}
+
+// DeleteFile deletes a file on behalf of one of its owner's devices. The
+// file is kept as a soft-deleted row so that it can still be restored.
+func (s *FileGatewayService) DeleteFile(ctx context.Context, req *proto.DeleteFileRequest) (*proto.DeleteFileResponse, error) {
+	var file models.File
+	if err := s.db.WithContext(ctx).First(&file, "id = ?", req.FileId).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if file.OwnerID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "only the owner can delete a file")
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&file).Error; err != nil {
+			return err
+		}
+		if _, err := journal.Record(tx, &file, events.Deleted, "", req.DeviceId); err != nil {
+			return err
+		}
+		env, err := events.NewFileChange(tx, file.OwnerID, req.DeviceId, events.Deleted, file.ID, file.Path, "")
+		if err != nil {
+			return err
+		}
+		return outbox.EnqueueEvent(tx, env)
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete file: %v", err)
+	}
+
+	return &proto.DeleteFileResponse{
+		Success: true,
+		Message: "File deleted successfully",
+	}, nil
+}
+
+// MoveFile renames or moves a file on behalf of one of its owner's
+// devices. Only its metadata changes; the content stays where it is.
+func (s *FileGatewayService) MoveFile(ctx context.Context, req *proto.MoveFileRequest) (*proto.MoveFileResponse, error) {
+	var file models.File
+	if err := s.db.WithContext(ctx).First(&file, "id = ?", req.FileId).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if file.OwnerID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "only the owner can move a file")
+	}
+
+	oldPath := file.Path
+	if file.Encrypted {
+		// The server never learns the real name of an encrypted file, so
+		// only its sealed name changes.
+		if len(req.EncryptedName) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "encrypted name is required")
+		}
+		file.EncryptedName = req.EncryptedName
+	} else {
+		if req.NewName == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "new name is required")
+		}
+		file.Name = req.NewName
+		file.Path = utils.GenerateS3Key(file.OwnerID, req.DeviceId, req.NewName)
+	}
+
+	versionID := ""
+	if file.CurrentVersionID != nil {
+		versionID = *file.CurrentVersionID
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&file).Select("name", "path", "encrypted_name").Updates(&file).Error; err != nil {
+			return err
+		}
+		if _, err := journal.Record(tx, &file, events.Renamed, versionID, req.DeviceId); err != nil {
+			return err
+		}
+		env, err := events.NewFileRename(tx, file.OwnerID, req.DeviceId, file.ID, oldPath, file.Path, versionID)
+		if err != nil {
+			return err
+		}
+		return outbox.EnqueueEvent(tx, env)
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to move file: %v", err)
+	}
+
+	return &proto.MoveFileResponse{
+		Success: true,
+		Message: "File moved successfully",
+	}, nil
+}
+
+func (s *FileGatewayService) GetFileKey(ctx context.Context, req *proto.FileKeyRequest) (*proto.FileKeyResponse, error) {
+	if err := s.authorize(req.Token, req.UserId); err != nil {
+		return nil, err
+	}
+
+	var file models.File
+	if err := s.db.First(&file, "id = ?", req.FileId).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	var key models.FileKey
+	if err := s.db.First(&key, "file_id = ? AND recipient_id = ?", req.FileId, req.UserId).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, status.Errorf(codes.PermissionDenied, "no key for this user")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to look up key: %v", err)
+	}
+
+	return &proto.FileKeyResponse{
+		WrappedKey:    key.WrappedKey,
+		EncryptedName: file.EncryptedName,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/middleware"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+const testSecret = "test-secret"
+
+// newTestClient serves a gateway backed by sqlite and an in-memory blob
+// store, and returns a client for it along with its database.
+func newTestClient(t *testing.T) (proto.FileServiceClient, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "gateway.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.UserKey{}, &models.File{}, &models.FileKey{}, &models.FileVersion{},
+		&models.Chunk{}, &models.ChunkOwner{}, &models.VersionChunk{}, &models.OutboxEvent{},
+		&models.ChangeEntry{}, &models.JournalHead{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	server := grpc.NewServer()
+	proto.RegisterFileServiceServer(server, NewFileGatewayService(db, utils.NewMemoryBlobStore(), testSecret))
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return proto.NewFileServiceClient(conn), db
+}
+
+func upload(client proto.FileServiceClient, req *proto.FileUploadRequest) (*proto.FileUploadResponse, error) {
+	stream, err := client.UploadFile(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	return stream.CloseAndRecv()
+}
+
+func createUser(t *testing.T, db *gorm.DB, name string) (*models.User, string) {
+	t.Helper()
+	user := &models.User{Email: name + "@example.com", Username: name, Password: "x"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, err := middleware.GenerateToken(user.ID, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, token
+}
+
+func TestUploadToAnotherUsersFile(t *testing.T) {
+	client, db := newTestClient(t)
+	alice, _ := createUser(t, db, "alice")
+	bob, _ := createUser(t, db, "bob")
+
+	created, err := upload(client, &proto.FileUploadRequest{
+		UserId: alice.ID, DeviceId: "laptop", FileName: "notes.txt", Content: []byte("alice's notes\n"),
+	})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	_, err = upload(client, &proto.FileUploadRequest{
+		UserId: bob.ID, DeviceId: "phone", FileName: "stolen.txt", Content: []byte("bob's notes\n"),
+		FileId: created.FileId,
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("upload to another user's file: %v, want PermissionDenied", err)
+	}
+
+	var file models.File
+	if err := db.First(&file, "id = ?", created.FileId).Error; err != nil {
+		t.Fatal(err)
+	}
+	if file.OwnerID != alice.ID || file.Name != "notes.txt" {
+		t.Fatalf("file now %s owned by %s", file.Name, file.OwnerID)
+	}
+}
+
+func TestKeyCallsCheckTheCaller(t *testing.T) {
+	client, db := newTestClient(t)
+	alice, aliceToken := createUser(t, db, "alice")
+	bob, bobToken := createUser(t, db, "bob")
+	if err := db.Create(&models.UserKey{UserID: bob.ID, PublicKey: make([]byte, 32), Algorithm: "x25519-xsalsa20-poly1305"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := upload(client, &proto.FileUploadRequest{
+		UserId: alice.ID, DeviceId: "laptop", Content: []byte("ciphertext"),
+		Encrypted: true, WrappedKey: []byte("alice's wrapped key"),
+	})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	ctx := context.Background()
+	share := func(token string) error {
+		_, err := client.ShareFile(ctx, &proto.ShareFileRequest{
+			FileId: created.FileId, UserId: alice.ID, RecipientId: bob.ID,
+			WrappedKey: []byte("bob's wrapped key"), Token: token,
+		})
+		return err
+	}
+	getKey := func(token string) error {
+		_, err := client.GetFileKey(ctx, &proto.FileKeyRequest{FileId: created.FileId, UserId: alice.ID, Token: token})
+		return err
+	}
+
+	tests := []struct {
+		name  string
+		call  func(string) error
+		token string
+		want  codes.Code
+	}{
+		{"share without token", share, "", codes.Unauthenticated},
+		{"share as another user", share, bobToken, codes.PermissionDenied},
+		{"share as owner", share, aliceToken, codes.OK},
+		{"key without token", getKey, "", codes.Unauthenticated},
+		{"key of another user", getKey, bobToken, codes.PermissionDenied},
+		{"own key", getKey, aliceToken, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(tt.token); status.Code(err) != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// The share is journaled along with the upload, for every device.
+	var entries []models.ChangeEntry
+	if err := db.Order("seq").Find(&entries, "user_id = ?", alice.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].ChangeType != events.Shared || entries[1].FileID != created.FileId || entries[1].DeviceID != "" {
+		t.Fatalf("journal holds %+v, want the upload and the share", entries)
+	}
+}
@@ -0,0 +1,167 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/retention"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var retentionScopes = map[proto.RetentionPolicy_Scope]string{
+	proto.RetentionPolicy_USER:   models.RetentionScopeUser,
+	proto.RetentionPolicy_ORG:    models.RetentionScopeOrg,
+	proto.RetentionPolicy_FOLDER: models.RetentionScopeFolder,
+}
+
+func toRetentionPolicyProto(policy *models.RetentionPolicy) *proto.RetentionPolicy {
+	scope := proto.RetentionPolicy_USER
+	for s, name := range retentionScopes {
+		if name == policy.Scope {
+			scope = s
+		}
+	}
+
+	return &proto.RetentionPolicy{
+		Scope:              scope,
+		ScopeId:            policy.ScopeID,
+		FolderPath:         policy.FolderPath,
+		KeepLast:           int32(policy.KeepLast),
+		KeepHourlyForHours: int32(policy.KeepHourlyForHours),
+		KeepDailyForDays:   int32(policy.KeepDailyForDays),
+		KeepAllForDays:     int32(policy.KeepAllForDays),
+		KeepForever:        policy.KeepForever,
+	}
+}
+
+// scopeID fills in and checks the owner of a policy scope. Users manage
+// their own and their folders' policies, and the policy of their
+// organization.
+func (s *FileGatewayService) scopeID(userID string, scope proto.RetentionPolicy_Scope, requested string) (string, error) {
+	if scope != proto.RetentionPolicy_ORG {
+		if requested != "" && requested != userID {
+			return "", status.Errorf(codes.PermissionDenied, "cannot access another user's retention policy")
+		}
+		return userID, nil
+	}
+
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return "", status.Errorf(codes.NotFound, "user not found: %v", err)
+	}
+	if user.OrgID == "" || (requested != "" && requested != user.OrgID) {
+		return "", status.Errorf(codes.PermissionDenied, "user does not belong to organization")
+	}
+	return user.OrgID, nil
+}
+
+func (s *FileGatewayService) SetRetentionPolicy(ctx context.Context, req *proto.SetRetentionPolicyRequest) (*proto.SetRetentionPolicyResponse, error) {
+	if req.Policy == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "policy is required")
+	}
+
+	scopeID, err := s.scopeID(req.UserId, req.Policy.Scope, req.Policy.ScopeId)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.RetentionPolicy{
+		Scope:              retentionScopes[req.Policy.Scope],
+		ScopeID:            scopeID,
+		FolderPath:         req.Policy.FolderPath,
+		KeepLast:           int(req.Policy.KeepLast),
+		KeepHourlyForHours: int(req.Policy.KeepHourlyForHours),
+		KeepDailyForDays:   int(req.Policy.KeepDailyForDays),
+		KeepAllForDays:     int(req.Policy.KeepAllForDays),
+		KeepForever:        req.Policy.KeepForever,
+	}
+
+	if err := retention.Validate(policy); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid retention policy: %v", err)
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "scope_id"}, {Name: "folder_path"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"keep_last", "keep_hourly_for_hours", "keep_daily_for_days",
+			"keep_all_for_days", "keep_forever", "updated_at",
+		}),
+	}).Create(policy).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save retention policy: %v", err)
+	}
+
+	return &proto.SetRetentionPolicyResponse{
+		Success: true,
+		Message: "Retention policy saved successfully",
+	}, nil
+}
+
+// GetRetentionPolicy returns the policy set at a scope, or the policy in
+// effect for a file when a file ID is given.
+func (s *FileGatewayService) GetRetentionPolicy(ctx context.Context, req *proto.GetRetentionPolicyRequest) (*proto.GetRetentionPolicyResponse, error) {
+	if req.FileId != "" {
+		var file models.File
+		if err := s.db.First(&file, "id = ? AND owner_id = ?", req.FileId, req.UserId).Error; err != nil {
+			return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+		}
+
+		policy, err := retention.Resolve(s.db, &file)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to resolve retention policy: %v", err)
+		}
+		if policy == nil {
+			return &proto.GetRetentionPolicyResponse{Found: false}, nil
+		}
+		return &proto.GetRetentionPolicyResponse{Found: true, Policy: toRetentionPolicyProto(policy)}, nil
+	}
+
+	scopeID, err := s.scopeID(req.UserId, req.Scope, req.ScopeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy models.RetentionPolicy
+	err = s.db.Where("scope = ? AND scope_id = ? AND folder_path = ?", retentionScopes[req.Scope], scopeID, req.FolderPath).
+		First(&policy).Error
+	if err == gorm.ErrRecordNotFound {
+		return &proto.GetRetentionPolicyResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load retention policy: %v", err)
+	}
+
+	return &proto.GetRetentionPolicyResponse{Found: true, Policy: toRetentionPolicyProto(&policy)}, nil
+}
+
+// PinVersion marks a version to be kept forever, regardless of policy.
+func (s *FileGatewayService) PinVersion(ctx context.Context, req *proto.PinVersionRequest) (*proto.PinVersionResponse, error) {
+	var file models.File
+	if err := s.db.First(&file, "id = ? AND owner_id = ?", req.FileId, req.UserId).Error; err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	result := s.db.Model(&models.FileVersion{}).
+		Where("id = ? AND file_id = ?", req.VersionId, file.ID).
+		Update("pinned", req.Pinned)
+	if result.Error != nil {
+		return nil, status.Errorf(codes.Internal, "failed to pin version: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, status.Errorf(codes.NotFound, "version not found")
+	}
+
+	message := "Version pinned successfully"
+	if !req.Pinned {
+		message = "Version unpinned successfully"
+	}
+
+	return &proto.PinVersionResponse{
+		Success: true,
+		Message: message,
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Package journal keeps a durable, ordered log of the changes to each
+// user's files, which devices read from a cursor to catch up.
+package journal
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Record appends a change to the journal of the file's owner. It must run
+// in the transaction that makes the change. The owner's journal head stays
+// locked until that transaction ends, so entries become visible in
+// sequence order and readers never skip one that commits late.
+func Record(tx *gorm.DB, file *models.File, changeType, versionID, deviceID string) (*models.ChangeEntry, error) {
+	entry := &models.ChangeEntry{
+		UserID:     file.OwnerID,
+		FileID:     file.ID,
+		FilePath:   file.Path,
+		ChangeType: changeType,
+		VersionID:  versionID,
+		DeviceID:   deviceID,
+	}
+	return entry, record(tx, entry)
+}
+
+// RecordAlert appends an alert about a paused device to its user's
+// journal, so that every device watching the user hears of it, whichever
+// instance it is connected to. Like Record, it must run in the
+// transaction that creates the alert.
+func RecordAlert(tx *gorm.DB, alert *models.DeviceAlert, changeType string) (*models.ChangeEntry, error) {
+	entry := &models.ChangeEntry{
+		UserID:     alert.UserID,
+		ChangeType: changeType,
+		DeviceID:   alert.DeviceID,
+		AlertID:    alert.ID,
+		Reason:     alert.Reason,
+	}
+	return entry, record(tx, entry)
+}
+
+func record(tx *gorm.DB, entry *models.ChangeEntry) error {
+	if err := tx.Raw(`INSERT INTO journal_heads (user_id, seq) VALUES (?, 1)
+		ON CONFLICT (user_id) DO UPDATE SET seq = journal_heads.seq + 1
+		RETURNING seq`, entry.UserID).Scan(&entry.Seq).Error; err != nil {
+		return err
+	}
+
+	// Alerts have no file; their file_id stays NULL.
+	if entry.FileID == "" {
+		return tx.Omit("FileID").Create(entry).Error
+	}
+	return tx.Create(entry).Error
+}
+
+// Read returns up to limit changes of a user after the given sequence
+// number, oldest first.
+func Read(db *gorm.DB, userID string, after int64, limit int) ([]models.ChangeEntry, error) {
+	var entries []models.ChangeEntry
+	err := db.Where("user_id = ? AND seq > ?", userID, after).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Head returns the sequence number of a user's latest change, or 0.
+func Head(db *gorm.DB, userID string) (int64, error) {
+	var seq int64
+	err := db.Model(&models.JournalHead{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(MAX(seq), 0)").
+		Scan(&seq).Error
+	return seq, err
+}
+
+// FormatCursor turns a sequence number into the opaque cursor handed to
+// clients.
+func FormatCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// ParseCursor reads a cursor. The empty cursor is the start of the journal.
+func ParseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, fmt.Errorf("invalid cursor: %q", cursor)
+	}
+	return seq, nil
+}
@@ -0,0 +1,91 @@
+package merge
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// lcsLength is the textbook dynamic program, to check commonLines against.
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func checkCommonLines(t *testing.T, a, b []string) {
+	t.Helper()
+	matches := commonLines(a, b)
+	for i, m := range matches {
+		if a[m[0]] != b[m[1]] {
+			t.Fatalf("match %v pairs %q with %q", m, a[m[0]], b[m[1]])
+		}
+		if i > 0 && (m[0] <= matches[i-1][0] || m[1] <= matches[i-1][1]) {
+			t.Fatalf("matches out of order: %v", matches)
+		}
+	}
+	if want := lcsLength(a, b); len(matches) != want {
+		t.Fatalf("commonLines(%q, %q) found %d matches, want %d", a, b, len(matches), want)
+	}
+}
+
+func TestCommonLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"both empty", "", ""},
+		{"one empty", "abc", ""},
+		{"identical", "abcdef", "abcdef"},
+		{"insert", "abcdef", "abXcdef"},
+		{"delete", "abcdef", "abdef"},
+		{"replace", "abcdef", "abXYef"},
+		{"append", "abc", "abcde"},
+		{"prepend", "abc", "zabc"},
+		{"nothing in common", "abc", "xyz"},
+		{"repeated lines", "aabbaabb", "abab"},
+		{"classic", "abcabba", "cbabac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkCommonLines(t, strings.Split(tt.a, ""), strings.Split(tt.b, ""))
+		})
+	}
+}
+
+func TestCommonLinesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, rng.Intn(40))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		checkCommonLines(t, random(), random())
+	}
+}
+
+func TestCommonLinesGivesUp(t *testing.T) {
+	a := make([]string, maxEdits)
+	b := make([]string, maxEdits)
+	for i := range a {
+		a[i], b[i] = "a", "b"
+	}
+	if matches := commonLines(a, b); matches != nil {
+		t.Fatalf("got %d matches past the edit limit, want none", len(matches))
+	}
+}
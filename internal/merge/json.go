@@ -0,0 +1,292 @@
+package merge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSON merges JSON documents key by key. Object keys keep their order,
+// numbers keep their exact text, and the output uses the indentation of
+// the local document.
+func JSON(base, local, remote []byte) (*Result, error) {
+	docs := make([]interface{}, 3)
+	for i, data := range [][]byte{base, local, remote} {
+		doc, err := decodeJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON document: %v", err)
+		}
+		docs[i] = doc
+	}
+
+	var conflicts []string
+	merged := mergeJSON("", docs[0], docs[1], docs[2], &conflicts)
+	if len(conflicts) > 0 {
+		return &Result{Conflicts: conflicts}, nil
+	}
+
+	var out bytes.Buffer
+	if merged != nil {
+		writeJSON(&out, merged, jsonIndent(local), "")
+		out.WriteByte('\n')
+	}
+	return &Result{Merged: out.Bytes()}, nil
+}
+
+// jsonObject is a JSON object that remembers the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *jsonObject) get(key string) interface{} {
+	if o == nil {
+		return nil
+	}
+	return o.values[key]
+}
+
+// jsonNull is a JSON null. A nil value means the key is absent.
+type jsonNull struct{}
+
+// decodeJSON parses a document into *jsonObject, []interface{},
+// json.Number, string, bool and jsonNull values. Empty input is an empty
+// document, which decodes to nil.
+func decodeJSON(data []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the document")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			object := &jsonObject{values: make(map[string]interface{})}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				// A repeated key keeps its first place and its last value.
+				name := key.(string)
+				if _, ok := object.values[name]; !ok {
+					object.keys = append(object.keys, name)
+				}
+				object.values[name] = value
+			}
+			_, err := decoder.Token()
+			return object, err
+
+		case '[':
+			array := []interface{}{}
+			for decoder.More() {
+				value, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			}
+			_, err := decoder.Token()
+			return array, err
+		}
+		return nil, fmt.Errorf("unexpected %v", token)
+
+	case nil:
+		return jsonNull{}, nil
+	default:
+		return token, nil
+	}
+}
+
+// mergeJSON merges one value like mergeValue does for YAML: objects are
+// merged recursively, anything else is taken from the side that changed
+// it, and paths changed differently on both sides are reported as JSON
+// pointers.
+func mergeJSON(path string, base, local, remote interface{}, conflicts *[]string) interface{} {
+	switch {
+	case equalJSON(local, remote), equalJSON(base, remote):
+		return local
+	case equalJSON(base, local):
+		return remote
+	}
+
+	localObject, localOK := local.(*jsonObject)
+	remoteObject, remoteOK := remote.(*jsonObject)
+	baseObject, baseOK := base.(*jsonObject)
+	if localOK && remoteOK && (base == nil || baseOK) {
+		merged := &jsonObject{values: make(map[string]interface{})}
+		add := func(key string, value interface{}) {
+			if value != nil {
+				merged.keys = append(merged.keys, key)
+				merged.values[key] = value
+			}
+		}
+
+		// Keys keep the local order, followed by keys only remote has.
+		for _, key := range localObject.keys {
+			add(key, mergeJSON(path+"/"+escapePointer(key),
+				baseObject.get(key), localObject.values[key], remoteObject.get(key), conflicts))
+		}
+		for _, key := range remoteObject.keys {
+			if _, ok := localObject.values[key]; ok {
+				continue
+			}
+			add(key, mergeJSON(path+"/"+escapePointer(key),
+				baseObject.get(key), nil, remoteObject.values[key], conflicts))
+		}
+		return merged
+	}
+
+	if path == "" {
+		path = "/"
+	}
+	*conflicts = append(*conflicts, path)
+	return nil
+}
+
+// equalJSON compares values, ignoring the order of object keys. Numbers
+// are compared as written.
+func equalJSON(a, b interface{}) bool {
+	switch a := a.(type) {
+	case *jsonObject:
+		b, ok := b.(*jsonObject)
+		if !ok || len(a.keys) != len(b.keys) {
+			return false
+		}
+		for _, key := range a.keys {
+			other, ok := b.values[key]
+			if !ok || !equalJSON(a.values[key], other) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return a == b
+	}
+}
+
+// jsonIndent guesses the indentation of a JSON document from the first
+// line after the opening one. Documents on one line are written on one
+// line.
+func jsonIndent(data []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]; indent != "" {
+			return indent
+		}
+		return "  "
+	}
+	return ""
+}
+
+func writeJSON(out *bytes.Buffer, value interface{}, indent, prefix string) {
+	newline := func(depth string) {
+		if indent != "" {
+			out.WriteByte('\n')
+			out.WriteString(depth)
+		}
+	}
+	separator := ":"
+	if indent != "" {
+		separator = ": "
+	}
+
+	switch value := value.(type) {
+	case *jsonObject:
+		if len(value.keys) == 0 {
+			out.WriteString("{}")
+			return
+		}
+		out.WriteByte('{')
+		for i, key := range value.keys {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			newline(prefix + indent)
+			writeJSONString(out, key)
+			out.WriteString(separator)
+			writeJSON(out, value.values[key], indent, prefix+indent)
+		}
+		newline(prefix)
+		out.WriteByte('}')
+
+	case []interface{}:
+		if len(value) == 0 {
+			out.WriteString("[]")
+			return
+		}
+		out.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			newline(prefix + indent)
+			writeJSON(out, item, indent, prefix+indent)
+		}
+		newline(prefix)
+		out.WriteByte(']')
+
+	case string:
+		writeJSONString(out, value)
+	case json.Number:
+		out.WriteString(value.String())
+	case bool:
+		if value {
+			out.WriteString("true")
+		} else {
+			out.WriteString("false")
+		}
+	case jsonNull:
+		out.WriteString("null")
+	}
+}
+
+// writeJSONString quotes a string without escaping HTML characters, which
+// the documents being merged rarely do.
+func writeJSONString(out *bytes.Buffer, s string) {
+	var quoted bytes.Buffer
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	out.Write(bytes.TrimSuffix(quoted.Bytes(), []byte{'\n'}))
+}
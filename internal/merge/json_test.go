@@ -0,0 +1,138 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		want                string
+		conflicts           []string
+	}{
+		{
+			name: "edits to different keys",
+			base: `{"a": 1, "b": 2}`, local: `{"a": 10, "b": 2}`, remote: `{"a": 1, "b": 20}`,
+			want: `{"a":10,"b":20}` + "\n",
+		},
+		{
+			name:   "nested edits",
+			base:   "{\n  \"db\": {\"host\": \"a\", \"port\": 1}\n}\n",
+			local:  "{\n  \"db\": {\"host\": \"b\", \"port\": 1}\n}\n",
+			remote: "{\n  \"db\": {\"host\": \"a\", \"port\": 2}\n}\n",
+			want:   "{\n  \"db\": {\n    \"host\": \"b\",\n    \"port\": 2\n  }\n}\n",
+		},
+		{
+			name: "key order follows local, then keys only remote added",
+			base: `{"a": 1}`, local: `{"z": 0, "a": 1}`, remote: `{"a": 1, "m": 5}`,
+			want: `{"z":0,"a":1,"m":5}` + "\n",
+		},
+		{
+			name: "key deleted on one side",
+			base: `{"a": 1, "b": 2}`, local: `{"a": 1}`, remote: `{"a": 3, "b": 2}`,
+			want: `{"a":3}` + "\n",
+		},
+		{
+			name: "key deleted on both sides",
+			base: `{"a": 1, "b": 2}`, local: `{"a": 1}`, remote: `{"a": 1}`,
+			want: `{"a":1}` + "\n",
+		},
+		{
+			name: "deletion against edit",
+			base: `{"a": 1, "b": 2}`, local: `{"a": 1}`, remote: `{"a": 1, "b": 3}`,
+			conflicts: []string{"/b"},
+		},
+		{
+			name: "conflicting edits",
+			base: `{"a": {"x/y": 1, "t~": 1}}`, local: `{"a": {"x/y": 2, "t~": 2}}`, remote: `{"a": {"x/y": 3, "t~": 3}}`,
+			conflicts: []string{"/a/x~1y", "/a/t~0"},
+		},
+		{
+			name: "conflicting documents",
+			base: `[1]`, local: `[2]`, remote: `[3]`,
+			conflicts: []string{"/"},
+		},
+		{
+			name: "null differs from a missing key",
+			base: `{"a": 1}`, local: `{"a": 1, "b": null}`, remote: `{"a": 2}`,
+			want: `{"a":2,"b":null}` + "\n",
+		},
+		{
+			name: "escaped slash",
+			base: `{"url": "a\/b", "n": 1}`, local: `{"url": "a\/b", "n": 2}`, remote: `{"url": "a/c", "n": 1}`,
+			want: `{"url":"a/c","n":2}` + "\n",
+		},
+		{
+			name: "numbers keep their text",
+			base: `{"big": 1e400, "f": 1.50, "n": 1}`, local: `{"big": 1e400, "f": 1.50, "n": 2}`, remote: `{"big": 1e400, "f": 1.50, "n": 1}`,
+			want: `{"big":1e400,"f":1.50,"n":2}` + "\n",
+		},
+		{
+			name: "large number is not a string",
+			base: `{"a": 1}`, local: `{"a": 1e400}`, remote: `{"a": 1}`,
+			want: `{"a":1e400}` + "\n",
+		},
+		{
+			name: "one line with trailing newline stays on one line",
+			base: "{\"a\": 1}\n", local: "{\"a\": 2}\n", remote: "{\"a\": 1, \"b\": true}\n",
+			want: `{"a":2,"b":true}` + "\n",
+		},
+		{
+			name:   "tab indentation",
+			base:   "{\n\t\"a\": [1, 2]\n}\n",
+			local:  "{\n\t\"a\": [1, 2, 3]\n}\n",
+			remote: "{\n\t\"a\": [1, 2],\n\t\"b\": \"<&>\"\n}\n",
+			want:   "{\n\t\"a\": [\n\t\t1,\n\t\t2,\n\t\t3\n\t],\n\t\"b\": \"<&>\"\n}\n",
+		},
+		{
+			name: "no common ancestor",
+			base: "", local: `{"a": 1}`, remote: `{"b": 2}`,
+			want: `{"a":1,"b":2}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := JSON([]byte(tt.base), []byte(tt.local), []byte(tt.remote))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Conflicts, tt.conflicts) {
+				t.Fatalf("conflicts %q, want %q", result.Conflicts, tt.conflicts)
+			}
+			if tt.conflicts == nil && string(result.Merged) != tt.want {
+				t.Fatalf("got\n%s\nwant\n%s", result.Merged, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	for _, doc := range []string{`{"a": }`, `{"a": 1} {"b": 2}`, `[1,]`, `{'a': 1}`} {
+		if _, err := JSON([]byte(`{}`), []byte(doc), []byte(`{}`)); err == nil {
+			t.Errorf("%s: merged an invalid document", doc)
+		}
+	}
+}
+
+func TestJSONIndent(t *testing.T) {
+	tests := []struct {
+		doc, want string
+	}{
+		{`{"a": 1}`, ""},
+		{"{\"a\": 1}\n", ""},
+		{"{\"a\": 1}\r\n", ""},
+		{"{\n  \"a\": 1\n}\n", "  "},
+		{"{\n    \"a\": 1\n}", "    "},
+		{"{\n\t\"a\": 1\n}\n", "\t"},
+		{"{\n\n  \"a\": 1\n}\n", "  "},
+		{"{\n\"a\": 1\n}\n", "  "},
+	}
+	for _, tt := range tests {
+		if got := jsonIndent([]byte(tt.doc)); got != tt.want {
+			t.Errorf("jsonIndent(%q) = %q, want %q", tt.doc, got, tt.want)
+		}
+	}
+}
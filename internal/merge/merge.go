@@ -0,0 +1,34 @@
+// Package merge combines concurrent edits of a file with a three-way merge
+// against their common ancestor.
+package merge
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// Result is the outcome of a merge. When Conflicts is empty, Merged is the
+// cleanly merged content. Otherwise Conflicts names where the edits clash,
+// and Merged, if set, shows both sides with conflict markers.
+type Result struct {
+	Merged    []byte
+	Conflicts []string
+}
+
+func (r *Result) Clean() bool {
+	return len(r.Conflicts) == 0
+}
+
+// IsText reports whether content of the given type can be merged as text.
+func IsText(contentType string, data []byte) bool {
+	return isTextType(contentType) && !bytes.ContainsRune(data, 0) && utf8.Valid(data)
+}
+
+// isTextType reports whether a media type holds text, such as text/plain,
+// application/json or application/xml.
+func isTextType(contentType string) bool {
+	media := mediaType(contentType)
+	return strings.HasPrefix(media, "text/") || strings.Contains(media, "json") ||
+		strings.Contains(media, "yaml") || strings.Contains(media, "xml")
+}
@@ -0,0 +1,113 @@
+package merge
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Driver merges two edits of a document with their common ancestor.
+type Driver interface {
+	Merge(base, local, remote []byte) (*Result, error)
+}
+
+// DriverFunc adapts a function to a Driver.
+type DriverFunc func(base, local, remote []byte) (*Result, error)
+
+func (f DriverFunc) Merge(base, local, remote []byte) (*Result, error) {
+	return f(base, local, remote)
+}
+
+// Registry picks a merge driver for a file by its extension, then by its
+// content type. Text files without a driver of their own, including XML
+// and other text-like application types, are merged line by line.
+type Registry struct {
+	mu           sync.RWMutex
+	extensions   map[string]Driver
+	contentTypes map[string]Driver
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		extensions:   make(map[string]Driver),
+		contentTypes: make(map[string]Driver),
+	}
+}
+
+// RegisterExtension sets the driver for file names ending in ext, such as
+// ".json".
+func (r *Registry) RegisterExtension(ext string, driver Driver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extensions[strings.ToLower(ext)] = driver
+}
+
+// RegisterContentType sets the driver for a media type, such as
+// "application/json". Parameters like charset are ignored.
+func (r *Registry) RegisterContentType(contentType string, driver Driver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contentTypes[mediaType(contentType)] = driver
+}
+
+// Lookup returns the driver for a file, or nil when it cannot be merged.
+func (r *Registry) Lookup(name, contentType string) Driver {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if driver, ok := r.extensions[strings.ToLower(path.Ext(name))]; ok {
+		return driver
+	}
+	if driver, ok := r.contentTypes[mediaType(contentType)]; ok {
+		return driver
+	}
+	if isTextType(contentType) {
+		return TextDriver
+	}
+	return nil
+}
+
+func mediaType(contentType string) string {
+	if media, _, err := mime.ParseMediaType(contentType); err == nil {
+		return media
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+var (
+	TextDriver = DriverFunc(func(base, local, remote []byte) (*Result, error) {
+		return Text(base, local, remote), nil
+	})
+	JSONDriver = DriverFunc(JSON)
+	YAMLDriver = DriverFunc(YAML)
+)
+
+// Default is the registry used by the sync service. Register custom
+// drivers on it at startup.
+var Default = NewRegistry()
+
+func init() {
+	Default.RegisterExtension(".json", JSONDriver)
+	Default.RegisterContentType("application/json", JSONDriver)
+	Default.RegisterExtension(".yaml", YAMLDriver)
+	Default.RegisterExtension(".yml", YAMLDriver)
+	Default.RegisterContentType("application/yaml", YAMLDriver)
+	Default.RegisterContentType("application/x-yaml", YAMLDriver)
+	Default.RegisterContentType("text/yaml", YAMLDriver)
+}
+
+// RegisterExtension sets a driver on the Default registry.
+func RegisterExtension(ext string, driver Driver) {
+	Default.RegisterExtension(ext, driver)
+}
+
+// RegisterContentType sets a driver on the Default registry.
+func RegisterContentType(contentType string, driver Driver) {
+	Default.RegisterContentType(contentType, driver)
+}
+
+// Lookup finds a driver on the Default registry.
+func Lookup(name, contentType string) Driver {
+	return Default.Lookup(name, contentType)
+}
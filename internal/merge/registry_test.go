@@ -0,0 +1,69 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	custom := DriverFunc(func(base, local, remote []byte) (*Result, error) { return &Result{}, nil })
+	registry := NewRegistry()
+	registry.RegisterExtension(".json", JSONDriver)
+	registry.RegisterContentType("application/json", JSONDriver)
+	registry.RegisterExtension(".CSV", custom)
+
+	tests := []struct {
+		name, file, contentType string
+		want                    Driver
+	}{
+		{"extension", "config.json", "application/octet-stream", JSONDriver},
+		{"extension is case insensitive", "Config.JSON", "", JSONDriver},
+		{"registered extension is case insensitive", "data.csv", "text/csv", custom},
+		{"extension wins over content type", "data.csv", "application/json", custom},
+		{"content type", "config", "application/json", JSONDriver},
+		{"content type with parameters", "config", "application/json; charset=utf-8", JSONDriver},
+		{"plain text", "notes.txt", "text/plain", TextDriver},
+		{"xml", "layout.xml", "application/xml", TextDriver},
+		{"xml with parameters", "feed", "application/atom+xml; charset=utf-8", TextDriver},
+		{"json type without a driver", "doc", "application/ld+json", TextDriver},
+		{"binary", "photo.png", "image/png", nil},
+		{"unknown", "blob", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := registry.Lookup(tt.file, tt.contentType)
+			if !sameDriver(got, tt.want) {
+				t.Fatalf("Lookup(%q, %q) returned the wrong driver", tt.file, tt.contentType)
+			}
+		})
+	}
+}
+
+func sameDriver(a, b Driver) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+func TestIsText(t *testing.T) {
+	tests := []struct {
+		contentType string
+		data        string
+		want        bool
+	}{
+		{"text/plain", "hello", true},
+		{"application/xml", "<a/>", true},
+		{"application/json; charset=utf-8", "{}", true},
+		{"application/x-yaml", "a: 1", true},
+		{"text/plain", "nul\x00byte", false},
+		{"text/plain", "\xff\xfe", false},
+		{"image/png", "hello", false},
+	}
+	for _, tt := range tests {
+		if got := IsText(tt.contentType, []byte(tt.data)); got != tt.want {
+			t.Errorf("IsText(%q, %q) = %v, want %v", tt.contentType, tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package merge
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeNodes merges the documents of three YAML trees key by key. Mappings
+// are merged recursively; any other value, including whole sequences, is
+// taken from the side that changed it. Paths changed differently on both
+// sides are reported as JSON pointers.
+func mergeNodes(base, local, remote *yaml.Node) (*yaml.Node, []string) {
+	var conflicts []string
+	merged := mergeValue("", content(base), content(local), content(remote), &conflicts)
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+	if local != nil {
+		doc.HeadComment, doc.LineComment, doc.FootComment = local.HeadComment, local.LineComment, local.FootComment
+	}
+	if merged != nil {
+		doc.Content = []*yaml.Node{merged}
+	}
+	return doc, nil
+}
+
+// content returns the value inside a document node. Empty documents have
+// none.
+func content(doc *yaml.Node) *yaml.Node {
+	if doc == nil || len(doc.Content) == 0 {
+		return nil
+	}
+	return resolveAlias(doc.Content[0])
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// mergeValue merges one value. A nil node means the key is absent.
+func mergeValue(path string, base, local, remote *yaml.Node, conflicts *[]string) *yaml.Node {
+	switch {
+	case equalNodes(local, remote), equalNodes(base, remote):
+		return local
+	case equalNodes(base, local):
+		return remote
+	}
+
+	if isMapping(local) && isMapping(remote) && (base == nil || isMapping(base)) {
+		return mergeMappings(path, base, local, remote, conflicts)
+	}
+
+	if path == "" {
+		path = "/"
+	}
+	*conflicts = append(*conflicts, path)
+	return nil
+}
+
+func mergeMappings(path string, base, local, remote *yaml.Node, conflicts *[]string) *yaml.Node {
+	baseKeys, localKeys, remoteKeys := mappingIndex(base), mappingIndex(local), mappingIndex(remote)
+
+	merged := *local
+	merged.Content = nil
+
+	add := func(key *yaml.Node, value *yaml.Node) {
+		if value != nil {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+
+	// Keys keep the local order, followed by keys only remote has.
+	for i := 0; i+1 < len(local.Content); i += 2 {
+		key := local.Content[i]
+		value := mergeValue(path+"/"+escapePointer(key.Value),
+			baseKeys[key.Value], resolveAlias(local.Content[i+1]), remoteKeys[key.Value], conflicts)
+		add(key, value)
+	}
+	for i := 0; i+1 < len(remote.Content); i += 2 {
+		key := remote.Content[i]
+		if _, ok := localKeys[key.Value]; ok {
+			continue
+		}
+		value := mergeValue(path+"/"+escapePointer(key.Value),
+			baseKeys[key.Value], nil, resolveAlias(remote.Content[i+1]), conflicts)
+		add(key, value)
+	}
+
+	return &merged
+}
+
+func isMapping(node *yaml.Node) bool {
+	return node != nil && node.Kind == yaml.MappingNode
+}
+
+func mappingIndex(node *yaml.Node) map[string]*yaml.Node {
+	index := make(map[string]*yaml.Node)
+	if !isMapping(node) {
+		return index
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		index[node.Content[i].Value] = resolveAlias(node.Content[i+1])
+	}
+	return index
+}
+
+// equalNodes compares values, ignoring comments, styles and positions.
+func equalNodes(a, b *yaml.Node) bool {
+	a, b = resolveAlias(a), resolveAlias(b)
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind || a.ShortTag() != b.ShortTag() || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		return a.Value == b.Value
+	}
+
+	if a.Kind == yaml.MappingNode {
+		bKeys := mappingIndex(b)
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			other, ok := bKeys[a.Content[i].Value]
+			if !ok || !equalNodes(a.Content[i+1], other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range a.Content {
+		if !equalNodes(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// parseDocument parses a YAML document. Empty input is an empty
+// document.
+func parseDocument(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
@@ -0,0 +1,131 @@
+package merge
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	markerLocal  = "<<<<<<< local\n"
+	markerSplit  = "=======\n"
+	markerRemote = ">>>>>>> remote\n"
+)
+
+// Text merges two edits of a text file line by line. Hunks changed on only
+// one side, or changed the same way on both, merge cleanly. Conflicting
+// hunks are written with conflict markers and reported by line number.
+func Text(base, local, remote []byte) *Result {
+	o, a, b := splitLines(base), splitLines(local), splitLines(remote)
+	matchA := matchMap(commonLines(o, a))
+	matchB := matchMap(commonLines(o, b))
+
+	var out bytes.Buffer
+	result := &Result{}
+
+	emit := func(lines []string) {
+		for _, line := range lines {
+			out.WriteString(line)
+		}
+	}
+
+	// resolve writes one unstable hunk: base o[oi:oj] edited into a[ai:aj]
+	// and b[bi:bj].
+	resolve := func(oi, oj, ai, aj, bi, bj int) {
+		baseHunk, localHunk, remoteHunk := o[oi:oj], a[ai:aj], b[bi:bj]
+		switch {
+		case equalLines(localHunk, baseHunk):
+			emit(remoteHunk)
+		case equalLines(remoteHunk, baseHunk), equalLines(localHunk, remoteHunk):
+			emit(localHunk)
+		default:
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("line %d", countLines(out.Bytes())+1))
+			out.WriteString(markerLocal)
+			emitTerminated(&out, localHunk)
+			out.WriteString(markerSplit)
+			emitTerminated(&out, remoteHunk)
+			out.WriteString(markerRemote)
+		}
+	}
+
+	oi, ai, bi := 0, 0, 0
+	for {
+		// Find the next base line kept by both sides.
+		next := -1
+		for i := oi; i < len(o); i++ {
+			ja, okA := matchA[i]
+			jb, okB := matchB[i]
+			if okA && okB && ja >= ai && jb >= bi {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			if oi < len(o) || ai < len(a) || bi < len(b) {
+				resolve(oi, len(o), ai, len(a), bi, len(b))
+			}
+			break
+		}
+
+		ja, jb := matchA[next], matchB[next]
+		if next > oi || ja > ai || jb > bi {
+			resolve(oi, next, ai, ja, bi, jb)
+		}
+		out.WriteString(o[next])
+		oi, ai, bi = next+1, ja+1, jb+1
+	}
+
+	result.Merged = out.Bytes()
+	return result
+}
+
+// splitLines splits data after every newline, so joining the lines gives
+// the data back.
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, string(data))
+			break
+		}
+		lines = append(lines, string(data[:i+1]))
+		data = data[i+1:]
+	}
+	return lines
+}
+
+func matchMap(matches [][2]int) map[int]int {
+	m := make(map[int]int, len(matches))
+	for _, match := range matches {
+		m[match[0]] = match[1]
+	}
+	return m
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// emitTerminated writes lines, ending the last one with a newline so that
+// a marker that follows starts on its own line.
+func emitTerminated(out *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+	if n := len(lines); n > 0 && lines[n-1][len(lines[n-1])-1] != '\n' {
+		out.WriteByte('\n')
+	}
+}
+
+func countLines(data []byte) int {
+	return bytes.Count(data, []byte{'\n'})
+}
@@ -0,0 +1,141 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		want                string
+		conflicts           []string
+	}{
+		{
+			name: "unchanged",
+			base: "a\nb\nc\n", local: "a\nb\nc\n", remote: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "local edit",
+			base: "a\nb\nc\n", local: "a\nB\nc\n", remote: "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "remote edit",
+			base: "a\nb\nc\n", local: "a\nb\nc\n", remote: "a\nb\nC\n",
+			want: "a\nb\nC\n",
+		},
+		{
+			name: "edits to different lines",
+			base: "a\nb\nc\nd\ne\n", local: "A\nb\nc\nd\ne\n", remote: "a\nb\nc\nd\nE\n",
+			want: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "same edit on both sides",
+			base: "a\nb\nc\n", local: "a\nX\nc\n", remote: "a\nX\nc\n",
+			want: "a\nX\nc\n",
+		},
+		{
+			name: "insertion and deletion",
+			base: "a\nb\nc\nd\n", local: "a\nnew\nb\nc\nd\n", remote: "a\nb\nc\n",
+			want: "a\nnew\nb\nc\n",
+		},
+		{
+			name: "overlapping edits",
+			base: "a\nb\nc\n", local: "a\nlocal\nc\n", remote: "a\nremote\nc\n",
+			want:      "a\n<<<<<<< local\nlocal\n=======\nremote\n>>>>>>> remote\nc\n",
+			conflicts: []string{"line 2"},
+		},
+		{
+			name: "edit against deletion",
+			base: "a\nb\nc\n", local: "a\nB\nc\n", remote: "a\nc\n",
+			want:      "a\n<<<<<<< local\nB\n=======\n>>>>>>> remote\nc\n",
+			conflicts: []string{"line 2"},
+		},
+		{
+			name: "append on one side",
+			base: "a\nb\n", local: "a\nb\n", remote: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "different appends at end of file",
+			base: "a\n", local: "a\nlocal\n", remote: "a\nremote\n",
+			want:      "a\n<<<<<<< local\nlocal\n=======\nremote\n>>>>>>> remote\n",
+			conflicts: []string{"line 2"},
+		},
+		{
+			name: "same append at end of file",
+			base: "a\n", local: "a\nb\n", remote: "a\nb\n",
+			want: "a\nb\n",
+		},
+		{
+			name: "empty base",
+			base: "", local: "a\n", remote: "",
+			want: "a\n",
+		},
+		{
+			name: "base without trailing newline",
+			base: "a\nm\nb", local: "A\nm\nb", remote: "a\nm\nB",
+			want: "A\nm\nB",
+		},
+		{
+			name: "newline added at end of file",
+			base: "a\nm\nb", local: "a\nm\nb\n", remote: "A\nm\nb",
+			want: "A\nm\nb\n",
+		},
+		{
+			name: "append after last line without newline",
+			base: "a\nm\nb", local: "a\nm\nb\nc", remote: "A\nm\nb",
+			want: "A\nm\nb\nc",
+		},
+		{
+			// As in diff3, edits to adjacent lines overlap.
+			name: "edits to adjacent lines",
+			base: "a\nb\n", local: "A\nb\n", remote: "a\nB\n",
+			want:      "<<<<<<< local\nA\nb\n=======\na\nB\n>>>>>>> remote\n",
+			conflicts: []string{"line 1"},
+		},
+		{
+			name: "conflict without trailing newline",
+			base: "a\nb", local: "a\nlocal", remote: "a\nremote",
+			want:      "a\n<<<<<<< local\nlocal\n=======\nremote\n>>>>>>> remote\n",
+			conflicts: []string{"line 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Text([]byte(tt.base), []byte(tt.local), []byte(tt.remote))
+			if string(result.Merged) != tt.want {
+				t.Errorf("merged:\n%q\nwant:\n%q", result.Merged, tt.want)
+			}
+			if !reflect.DeepEqual(result.Conflicts, tt.conflicts) {
+				t.Errorf("conflicts %v, want %v", result.Conflicts, tt.conflicts)
+			}
+			if result.Clean() != (len(tt.conflicts) == 0) {
+				t.Errorf("Clean() = %v", result.Clean())
+			}
+		})
+	}
+}
+
+// Merging is symmetric up to which side is called local.
+func TestTextSymmetric(t *testing.T) {
+	base := "one\ntwo\nthree\nfour\nfive\n"
+	local := "zero\none\ntwo\nthree\nfive\n"
+	remote := "one\nTWO\nthree\nfour\nfive\nsix\n"
+
+	ab := Text([]byte(base), []byte(local), []byte(remote))
+	ba := Text([]byte(base), []byte(remote), []byte(local))
+	if !ab.Clean() || !ba.Clean() {
+		t.Fatalf("unexpected conflicts: %v, %v", ab.Conflicts, ba.Conflicts)
+	}
+	if string(ab.Merged) != string(ba.Merged) {
+		t.Fatalf("merges differ:\n%q\n%q", ab.Merged, ba.Merged)
+	}
+	if want := "zero\none\nTWO\nthree\nfive\nsix\n"; string(ab.Merged) != want {
+		t.Fatalf("merged %q, want %q", ab.Merged, want)
+	}
+}
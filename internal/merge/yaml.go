@@ -0,0 +1,39 @@
+package merge
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML merges YAML documents key by key. The output keeps the order and
+// comments of the local document.
+func YAML(base, local, remote []byte) (*Result, error) {
+	docs := make([]*yaml.Node, 3)
+	for i, data := range [][]byte{base, local, remote} {
+		doc, err := parseDocument(data)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+
+	merged, conflicts := mergeNodes(docs[0], docs[1], docs[2])
+	if len(conflicts) > 0 {
+		return &Result{Conflicts: conflicts}, nil
+	}
+	if content(merged) == nil {
+		return &Result{Merged: []byte{}}, nil
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(merged); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return &Result{Merged: out.Bytes()}, nil
+}
@@ -0,0 +1,73 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestYAML(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		want                string
+		conflicts           []string
+	}{
+		{
+			name: "edits to different keys",
+			base: "a: 1\nb: 2\n", local: "a: 10\nb: 2\n", remote: "a: 1\nb: 20\n",
+			want: "a: 10\nb: 20\n",
+		},
+		{
+			name:   "nested edits keep local comments",
+			base:   "db:\n  host: a\n  port: 1\n",
+			local:  "# settings\ndb:\n  host: b # moved\n  port: 1\n",
+			remote: "db:\n  host: a\n  port: 2\n",
+			want:   "# settings\ndb:\n  host: b # moved\n  port: 2\n",
+		},
+		{
+			name: "keys only remote added come last",
+			base: "a: 1\n", local: "z: 0\na: 1\n", remote: "a: 1\nm: 5\n",
+			want: "z: 0\na: 1\nm: 5\n",
+		},
+		{
+			name: "key deleted on one side",
+			base: "a: 1\nb: 2\n", local: "a: 1\n", remote: "a: 3\nb: 2\n",
+			want: "a: 3\n",
+		},
+		{
+			name: "deletion against edit",
+			base: "a: 1\nb: 2\n", local: "a: 1\n", remote: "a: 1\nb: 3\n",
+			conflicts: []string{"/b"},
+		},
+		{
+			name: "conflicting edits",
+			base: "a:\n  x/y: 1\n", local: "a:\n  x/y: 2\n", remote: "a:\n  x/y: 3\n",
+			conflicts: []string{"/a/x~1y"},
+		},
+		{
+			name: "conflicting documents",
+			base: "- 1\n", local: "- 2\n", remote: "- 3\n",
+			conflicts: []string{"/"},
+		},
+		{
+			name: "everything deleted",
+			base: "a: 1\n", local: "", remote: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := YAML([]byte(tt.base), []byte(tt.local), []byte(tt.remote))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Conflicts, tt.conflicts) {
+				t.Fatalf("conflicts %q, want %q", result.Conflicts, tt.conflicts)
+			}
+			if tt.conflicts == nil && string(result.Merged) != tt.want {
+				t.Fatalf("got\n%s\nwant\n%s", result.Merged, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+)
+
+// LocalFile is a file a sync agent has synced, as it was on disk when it
+// last matched the server. These models live in the agent's own state
+// database, not the servers'.
+type LocalFile struct {
+	// Path is slash-separated and relative to the sync root.
+	Path      string `gorm:"primaryKey" json:"path"`
+	FileID    string `gorm:"index;not null" json:"file_id"`
+	VersionID string `json:"version_id"`
+	// Vector is the version vector of VersionID, sent as the base of the
+	// next upload.
+	Vector  vclock.Vector `gorm:"serializer:json" json:"vector,omitempty"`
+	Hash    string        `gorm:"index" json:"hash"`
+	Size    int64         `json:"size"`
+	ModTime time.Time     `json:"mod_time"`
+	// Device and Inode identify the file on disk, to follow it when it is
+	// renamed. They are zero where the system does not expose them.
+	Device    int64     `json:"device"`
+	Inode     int64     `json:"inode"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AgentSetting is a value a sync agent keeps between runs, such as its
+// device ID and journal cursor.
+type AgentSetting struct {
+	Key   string `gorm:"primaryKey" json:"key"`
+	Value string `gorm:"not null" json:"value"`
+}
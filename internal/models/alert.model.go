@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DeviceAlertPaused   = "paused"
+	DeviceAlertApproved = "approved"
+	DeviceAlertRejected = "rejected"
+)
+
+// DeviceAlert is raised when a device's changes look anomalous. While it
+// is paused, changes from the device are held instead of propagated.
+type DeviceAlert struct {
+	ID         string     `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null;index;index:idx_device_alert_user_paused,unique,where:status = 'paused',priority:1" json:"user_id"`
+	DeviceID   string     `gorm:"not null;index:idx_device_alert_user_paused,unique,where:status = 'paused',priority:2" json:"device_id"`
+	Kind       string     `gorm:"not null" json:"kind"`
+	Reason     string     `json:"reason"`
+	Status     string     `gorm:"not null" json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+func (da *DeviceAlert) BeforeCreate(tx *gorm.DB) error {
+	if da.ID == "" {
+		da.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// HeldChange is a change kept back while its device is paused.
+type HeldChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AlertID   string    `gorm:"type:uuid;not null;index" json:"alert_id"`
+	Payload   []byte    `gorm:"not null" json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Kinds of DeviceActivity.
+const (
+	DeviceActivityModification = "modification"
+	DeviceActivityDeletion     = "deletion"
+	DeviceActivityEntropyJump  = "entropy_jump"
+)
+
+// DeviceActivity is one change of a device within the anomaly detector's
+// window. Every sync instance counts the same rows, so the thresholds hold
+// however a device's changes are split between them.
+type DeviceActivity struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     string    `gorm:"type:uuid;not null;index:idx_device_activity,priority:1" json:"user_id"`
+	DeviceID   string    `gorm:"not null;index:idx_device_activity,priority:2" json:"device_id"`
+	Kind       string    `gorm:"not null" json:"kind"`
+	OccurredAt time.Time `gorm:"not null;index:idx_device_activity,priority:3" json:"occurred_at"`
+}
+
+// FileEntropy is the last entropy seen for a file, keyed by path, to tell
+// when a change makes it jump.
+type FileEntropy struct {
+	UserID  string    `gorm:"primaryKey;type:uuid;index:idx_file_entropy_seen,priority:1" json:"user_id"`
+	Path    string    `gorm:"primaryKey" json:"path"`
+	Entropy float64   `json:"entropy"`
+	SeenAt  time.Time `gorm:"not null;index:idx_file_entropy_seen,priority:2" json:"seen_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type Chunk struct {
+	Hash       string    `gorm:"primaryKey" json:"hash"`
+	Size       int64     `gorm:"not null" json:"size"`
+	StoredSize int64     `json:"stored_size"`
+	Codec      string    `gorm:"not null;default:identity" json:"codec"`
+	S3Key      string    `gorm:"not null" json:"s3_key"`
+	LastUsedAt time.Time `gorm:"index" json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ChunkOwner records that a user has uploaded the content of a chunk.
+// Deduplicated uploads may only refer to chunks their user owns, so the
+// store cannot be probed for other users' content.
+type ChunkOwner struct {
+	ChunkHash string    `gorm:"primaryKey" json:"chunk_hash"`
+	UserID    string    `gorm:"primaryKey;type:uuid;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VersionChunk struct {
+	VersionID string `gorm:"primaryKey;type:uuid" json:"version_id"`
+	Index     int    `gorm:"primaryKey" json:"index"`
+	ChunkHash string `gorm:"not null;index" json:"chunk_hash"`
+	Chunk     Chunk  `gorm:"foreignKey:ChunkHash" json:"-"`
+	Offset    int64  `json:"offset"`
+	Size      int64  `json:"size"`
+}
+
+// DataKey is the wrapped data key of a stored object encrypted at rest.
+// Rotating the wrapping key rewrites only this row, never the object.
+type DataKey struct {
+	ObjectKey string `gorm:"primaryKey" json:"object_key"`
+	// BlobKey is where the encrypted object is stored. Every write goes to
+	// a new blob, so a row always names the blob sealed with its key. It
+	// is empty for objects stored under their own key by older versions.
+	BlobKey    string    `json:"blob_key"`
+	KeyID      string    `gorm:"not null;index" json:"key_id"`
+	WrappedKey []byte    `gorm:"not null" json:"-"`
+	Size       int64     `json:"size"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
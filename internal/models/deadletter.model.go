@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DeadLetterPending   = "pending"
+	DeadLetterReplayed  = "replayed"
+	DeadLetterDiscarded = "discarded"
+)
+
+// DeadLetter is an event a consumer group gave up on, kept until an
+// operator replays or discards it.
+type DeadLetter struct {
+	ID         string            `gorm:"primaryKey;type:uuid" json:"id"`
+	EventID    string            `gorm:"index" json:"event_id,omitempty"`
+	Topic      string            `gorm:"not null;uniqueIndex:idx_dead_letter_source,priority:1" json:"topic"`
+	Group      string            `gorm:"not null;uniqueIndex:idx_dead_letter_source,priority:2" json:"group"`
+	Partition  int               `gorm:"not null;uniqueIndex:idx_dead_letter_source,priority:3" json:"partition"`
+	Offset     int64             `gorm:"not null;uniqueIndex:idx_dead_letter_source,priority:4" json:"offset"`
+	Key        string            `json:"key"`
+	Payload    []byte            `json:"payload"`
+	Headers    map[string]string `gorm:"type:jsonb;serializer:json" json:"headers"`
+	Attempts   int               `json:"attempts"`
+	Error      string            `json:"error"`
+	FailedAt   time.Time         `gorm:"not null" json:"failed_at"`
+	Status     string            `gorm:"not null;index" json:"status"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func (d *DeadLetter) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -3,33 +3,58 @@ package models
 import (
 	"time"
 
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type File struct {
-	ID          string        `gorm:"primaryKey;type:uuid" json:"id"`
-	Name        string        `gorm:"not null" json:"name"`
-	Path        string        `gorm:"not null" json:"path"`
-	Size        int64         `json:"size"`
-	ContentType string        `json:"content_type"`
-	OwnerID     string        `gorm:"type:uuid;not null" json:"owner_id"`
-	Owner       User          `gorm:"foreignKey:OwnerID" json:"owner"`
-	Versions    []FileVersion `gorm:"foreignKey:FileID" json:"versions"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+	ID               string         `gorm:"primaryKey;type:uuid" json:"id"`
+	Name             string         `gorm:"not null" json:"name"`
+	Path             string         `gorm:"not null" json:"path"`
+	Size             int64          `json:"size"`
+	ContentType      string         `json:"content_type"`
+	Encrypted        bool           `gorm:"not null;default:false" json:"encrypted"`
+	EncryptedName    []byte         `json:"encrypted_name,omitempty"`
+	OwnerID          string         `gorm:"type:uuid;not null" json:"owner_id"`
+	Owner            User           `gorm:"foreignKey:OwnerID" json:"owner"`
+	Versions         []FileVersion  `gorm:"foreignKey:FileID" json:"versions"`
+	CurrentVersionID *string        `gorm:"type:uuid" json:"current_version_id,omitempty"`
+	Keys             []FileKey      `gorm:"foreignKey:FileID" json:"-"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// FileKey holds the data key of an end-to-end encrypted file, wrapped with
+// the public key of one recipient. The server cannot unwrap it.
+type FileKey struct {
+	FileID      string    `gorm:"primaryKey;type:uuid" json:"file_id"`
+	RecipientID string    `gorm:"primaryKey;type:uuid" json:"recipient_id"`
+	WrappedKey  []byte    `gorm:"not null" json:"wrapped_key"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type FileVersion struct {
-	ID         string    `gorm:"primaryKey;type:uuid" json:"id"`
-	FileID     string    `gorm:"type:uuid;not null" json:"file_id"`
-	File       File      `gorm:"foreignKey:FileID" json:"-"`
-	VersionNum int       `gorm:"not null" json:"version_num"`
-	Hash       string    `gorm:"not null" json:"hash"`
-	Size       int64     `json:"size"`
-	S3Key      string    `gorm:"not null" json:"s3_key"`
-	DeviceID   string    `json:"device_id"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID           string         `gorm:"primaryKey;type:uuid" json:"id"`
+	FileID       string         `gorm:"type:uuid;not null;uniqueIndex:idx_file_version_num,priority:1" json:"file_id"`
+	File         File           `gorm:"foreignKey:FileID" json:"-"`
+	VersionNum   int            `gorm:"not null;uniqueIndex:idx_file_version_num,priority:2" json:"version_num"`
+	Hash         string         `gorm:"not null" json:"hash"`
+	Size         int64          `json:"size"`
+	StoredSize   int64          `json:"stored_size"`
+	Codec        string         `gorm:"not null;default:identity" json:"codec"`
+	S3Key        string         `gorm:"not null" json:"s3_key"`
+	Chunks       []VersionChunk `gorm:"foreignKey:VersionID" json:"chunks,omitempty"`
+	DeviceID     string         `json:"device_id"`
+	Pinned       bool           `gorm:"not null;default:false" json:"pinned"`
+	SupersededBy *string        `gorm:"type:uuid;index" json:"superseded_by,omitempty"`
+	Vector       vclock.Vector  `gorm:"type:jsonb;serializer:json" json:"vector,omitempty"`
+	// ConflictsWith is the version that was current when this one was
+	// added without having seen it.
+	ConflictsWith *string   `gorm:"type:uuid" json:"conflicts_with,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 func (f *File) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ChangeEntry is one change in a user's change journal. Seq numbers the
+// changes of each user without gaps, in commit order. Alerts about a
+// paused device are journaled too, without a file.
+type ChangeEntry struct {
+	UserID     string    `gorm:"primaryKey;type:uuid" json:"user_id"`
+	Seq        int64     `gorm:"primaryKey;autoIncrement:false" json:"seq"`
+	FileID     string    `gorm:"type:uuid" json:"file_id,omitempty"`
+	FilePath   string    `gorm:"not null" json:"file_path"`
+	ChangeType string    `gorm:"not null" json:"change_type"`
+	VersionID  string    `json:"version_id,omitempty"`
+	DeviceID   string    `json:"device_id"`
+	AlertID    string    `json:"alert_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// JournalHead holds the last sequence number used in a user's journal.
+type JournalHead struct {
+	UserID string `gorm:"primaryKey;type:uuid" json:"user_id"`
+	Seq    int64  `gorm:"not null" json:"seq"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// OutboxEvent is an event written in the same transaction as the change it
+// describes, waiting to be relayed to the event bus. ID orders the events.
+type OutboxEvent struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	EventID       string            `gorm:"type:uuid;not null;uniqueIndex" json:"event_id"`
+	Topic         string            `gorm:"not null" json:"topic"`
+	Key           string            `gorm:"not null" json:"key"`
+	Payload       []byte            `gorm:"not null" json:"payload"`
+	Headers       map[string]string `gorm:"type:jsonb;serializer:json" json:"headers,omitempty"`
+	Attempts      int               `gorm:"not null;default:0" json:"attempts"`
+	LastError     string            `json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	PublishedAt   *time.Time        `gorm:"index" json:"published_at,omitempty"`
+}
+
+// ProcessedEvent marks an event as handled, so a redelivered copy is
+// skipped.
+type ProcessedEvent struct {
+	EventID     string    `gorm:"primaryKey" json:"event_id"`
+	ProcessedAt time.Time `gorm:"not null;index" json:"processed_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RestoreJobCompleted = "completed"
+	RestoreJobFailed    = "failed"
+)
+
+// RestoreJob records a point-in-time restore of a folder.
+type RestoreJob struct {
+	ID           string     `gorm:"primaryKey;type:uuid" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	FolderPath   string     `gorm:"not null" json:"folder_path"`
+	RestoreTo    time.Time  `gorm:"not null" json:"restore_to"`
+	Status       string     `gorm:"not null" json:"status"`
+	FilesChanged int        `json:"files_changed"`
+	Error        string     `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+func (rj *RestoreJob) BeforeCreate(tx *gorm.DB) error {
+	if rj.ID == "" {
+		rj.ID = uuid.New().String()
+	}
+	return nil
+}
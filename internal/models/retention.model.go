@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RetentionScopeUser   = "user"
+	RetentionScopeOrg    = "org"
+	RetentionScopeFolder = "folder"
+)
+
+// RetentionPolicy decides which versions of a file are kept. A version is
+// kept when any of the rules keeps it. Folder policies are scoped to the
+// owner in ScopeID and apply to files whose path starts with FolderPath.
+type RetentionPolicy struct {
+	ID                 string    `gorm:"primaryKey;type:uuid" json:"id"`
+	Scope              string    `gorm:"not null;uniqueIndex:idx_retention_scope" json:"scope"`
+	ScopeID            string    `gorm:"not null;uniqueIndex:idx_retention_scope" json:"scope_id"`
+	FolderPath         string    `gorm:"not null;default:'';uniqueIndex:idx_retention_scope" json:"folder_path"`
+	KeepLast           int       `json:"keep_last"`
+	KeepHourlyForHours int       `json:"keep_hourly_for_hours"`
+	KeepDailyForDays   int       `json:"keep_daily_for_days"`
+	KeepAllForDays     int       `json:"keep_all_for_days"`
+	KeepForever        bool      `json:"keep_forever"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func (rp *RetentionPolicy) BeforeCreate(tx *gorm.DB) error {
+	if rp.ID == "" {
+		rp.ID = uuid.New().String()
+	}
+	return nil
+}
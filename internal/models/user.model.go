@@ -12,6 +12,7 @@ type User struct {
 	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
 	Username  string    `gorm:"uniqueIndex;not null" json:"username"`
 	Password  string    `gorm:"not null" json:"-"`
+	OrgID     string    `gorm:"index" json:"org_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -22,3 +23,14 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// UserKey is the public half of a user's end-to-end encryption key pair.
+// The private key never leaves the user's devices.
+type UserKey struct {
+	UserID    string    `gorm:"primaryKey;type:uuid" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	PublicKey []byte    `gorm:"not null" json:"public_key"`
+	Algorithm string    `gorm:"not null" json:"algorithm"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,64 @@
+// Package outbox stores events in the same transaction as the changes they
+// describe, so that the database and the event bus cannot drift apart. A
+// Relay publishes them once committed.
+package outbox
+
+import (
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueEvent adds a file change event to the outbox within tx, keyed by
+// user.
+func EnqueueEvent(tx *gorm.DB, env *proto.EventEnvelope) error {
+	payload, headers, err := events.Encode(env)
+	if err != nil {
+		return err
+	}
+	return enqueue(tx, env.EventId, events.Topic, env.UserId, payload, headers)
+}
+
+// Enqueue adds an event to the outbox within tx and returns its event ID.
+func Enqueue(tx *gorm.DB, topic, key string, payload []byte, headers map[string]string) (string, error) {
+	eventID := uuid.New().String()
+	if err := enqueue(tx, eventID, topic, key, payload, headers); err != nil {
+		return "", err
+	}
+	return eventID, nil
+}
+
+func enqueue(tx *gorm.DB, eventID, topic, key string, payload []byte, headers map[string]string) error {
+	return tx.Create(&models.OutboxEvent{
+		EventID:       eventID,
+		Topic:         topic,
+		Key:           key,
+		Payload:       payload,
+		Headers:       headers,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// Claim marks an event as processed within tx. It returns false if the
+// event was processed before, in which case the caller skips it. Events
+// without an ID cannot be deduplicated and are always claimed.
+func Claim(tx *gorm.DB, eventID string) (bool, error) {
+	if eventID == "" {
+		return true, nil
+	}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedEvent{
+		EventID:     eventID,
+		ProcessedAt: time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,284 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	relayBatchSize = 100
+	// relayLockID is the advisory lock that lets one relay at a time
+	// claim events, which with the lease on claimed events keeps events of
+	// a key in order across instances.
+	relayLockID = 7246101
+	maxBackoff  = 5 * time.Minute
+	// relayLease is how long a claimed event is left to the relay that
+	// claimed it. Events still unmarked after that, because the relay
+	// stopped, are published again.
+	relayLease = time.Minute
+)
+
+// Relay publishes committed outbox events to the event bus in ID order.
+// An event that fails to publish is retried with backoff, and later events
+// with the same key wait for it.
+type Relay struct {
+	db        *gorm.DB
+	bus       utils.EventBus
+	retention time.Duration
+}
+
+// NewRelay returns a relay that deletes published events, and the marks of
+// processed ones, after the retention period.
+func NewRelay(db *gorm.DB, bus utils.EventBus, retention time.Duration) *Relay {
+	return &Relay{
+		db:        db,
+		bus:       bus,
+		retention: retention,
+	}
+}
+
+// Start relays on the given interval until the context is cancelled.
+func (r *Relay) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayOnce(ctx); err != nil {
+				log.Printf("Outbox relay failed: %v", err)
+			}
+
+			if time.Since(lastCleanup) > time.Hour {
+				if err := r.cleanup(ctx); err != nil {
+					log.Printf("Outbox cleanup failed: %v", err)
+				}
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+// RelayOnce publishes the pending events that are due and returns how many
+// were published. Events are claimed in a short transaction and published
+// outside it, so a slow bus holds up neither the lock nor the database.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		events, err := r.claim(ctx)
+		if err != nil || len(events) == 0 {
+			return published, err
+		}
+
+		n, err := r.publish(ctx, events)
+		published += n
+		if err != nil || len(events) < relayBatchSize {
+			return published, err
+		}
+	}
+}
+
+// claim leases up to a batch of due events, in ID order, whose keys have
+// no earlier event pending. A leased event is not due again until the
+// lease runs out, so other relays leave its key alone meanwhile. Nothing
+// is claimed while another relay is claiming.
+func (r *Relay) claim(ctx context.Context) ([]models.OutboxEvent, error) {
+	var (
+		claimed []models.OutboxEvent
+		ids     []uint
+	)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		now := time.Now()
+		// blocked holds the keys with an earlier event still pending.
+		blocked := make(map[string]bool)
+		var lastID uint
+		for len(claimed) < relayBatchSize {
+			var events []models.OutboxEvent
+			if err := tx.Where("published_at IS NULL AND id > ?", lastID).
+				Order("id ASC").
+				Limit(relayBatchSize).
+				Find(&events).Error; err != nil {
+				return err
+			}
+
+			for _, event := range events {
+				if len(claimed) == relayBatchSize {
+					break
+				}
+				if blocked[event.Key] {
+					continue
+				}
+				if event.NextAttemptAt.After(now) {
+					blocked[event.Key] = true
+					continue
+				}
+				claimed = append(claimed, event)
+				ids = append(ids, event.ID)
+			}
+
+			if len(events) < relayBatchSize {
+				break
+			}
+			lastID = events[len(events)-1].ID
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(relayLease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// publish sends claimed events, each key's in order and different keys at
+// once, and records the outcomes. After a failure the rest of its key's
+// events are released to wait for it. It returns how many were published;
+// only database errors are returned.
+func (r *Relay) publish(ctx context.Context, events []models.OutboxEvent) (int, error) {
+	byKey := make(map[string][]*models.OutboxEvent)
+	for i := range events {
+		byKey[events[i].Key] = append(byKey[events[i].Key], &events[i])
+	}
+
+	var (
+		mu       sync.Mutex
+		sent     []*models.OutboxEvent
+		failed   []*models.OutboxEvent
+		released []*models.OutboxEvent
+		wg       sync.WaitGroup
+	)
+	for _, keyEvents := range byKey {
+		wg.Add(1)
+		go func(keyEvents []*models.OutboxEvent) {
+			defer wg.Done()
+			for i, event := range keyEvents {
+				err := r.send(ctx, event)
+
+				mu.Lock()
+				if err != nil {
+					failed = append(failed, event)
+					released = append(released, keyEvents[i+1:]...)
+				} else {
+					sent = append(sent, event)
+				}
+				mu.Unlock()
+
+				if err != nil {
+					return
+				}
+			}
+		}(keyEvents)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(sent) > 0 {
+			if err := tx.Model(&models.OutboxEvent{}).
+				Where("id IN ?", eventIDs(sent)).
+				Update("published_at", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(released) > 0 {
+			if err := tx.Model(&models.OutboxEvent{}).
+				Where("id IN ?", eventIDs(released)).
+				Update("next_attempt_at", now).Error; err != nil {
+				return err
+			}
+		}
+		for _, event := range failed {
+			if err := tx.Model(event).Updates(map[string]interface{}{
+				"attempts":        event.Attempts,
+				"last_error":      event.LastError,
+				"next_attempt_at": now.Add(backoff(event.Attempts)),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(sent), nil
+}
+
+// send publishes one event, counting the attempt if it fails.
+func (r *Relay) send(ctx context.Context, event *models.OutboxEvent) error {
+	headers := make(map[string]string, len(event.Headers)+1)
+	for key, value := range event.Headers {
+		headers[key] = value
+	}
+	headers[utils.EventIDHeader] = event.EventID
+
+	err := r.bus.Publish(ctx, &utils.Message{
+		Topic:   event.Topic,
+		Key:     event.Key,
+		Value:   event.Payload,
+		Headers: headers,
+	})
+	if err != nil {
+		event.Attempts++
+		event.LastError = err.Error()
+		log.Printf("Failed to publish outbox event %s (attempt %d): %v", event.EventID, event.Attempts, err)
+	}
+	return err
+}
+
+func eventIDs(events []*models.OutboxEvent) []uint {
+	ids := make([]uint, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// cleanup deletes published events and processed marks past retention.
+func (r *Relay) cleanup(ctx context.Context) error {
+	cutoff := time.Now().Add(-r.retention)
+	if err := r.db.WithContext(ctx).
+		Where("published_at < ?", cutoff).
+		Delete(&models.OutboxEvent{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Where("processed_at < ?", cutoff).
+		Delete(&models.ProcessedEvent{}).Error
+}
+
+// backoff doubles the retry delay from a second up to maxBackoff.
+func backoff(attempts int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+func init() {
+	// SQLite has no advisory locks; the tests run one relay at a time.
+	gosqlite.MustRegisterScalarFunction("pg_try_advisory_xact_lock", 1,
+		func(*gosqlite.FunctionContext, []driver.Value) (driver.Value, error) { return true, nil })
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// testBus records what is published to it. Publish runs onPublish first,
+// and fails while fail returns true.
+type testBus struct {
+	mu        sync.Mutex
+	published []string
+	onPublish func(msg *utils.Message)
+	fail      func(msg *utils.Message) bool
+}
+
+func (b *testBus) Publish(ctx context.Context, msg *utils.Message) error {
+	if b.onPublish != nil {
+		b.onPublish(msg)
+	}
+	if b.fail != nil && b.fail(msg) {
+		return errors.New("unavailable")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, string(msg.Value))
+	return nil
+}
+
+func (b *testBus) Subscribe(context.Context, string, utils.SubscribeOptions, utils.Handler) error {
+	return nil
+}
+
+func (b *testBus) Close() error { return nil }
+
+func (b *testBus) values() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.published...)
+}
+
+func enqueueAll(t *testing.T, db *gorm.DB, events ...[2]string) {
+	t.Helper()
+	for _, event := range events {
+		if _, err := Enqueue(db, "topic", event[0], []byte(event[1]), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func relayOnce(t *testing.T, relay *Relay) int {
+	t.Helper()
+	published, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	return published
+}
+
+// Events of a key that failed to publish wait for it; other keys go on.
+func TestRelayKeepsKeysInOrder(t *testing.T) {
+	db := newTestDB(t)
+	down := true
+	bus := &testBus{fail: func(msg *utils.Message) bool { return down && string(msg.Value) == "a1" }}
+	relay := NewRelay(db, bus, time.Hour)
+	enqueueAll(t, db, [2]string{"a", "a1"}, [2]string{"b", "b1"}, [2]string{"a", "a2"}, [2]string{"b", "b2"})
+
+	if published := relayOnce(t, relay); published != 2 {
+		t.Fatalf("published %d events, want 2", published)
+	}
+	if got, want := bus.values(), []string{"b1", "b2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+
+	var failed models.OutboxEvent
+	if err := db.First(&failed, "key = ? AND published_at IS NULL", "a").Error; err != nil {
+		t.Fatal(err)
+	}
+	if failed.Attempts != 1 || failed.LastError == "" || !failed.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("failed event recorded as %+v", failed)
+	}
+
+	// Nothing is due until the failed event's backoff is over.
+	down = false
+	if published := relayOnce(t, relay); published != 0 {
+		t.Fatalf("published %d events during the backoff", published)
+	}
+	if err := db.Model(&models.OutboxEvent{}).Where("id = ?", failed.ID).
+		Update("next_attempt_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+	relayOnce(t, relay)
+	if got, want := bus.values(), []string{"b1", "b2", "a1", "a2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+}
+
+// Events are published outside the transaction that claims them, and a
+// relay running meanwhile leaves the claimed events' keys alone.
+func TestRelayPublishesClaimedEventsOnce(t *testing.T) {
+	db := newTestDB(t)
+	bus := &testBus{}
+	relay := NewRelay(db, bus, time.Hour)
+	enqueueAll(t, db, [2]string{"a", "a1"}, [2]string{"a", "a2"})
+
+	var concurrent []int
+	bus.onPublish = func(msg *utils.Message) {
+		if string(msg.Value) != "a1" {
+			return
+		}
+		enqueueAll(t, db, [2]string{"a", "a3"}, [2]string{"b", "b1"})
+		concurrent = append(concurrent, relayOnce(t, NewRelay(db, bus, time.Hour)))
+	}
+
+	if published := relayOnce(t, relay); published != 2 {
+		t.Fatalf("published %d events, want 2", published)
+	}
+	if !reflect.DeepEqual(concurrent, []int{1}) {
+		t.Fatalf("concurrent relay published %v events, want only b1", concurrent)
+	}
+
+	bus.onPublish = nil
+	relayOnce(t, relay)
+	if got, want := bus.values(), []string{"b1", "a1", "a2", "a3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,307 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.0--rc3
+// source: internal/proto/admin.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventAdminService_ListDeadLetters_FullMethodName    = "/proto.EventAdminService/ListDeadLetters"
+	EventAdminService_ReplayDeadLetters_FullMethodName  = "/proto.EventAdminService/ReplayDeadLetters"
+	EventAdminService_DiscardDeadLetters_FullMethodName = "/proto.EventAdminService/DiscardDeadLetters"
+)
+
+// EventAdminServiceClient is the client API for EventAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventAdminService lets operators recover events that consumers gave up on.
+type EventAdminServiceClient interface {
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	ReplayDeadLetters(ctx context.Context, in *DeadLetterActionRequest, opts ...grpc.CallOption) (*DeadLetterActionResponse, error)
+	DiscardDeadLetters(ctx context.Context, in *DeadLetterActionRequest, opts ...grpc.CallOption) (*DeadLetterActionResponse, error)
+}
+
+type eventAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventAdminServiceClient(cc grpc.ClientConnInterface) EventAdminServiceClient {
+	return &eventAdminServiceClient{cc}
+}
+
+func (c *eventAdminServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, EventAdminService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventAdminServiceClient) ReplayDeadLetters(ctx context.Context, in *DeadLetterActionRequest, opts ...grpc.CallOption) (*DeadLetterActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeadLetterActionResponse)
+	err := c.cc.Invoke(ctx, EventAdminService_ReplayDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventAdminServiceClient) DiscardDeadLetters(ctx context.Context, in *DeadLetterActionRequest, opts ...grpc.CallOption) (*DeadLetterActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeadLetterActionResponse)
+	err := c.cc.Invoke(ctx, EventAdminService_DiscardDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventAdminServiceServer is the server API for EventAdminService service.
+// All implementations must embed UnimplementedEventAdminServiceServer
+// for forward compatibility.
+//
+// EventAdminService lets operators recover events that consumers gave up on.
+type EventAdminServiceServer interface {
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	ReplayDeadLetters(context.Context, *DeadLetterActionRequest) (*DeadLetterActionResponse, error)
+	DiscardDeadLetters(context.Context, *DeadLetterActionRequest) (*DeadLetterActionResponse, error)
+	mustEmbedUnimplementedEventAdminServiceServer()
+}
+
+// UnimplementedEventAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventAdminServiceServer struct{}
+
+func (UnimplementedEventAdminServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedEventAdminServiceServer) ReplayDeadLetters(context.Context, *DeadLetterActionRequest) (*DeadLetterActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetters not implemented")
+}
+func (UnimplementedEventAdminServiceServer) DiscardDeadLetters(context.Context, *DeadLetterActionRequest) (*DeadLetterActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardDeadLetters not implemented")
+}
+func (UnimplementedEventAdminServiceServer) mustEmbedUnimplementedEventAdminServiceServer() {}
+func (UnimplementedEventAdminServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeEventAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventAdminServiceServer will
+// result in compilation errors.
+type UnsafeEventAdminServiceServer interface {
+	mustEmbedUnimplementedEventAdminServiceServer()
+}
+
+func RegisterEventAdminServiceServer(s grpc.ServiceRegistrar, srv EventAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEventAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventAdminService_ServiceDesc, srv)
+}
+
+func _EventAdminService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventAdminServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventAdminService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventAdminServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventAdminService_ReplayDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventAdminServiceServer).ReplayDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventAdminService_ReplayDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventAdminServiceServer).ReplayDeadLetters(ctx, req.(*DeadLetterActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventAdminService_DiscardDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventAdminServiceServer).DiscardDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventAdminService_DiscardDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventAdminServiceServer).DiscardDeadLetters(ctx, req.(*DeadLetterActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventAdminService_ServiceDesc is the grpc.ServiceDesc for EventAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.EventAdminService",
+	HandlerType: (*EventAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _EventAdminService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetters",
+			Handler:    _EventAdminService_ReplayDeadLetters_Handler,
+		},
+		{
+			MethodName: "DiscardDeadLetters",
+			Handler:    _EventAdminService_DiscardDeadLetters_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/proto/admin.proto",
+}
+
+const (
+	UserAdminService_SetUserOrganization_FullMethodName = "/proto.UserAdminService/SetUserOrganization"
+)
+
+// UserAdminServiceClient is the client API for UserAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserAdminService lets operators manage user accounts.
+type UserAdminServiceClient interface {
+	SetUserOrganization(ctx context.Context, in *SetUserOrganizationRequest, opts ...grpc.CallOption) (*SetUserOrganizationResponse, error)
+}
+
+type userAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserAdminServiceClient(cc grpc.ClientConnInterface) UserAdminServiceClient {
+	return &userAdminServiceClient{cc}
+}
+
+func (c *userAdminServiceClient) SetUserOrganization(ctx context.Context, in *SetUserOrganizationRequest, opts ...grpc.CallOption) (*SetUserOrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserOrganizationResponse)
+	err := c.cc.Invoke(ctx, UserAdminService_SetUserOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserAdminServiceServer is the server API for UserAdminService service.
+// All implementations must embed UnimplementedUserAdminServiceServer
+// for forward compatibility.
+//
+// UserAdminService lets operators manage user accounts.
+type UserAdminServiceServer interface {
+	SetUserOrganization(context.Context, *SetUserOrganizationRequest) (*SetUserOrganizationResponse, error)
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+// UnimplementedUserAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserAdminServiceServer struct{}
+
+func (UnimplementedUserAdminServiceServer) SetUserOrganization(context.Context, *SetUserOrganizationRequest) (*SetUserOrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserOrganization not implemented")
+}
+func (UnimplementedUserAdminServiceServer) mustEmbedUnimplementedUserAdminServiceServer() {}
+func (UnimplementedUserAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeUserAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserAdminServiceServer will
+// result in compilation errors.
+type UnsafeUserAdminServiceServer interface {
+	mustEmbedUnimplementedUserAdminServiceServer()
+}
+
+func RegisterUserAdminServiceServer(s grpc.ServiceRegistrar, srv UserAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserAdminService_ServiceDesc, srv)
+}
+
+func _UserAdminService_SetUserOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServiceServer).SetUserOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdminService_SetUserOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServiceServer).SetUserOrganization(ctx, req.(*SetUserOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserAdminService_ServiceDesc is the grpc.ServiceDesc for UserAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.UserAdminService",
+	HandlerType: (*UserAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetUserOrganization",
+			Handler:    _UserAdminService_SetUserOrganization_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/proto/admin.proto",
+}
@@ -289,6 +289,231 @@ func (x *ValidateTokenResponse) GetUserId() string {
 	return ""
 }
 
+type RegisterPublicKeyRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	UserId    string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PublicKey []byte                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Algorithm string                 `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// Token of the user registering the key, as returned by SignIn.
+	Token         string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterPublicKeyRequest) Reset() {
+	*x = RegisterPublicKeyRequest{}
+	mi := &file_internal_proto_auth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPublicKeyRequest) ProtoMessage() {}
+
+func (x *RegisterPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_auth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*RegisterPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_auth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RegisterPublicKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RegisterPublicKeyRequest) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *RegisterPublicKeyRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *RegisterPublicKeyRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type RegisterPublicKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterPublicKeyResponse) Reset() {
+	*x = RegisterPublicKeyResponse{}
+	mi := &file_internal_proto_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterPublicKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterPublicKeyResponse) ProtoMessage() {}
+
+func (x *RegisterPublicKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterPublicKeyResponse.ProtoReflect.Descriptor instead.
+func (*RegisterPublicKeyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisterPublicKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterPublicKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetPublicKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeyRequest) Reset() {
+	*x = GetPublicKeyRequest{}
+	mi := &file_internal_proto_auth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeyRequest) ProtoMessage() {}
+
+func (x *GetPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_auth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_auth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPublicKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetPublicKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeyResponse) Reset() {
+	*x = GetPublicKeyResponse{}
+	mi := &file_internal_proto_auth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeyResponse) ProtoMessage() {}
+
+func (x *GetPublicKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_auth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetPublicKeyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_auth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPublicKeyResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetPublicKeyResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *GetPublicKeyResponse) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
 var File_internal_proto_auth_proto protoreflect.FileDescriptor
 
 const file_internal_proto_auth_proto_rawDesc = "" +
@@ -309,11 +534,29 @@ const file_internal_proto_auth_proto_rawDesc = "" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"F\n" +
 	"\x15ValidateTokenResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId2\xc3\x01\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x86\x01\n" +
+	"\x18RegisterPublicKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"O\n" +
+	"\x19RegisterPublicKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\".\n" +
+	"\x13GetPublicKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"l\n" +
+	"\x14GetPublicKeyResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm2\xe4\x02\n" +
 	"\vAuthService\x123\n" +
 	"\x06SignUp\x12\x14.proto.SignUpRequest\x1a\x13.proto.AuthResponse\x123\n" +
 	"\x06SignIn\x12\x14.proto.SignInRequest\x1a\x13.proto.AuthResponse\x12J\n" +
-	"\rValidateToken\x12\x1b.proto.ValidateTokenRequest\x1a\x1c.proto.ValidateTokenResponseBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
+	"\rValidateToken\x12\x1b.proto.ValidateTokenRequest\x1a\x1c.proto.ValidateTokenResponse\x12V\n" +
+	"\x11RegisterPublicKey\x12\x1f.proto.RegisterPublicKeyRequest\x1a .proto.RegisterPublicKeyResponse\x12G\n" +
+	"\fGetPublicKey\x12\x1a.proto.GetPublicKeyRequest\x1a\x1b.proto.GetPublicKeyResponseBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
 
 var (
 	file_internal_proto_auth_proto_rawDescOnce sync.Once
@@ -327,23 +570,31 @@ func file_internal_proto_auth_proto_rawDescGZIP() []byte {
 	return file_internal_proto_auth_proto_rawDescData
 }
 
-var file_internal_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_internal_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_internal_proto_auth_proto_goTypes = []any{
-	(*SignUpRequest)(nil),         // 0: proto.SignUpRequest
-	(*SignInRequest)(nil),         // 1: proto.SignInRequest
-	(*AuthResponse)(nil),          // 2: proto.AuthResponse
-	(*ValidateTokenRequest)(nil),  // 3: proto.ValidateTokenRequest
-	(*ValidateTokenResponse)(nil), // 4: proto.ValidateTokenResponse
+	(*SignUpRequest)(nil),             // 0: proto.SignUpRequest
+	(*SignInRequest)(nil),             // 1: proto.SignInRequest
+	(*AuthResponse)(nil),              // 2: proto.AuthResponse
+	(*ValidateTokenRequest)(nil),      // 3: proto.ValidateTokenRequest
+	(*ValidateTokenResponse)(nil),     // 4: proto.ValidateTokenResponse
+	(*RegisterPublicKeyRequest)(nil),  // 5: proto.RegisterPublicKeyRequest
+	(*RegisterPublicKeyResponse)(nil), // 6: proto.RegisterPublicKeyResponse
+	(*GetPublicKeyRequest)(nil),       // 7: proto.GetPublicKeyRequest
+	(*GetPublicKeyResponse)(nil),      // 8: proto.GetPublicKeyResponse
 }
 var file_internal_proto_auth_proto_depIdxs = []int32{
 	0, // 0: proto.AuthService.SignUp:input_type -> proto.SignUpRequest
 	1, // 1: proto.AuthService.SignIn:input_type -> proto.SignInRequest
 	3, // 2: proto.AuthService.ValidateToken:input_type -> proto.ValidateTokenRequest
-	2, // 3: proto.AuthService.SignUp:output_type -> proto.AuthResponse
-	2, // 4: proto.AuthService.SignIn:output_type -> proto.AuthResponse
-	4, // 5: proto.AuthService.ValidateToken:output_type -> proto.ValidateTokenResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	5, // 3: proto.AuthService.RegisterPublicKey:input_type -> proto.RegisterPublicKeyRequest
+	7, // 4: proto.AuthService.GetPublicKey:input_type -> proto.GetPublicKeyRequest
+	2, // 5: proto.AuthService.SignUp:output_type -> proto.AuthResponse
+	2, // 6: proto.AuthService.SignIn:output_type -> proto.AuthResponse
+	4, // 7: proto.AuthService.ValidateToken:output_type -> proto.ValidateTokenResponse
+	6, // 8: proto.AuthService.RegisterPublicKey:output_type -> proto.RegisterPublicKeyResponse
+	8, // 9: proto.AuthService.GetPublicKey:output_type -> proto.GetPublicKeyResponse
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -360,7 +611,7 @@ func file_internal_proto_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_auth_proto_rawDesc), len(file_internal_proto_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,860 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.0--rc3
+// source: internal/proto/events.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EventEnvelope is the payload of every file change event on the bus.
+// Schema version 1 was a JSON document; version 2 introduced this envelope.
+type EventEnvelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	SchemaVersion uint32                 `protobuf:"varint,2,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// tenant_id is the user's organization, or the user without one.
+	TenantId string `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// causation_id is the event this one was produced in response to.
+	CausationId string `protobuf:"bytes,4,opt,name=causation_id,json=causationId,proto3" json:"causation_id,omitempty"`
+	// correlation_id is shared by every event of one operation.
+	CorrelationId string `protobuf:"bytes,5,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	OccurredAt    string `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	UserId        string `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string `protobuf:"bytes,8,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// confirmed marks held changes released by the user, which are not
+	// screened again.
+	Confirmed bool `protobuf:"varint,9,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*EventEnvelope_FileCreated
+	//	*EventEnvelope_FileModified
+	//	*EventEnvelope_FileDeleted
+	//	*EventEnvelope_FileRenamed
+	//	*EventEnvelope_FileShared
+	//	*EventEnvelope_PermissionChanged
+	//	*EventEnvelope_DeviceAnomaly
+	Payload       isEventEnvelope_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventEnvelope) Reset() {
+	*x = EventEnvelope{}
+	mi := &file_internal_proto_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventEnvelope) ProtoMessage() {}
+
+func (x *EventEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventEnvelope.ProtoReflect.Descriptor instead.
+func (*EventEnvelope) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EventEnvelope) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *EventEnvelope) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetCausationId() string {
+	if x != nil {
+		return x.CausationId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *EventEnvelope) GetConfirmed() bool {
+	if x != nil {
+		return x.Confirmed
+	}
+	return false
+}
+
+func (x *EventEnvelope) GetPayload() isEventEnvelope_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetFileCreated() *FileCreated {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_FileCreated); ok {
+			return x.FileCreated
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetFileModified() *FileModified {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_FileModified); ok {
+			return x.FileModified
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetFileDeleted() *FileDeleted {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_FileDeleted); ok {
+			return x.FileDeleted
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetFileRenamed() *FileRenamed {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_FileRenamed); ok {
+			return x.FileRenamed
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetFileShared() *FileShared {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_FileShared); ok {
+			return x.FileShared
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetPermissionChanged() *PermissionChanged {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_PermissionChanged); ok {
+			return x.PermissionChanged
+		}
+	}
+	return nil
+}
+
+func (x *EventEnvelope) GetDeviceAnomaly() *DeviceAnomaly {
+	if x != nil {
+		if x, ok := x.Payload.(*EventEnvelope_DeviceAnomaly); ok {
+			return x.DeviceAnomaly
+		}
+	}
+	return nil
+}
+
+type isEventEnvelope_Payload interface {
+	isEventEnvelope_Payload()
+}
+
+type EventEnvelope_FileCreated struct {
+	FileCreated *FileCreated `protobuf:"bytes,20,opt,name=file_created,json=fileCreated,proto3,oneof"`
+}
+
+type EventEnvelope_FileModified struct {
+	FileModified *FileModified `protobuf:"bytes,21,opt,name=file_modified,json=fileModified,proto3,oneof"`
+}
+
+type EventEnvelope_FileDeleted struct {
+	FileDeleted *FileDeleted `protobuf:"bytes,22,opt,name=file_deleted,json=fileDeleted,proto3,oneof"`
+}
+
+type EventEnvelope_FileRenamed struct {
+	FileRenamed *FileRenamed `protobuf:"bytes,23,opt,name=file_renamed,json=fileRenamed,proto3,oneof"`
+}
+
+type EventEnvelope_FileShared struct {
+	FileShared *FileShared `protobuf:"bytes,24,opt,name=file_shared,json=fileShared,proto3,oneof"`
+}
+
+type EventEnvelope_PermissionChanged struct {
+	PermissionChanged *PermissionChanged `protobuf:"bytes,25,opt,name=permission_changed,json=permissionChanged,proto3,oneof"`
+}
+
+type EventEnvelope_DeviceAnomaly struct {
+	DeviceAnomaly *DeviceAnomaly `protobuf:"bytes,26,opt,name=device_anomaly,json=deviceAnomaly,proto3,oneof"`
+}
+
+func (*EventEnvelope_FileCreated) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_FileModified) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_FileDeleted) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_FileRenamed) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_FileShared) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_PermissionChanged) isEventEnvelope_Payload() {}
+
+func (*EventEnvelope_DeviceAnomaly) isEventEnvelope_Payload() {}
+
+type FileCreated struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	FileId    string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Path      string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	VersionId string                 `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	// entropy of the content in bits per byte, when known.
+	Entropy       float64 `protobuf:"fixed64,4,opt,name=entropy,proto3" json:"entropy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileCreated) Reset() {
+	*x = FileCreated{}
+	mi := &file_internal_proto_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileCreated) ProtoMessage() {}
+
+func (x *FileCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileCreated.ProtoReflect.Descriptor instead.
+func (*FileCreated) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FileCreated) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileCreated) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileCreated) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *FileCreated) GetEntropy() float64 {
+	if x != nil {
+		return x.Entropy
+	}
+	return 0
+}
+
+type FileModified struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	VersionId     string                 `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	Entropy       float64                `protobuf:"fixed64,4,opt,name=entropy,proto3" json:"entropy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileModified) Reset() {
+	*x = FileModified{}
+	mi := &file_internal_proto_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileModified) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileModified) ProtoMessage() {}
+
+func (x *FileModified) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileModified.ProtoReflect.Descriptor instead.
+func (*FileModified) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileModified) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileModified) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileModified) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *FileModified) GetEntropy() float64 {
+	if x != nil {
+		return x.Entropy
+	}
+	return 0
+}
+
+type FileDeleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDeleted) Reset() {
+	*x = FileDeleted{}
+	mi := &file_internal_proto_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDeleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDeleted) ProtoMessage() {}
+
+func (x *FileDeleted) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDeleted.ProtoReflect.Descriptor instead.
+func (*FileDeleted) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FileDeleted) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileDeleted) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type FileRenamed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	OldPath       string                 `protobuf:"bytes,2,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath       string                 `protobuf:"bytes,3,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	VersionId     string                 `protobuf:"bytes,4,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileRenamed) Reset() {
+	*x = FileRenamed{}
+	mi := &file_internal_proto_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileRenamed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileRenamed) ProtoMessage() {}
+
+func (x *FileRenamed) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileRenamed.ProtoReflect.Descriptor instead.
+func (*FileRenamed) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FileRenamed) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileRenamed) GetOldPath() string {
+	if x != nil {
+		return x.OldPath
+	}
+	return ""
+}
+
+func (x *FileRenamed) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+func (x *FileRenamed) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+type FileShared struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Path             string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	SharedWithUserId string                 `protobuf:"bytes,3,opt,name=shared_with_user_id,json=sharedWithUserId,proto3" json:"shared_with_user_id,omitempty"`
+	Permission       string                 `protobuf:"bytes,4,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FileShared) Reset() {
+	*x = FileShared{}
+	mi := &file_internal_proto_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileShared) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileShared) ProtoMessage() {}
+
+func (x *FileShared) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileShared.ProtoReflect.Descriptor instead.
+func (*FileShared) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FileShared) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileShared) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileShared) GetSharedWithUserId() string {
+	if x != nil {
+		return x.SharedWithUserId
+	}
+	return ""
+}
+
+func (x *FileShared) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
+type PermissionChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	SubjectUserId string                 `protobuf:"bytes,3,opt,name=subject_user_id,json=subjectUserId,proto3" json:"subject_user_id,omitempty"`
+	OldPermission string                 `protobuf:"bytes,4,opt,name=old_permission,json=oldPermission,proto3" json:"old_permission,omitempty"`
+	NewPermission string                 `protobuf:"bytes,5,opt,name=new_permission,json=newPermission,proto3" json:"new_permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionChanged) Reset() {
+	*x = PermissionChanged{}
+	mi := &file_internal_proto_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionChanged) ProtoMessage() {}
+
+func (x *PermissionChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionChanged.ProtoReflect.Descriptor instead.
+func (*PermissionChanged) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PermissionChanged) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *PermissionChanged) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PermissionChanged) GetSubjectUserId() string {
+	if x != nil {
+		return x.SubjectUserId
+	}
+	return ""
+}
+
+func (x *PermissionChanged) GetOldPermission() string {
+	if x != nil {
+		return x.OldPermission
+	}
+	return ""
+}
+
+func (x *PermissionChanged) GetNewPermission() string {
+	if x != nil {
+		return x.NewPermission
+	}
+	return ""
+}
+
+type DeviceAnomaly struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       string                 `protobuf:"bytes,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceAnomaly) Reset() {
+	*x = DeviceAnomaly{}
+	mi := &file_internal_proto_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceAnomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceAnomaly) ProtoMessage() {}
+
+func (x *DeviceAnomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceAnomaly.ProtoReflect.Descriptor instead.
+func (*DeviceAnomaly) Descriptor() ([]byte, []int) {
+	return file_internal_proto_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeviceAnomaly) GetAlertId() string {
+	if x != nil {
+		return x.AlertId
+	}
+	return ""
+}
+
+func (x *DeviceAnomaly) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DeviceAnomaly) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_internal_proto_events_proto protoreflect.FileDescriptor
+
+const file_internal_proto_events_proto_rawDesc = "" +
+	"\n" +
+	"\x1binternal/proto/events.proto\x12\x05proto\"\xdf\x05\n" +
+	"\rEventEnvelope\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12%\n" +
+	"\x0eschema_version\x18\x02 \x01(\rR\rschemaVersion\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\x12!\n" +
+	"\fcausation_id\x18\x04 \x01(\tR\vcausationId\x12%\n" +
+	"\x0ecorrelation_id\x18\x05 \x01(\tR\rcorrelationId\x12\x1f\n" +
+	"\voccurred_at\x18\x06 \x01(\tR\n" +
+	"occurredAt\x12\x17\n" +
+	"\auser_id\x18\a \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\b \x01(\tR\bdeviceId\x12\x1c\n" +
+	"\tconfirmed\x18\t \x01(\bR\tconfirmed\x127\n" +
+	"\ffile_created\x18\x14 \x01(\v2\x12.proto.FileCreatedH\x00R\vfileCreated\x12:\n" +
+	"\rfile_modified\x18\x15 \x01(\v2\x13.proto.FileModifiedH\x00R\ffileModified\x127\n" +
+	"\ffile_deleted\x18\x16 \x01(\v2\x12.proto.FileDeletedH\x00R\vfileDeleted\x127\n" +
+	"\ffile_renamed\x18\x17 \x01(\v2\x12.proto.FileRenamedH\x00R\vfileRenamed\x124\n" +
+	"\vfile_shared\x18\x18 \x01(\v2\x11.proto.FileSharedH\x00R\n" +
+	"fileShared\x12I\n" +
+	"\x12permission_changed\x18\x19 \x01(\v2\x18.proto.PermissionChangedH\x00R\x11permissionChanged\x12=\n" +
+	"\x0edevice_anomaly\x18\x1a \x01(\v2\x14.proto.DeviceAnomalyH\x00R\rdeviceAnomalyB\t\n" +
+	"\apayload\"s\n" +
+	"\vFileCreated\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\tR\tversionId\x12\x18\n" +
+	"\aentropy\x18\x04 \x01(\x01R\aentropy\"t\n" +
+	"\fFileModified\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\tR\tversionId\x12\x18\n" +
+	"\aentropy\x18\x04 \x01(\x01R\aentropy\":\n" +
+	"\vFileDeleted\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"{\n" +
+	"\vFileRenamed\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x19\n" +
+	"\bold_path\x18\x02 \x01(\tR\aoldPath\x12\x19\n" +
+	"\bnew_path\x18\x03 \x01(\tR\anewPath\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x04 \x01(\tR\tversionId\"\x88\x01\n" +
+	"\n" +
+	"FileShared\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12-\n" +
+	"\x13shared_with_user_id\x18\x03 \x01(\tR\x10sharedWithUserId\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x04 \x01(\tR\n" +
+	"permission\"\xb6\x01\n" +
+	"\x11PermissionChanged\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12&\n" +
+	"\x0fsubject_user_id\x18\x03 \x01(\tR\rsubjectUserId\x12%\n" +
+	"\x0eold_permission\x18\x04 \x01(\tR\roldPermission\x12%\n" +
+	"\x0enew_permission\x18\x05 \x01(\tR\rnewPermission\"V\n" +
+	"\rDeviceAnomaly\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\tR\aalertId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reasonBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
+
+var (
+	file_internal_proto_events_proto_rawDescOnce sync.Once
+	file_internal_proto_events_proto_rawDescData []byte
+)
+
+func file_internal_proto_events_proto_rawDescGZIP() []byte {
+	file_internal_proto_events_proto_rawDescOnce.Do(func() {
+		file_internal_proto_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_proto_events_proto_rawDesc), len(file_internal_proto_events_proto_rawDesc)))
+	})
+	return file_internal_proto_events_proto_rawDescData
+}
+
+var file_internal_proto_events_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_internal_proto_events_proto_goTypes = []any{
+	(*EventEnvelope)(nil),     // 0: proto.EventEnvelope
+	(*FileCreated)(nil),       // 1: proto.FileCreated
+	(*FileModified)(nil),      // 2: proto.FileModified
+	(*FileDeleted)(nil),       // 3: proto.FileDeleted
+	(*FileRenamed)(nil),       // 4: proto.FileRenamed
+	(*FileShared)(nil),        // 5: proto.FileShared
+	(*PermissionChanged)(nil), // 6: proto.PermissionChanged
+	(*DeviceAnomaly)(nil),     // 7: proto.DeviceAnomaly
+}
+var file_internal_proto_events_proto_depIdxs = []int32{
+	1, // 0: proto.EventEnvelope.file_created:type_name -> proto.FileCreated
+	2, // 1: proto.EventEnvelope.file_modified:type_name -> proto.FileModified
+	3, // 2: proto.EventEnvelope.file_deleted:type_name -> proto.FileDeleted
+	4, // 3: proto.EventEnvelope.file_renamed:type_name -> proto.FileRenamed
+	5, // 4: proto.EventEnvelope.file_shared:type_name -> proto.FileShared
+	6, // 5: proto.EventEnvelope.permission_changed:type_name -> proto.PermissionChanged
+	7, // 6: proto.EventEnvelope.device_anomaly:type_name -> proto.DeviceAnomaly
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_internal_proto_events_proto_init() }
+func file_internal_proto_events_proto_init() {
+	if File_internal_proto_events_proto != nil {
+		return
+	}
+	file_internal_proto_events_proto_msgTypes[0].OneofWrappers = []any{
+		(*EventEnvelope_FileCreated)(nil),
+		(*EventEnvelope_FileModified)(nil),
+		(*EventEnvelope_FileDeleted)(nil),
+		(*EventEnvelope_FileRenamed)(nil),
+		(*EventEnvelope_FileShared)(nil),
+		(*EventEnvelope_PermissionChanged)(nil),
+		(*EventEnvelope_DeviceAnomaly)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_events_proto_rawDesc), len(file_internal_proto_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_proto_events_proto_goTypes,
+		DependencyIndexes: file_internal_proto_events_proto_depIdxs,
+		MessageInfos:      file_internal_proto_events_proto_msgTypes,
+	}.Build()
+	File_internal_proto_events_proto = out.File
+	file_internal_proto_events_proto_goTypes = nil
+	file_internal_proto_events_proto_depIdxs = nil
+}
@@ -21,6 +21,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RetentionPolicy_Scope int32
+
+const (
+	RetentionPolicy_USER   RetentionPolicy_Scope = 0
+	RetentionPolicy_ORG    RetentionPolicy_Scope = 1
+	RetentionPolicy_FOLDER RetentionPolicy_Scope = 2
+)
+
+// Enum value maps for RetentionPolicy_Scope.
+var (
+	RetentionPolicy_Scope_name = map[int32]string{
+		0: "USER",
+		1: "ORG",
+		2: "FOLDER",
+	}
+	RetentionPolicy_Scope_value = map[string]int32{
+		"USER":   0,
+		"ORG":    1,
+		"FOLDER": 2,
+	}
+)
+
+func (x RetentionPolicy_Scope) Enum() *RetentionPolicy_Scope {
+	p := new(RetentionPolicy_Scope)
+	*p = x
+	return p
+}
+
+func (x RetentionPolicy_Scope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RetentionPolicy_Scope) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_file_proto_enumTypes[0].Descriptor()
+}
+
+func (RetentionPolicy_Scope) Type() protoreflect.EnumType {
+	return &file_internal_proto_file_proto_enumTypes[0]
+}
+
+func (x RetentionPolicy_Scope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RetentionPolicy_Scope.Descriptor instead.
+func (RetentionPolicy_Scope) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{17, 0}
+}
+
 type FileChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
@@ -286,18 +335,22 @@ func (x *FileMetadataRequest) GetUserId() string {
 }
 
 type FileMetadataResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	VersionId     string                 `protobuf:"bytes,7,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
-	OwnerId       string                 `protobuf:"bytes,8,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	SharedWith    []string               `protobuf:"bytes,9,rep,name=shared_with,json=sharedWith,proto3" json:"shared_with,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FileName         string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size             int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ContentType      string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	CreatedAt        string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	VersionId        string                 `protobuf:"bytes,7,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	OwnerId          string                 `protobuf:"bytes,8,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	SharedWith       []string               `protobuf:"bytes,9,rep,name=shared_with,json=sharedWith,proto3" json:"shared_with,omitempty"`
+	CompressionRatio float64                `protobuf:"fixed64,10,opt,name=compression_ratio,json=compressionRatio,proto3" json:"compression_ratio,omitempty"`
+	Encrypted        bool                   `protobuf:"varint,11,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	EncryptedName    []byte                 `protobuf:"bytes,12,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	VersionVector    map[string]uint64      `protobuf:"bytes,13,rep,name=version_vector,json=versionVector,proto3" json:"version_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *FileMetadataResponse) Reset() {
@@ -393,6 +446,34 @@ func (x *FileMetadataResponse) GetSharedWith() []string {
 	return nil
 }
 
+func (x *FileMetadataResponse) GetCompressionRatio() float64 {
+	if x != nil {
+		return x.CompressionRatio
+	}
+	return 0
+}
+
+func (x *FileMetadataResponse) GetEncrypted() bool {
+	if x != nil {
+		return x.Encrypted
+	}
+	return false
+}
+
+func (x *FileMetadataResponse) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *FileMetadataResponse) GetVersionVector() map[string]uint64 {
+	if x != nil {
+		return x.VersionVector
+	}
+	return nil
+}
+
 type ListFilesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -520,6 +601,13 @@ type FileUploadRequest struct {
 	FileName      string                 `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
 	Content       []byte                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
 	FileId        string                 `protobuf:"bytes,5,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	ChunkHash     string                 `protobuf:"bytes,6,opt,name=chunk_hash,json=chunkHash,proto3" json:"chunk_hash,omitempty"`
+	Encrypted     bool                   `protobuf:"varint,7,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	EncryptedName []byte                 `protobuf:"bytes,8,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	WrappedKey    []byte                 `protobuf:"bytes,9,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	// base_vector is the version vector of the copy the upload was made
+	// from. Uploads without one build on the current version.
+	BaseVector    map[string]uint64 `protobuf:"bytes,10,rep,name=base_vector,json=baseVector,proto3" json:"base_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -589,10 +677,52 @@ func (x *FileUploadRequest) GetFileId() string {
 	return ""
 }
 
+func (x *FileUploadRequest) GetChunkHash() string {
+	if x != nil {
+		return x.ChunkHash
+	}
+	return ""
+}
+
+func (x *FileUploadRequest) GetEncrypted() bool {
+	if x != nil {
+		return x.Encrypted
+	}
+	return false
+}
+
+func (x *FileUploadRequest) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *FileUploadRequest) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *FileUploadRequest) GetBaseVector() map[string]uint64 {
+	if x != nil {
+		return x.BaseVector
+	}
+	return nil
+}
+
 type FileUploadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	FileId  string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// conflicting_version_id is set when the upload was made concurrently
+	// with the version it replaced, which it names.
+	ConflictingVersionId string `protobuf:"bytes,3,opt,name=conflicting_version_id,json=conflictingVersionId,proto3" json:"conflicting_version_id,omitempty"`
+	// version_id and version_vector describe the version the upload
+	// created.
+	VersionId     string            `protobuf:"bytes,4,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	VersionVector map[string]uint64 `protobuf:"bytes,5,rep,name=version_vector,json=versionVector,proto3" json:"version_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -641,6 +771,27 @@ func (x *FileUploadResponse) GetMessage() string {
 	return ""
 }
 
+func (x *FileUploadResponse) GetConflictingVersionId() string {
+	if x != nil {
+		return x.ConflictingVersionId
+	}
+	return ""
+}
+
+func (x *FileUploadResponse) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *FileUploadResponse) GetVersionVector() map[string]uint64 {
+	if x != nil {
+		return x.VersionVector
+	}
+	return nil
+}
+
 type FileDownloadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
@@ -745,78 +896,1225 @@ func (x *FileDownloadResponse) GetError() string {
 	return ""
 }
 
-var File_internal_proto_file_proto protoreflect.FileDescriptor
+type HasChunksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ChunkHashes   []string               `protobuf:"bytes,2,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_internal_proto_file_proto_rawDesc = "" +
-	"\n" +
-	"\x19internal/proto/file.proto\x12\x05proto\"\xd5\x01\n" +
-	"\tFileChunk\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\fR\acontent\x12\x17\n" +
-	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12\x1b\n" +
-	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x1f\n" +
-	"\vchunk_index\x18\x04 \x01(\x03R\n" +
-	"chunkIndex\x12!\n" +
-	"\ftotal_chunks\x18\x05 \x01(\x03R\vtotalChunks\x12\x17\n" +
-	"\auser_id\x18\x06 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tdevice_id\x18\a \x01(\tR\bdeviceId\"b\n" +
-	"\x0eUploadResponse\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
-	"\n" +
-	"version_id\x18\x02 \x01(\tR\tversionId\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"b\n" +
-	"\x0fDownloadRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
-	"\n" +
-	"version_id\x18\x02 \x01(\tR\tversionId\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\"G\n" +
-	"\x13FileMetadataRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x9c\x02\n" +
-	"\x14FileMetadataResponse\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
-	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x12\n" +
-	"\x04size\x18\x03 \x01(\x03R\x04size\x12!\n" +
-	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12\x1d\n" +
-	"\n" +
-	"version_id\x18\a \x01(\tR\tversionId\x12\x19\n" +
-	"\bowner_id\x18\b \x01(\tR\aownerId\x12\x1f\n" +
-	"\vshared_with\x18\t \x03(\tR\n" +
-	"sharedWith\"}\n" +
-	"\x10ListFilesRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
-	"\vfolder_path\x18\x02 \x01(\tR\n" +
-	"folderPath\x12\x12\n" +
-	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"g\n" +
-	"\x11ListFilesResponse\x121\n" +
-	"\x05files\x18\x01 \x03(\v2\x1b.proto.FileMetadataResponseR\x05files\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"\x99\x01\n" +
-	"\x11FileUploadRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1b\n" +
-	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x18\n" +
-	"\acontent\x18\x04 \x01(\fR\acontent\x12\x17\n" +
-	"\afile_id\x18\x05 \x01(\tR\x06fileId\"G\n" +
-	"\x12FileUploadResponse\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"G\n" +
-	"\x13FileDownloadRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"F\n" +
-	"\x14FileDownloadResponse\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\fR\acontent\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error2\xa9\x02\n" +
-	"\vFileService\x12C\n" +
-	"\n" +
-	"UploadFile\x12\x18.proto.FileUploadRequest\x1a\x19.proto.FileUploadResponse(\x01\x12I\n" +
-	"\fDownloadFile\x12\x1a.proto.FileDownloadRequest\x1a\x1b.proto.FileDownloadResponse0\x01\x12J\n" +
-	"\x0fGetFileMetadata\x12\x1a.proto.FileMetadataRequest\x1a\x1b.proto.FileMetadataResponse\x12>\n" +
-	"\tListFiles\x12\x17.proto.ListFilesRequest\x1a\x18.proto.ListFilesResponseBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
+func (x *HasChunksRequest) Reset() {
+	*x = HasChunksRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasChunksRequest) ProtoMessage() {}
+
+func (x *HasChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasChunksRequest.ProtoReflect.Descriptor instead.
+func (*HasChunksRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HasChunksRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *HasChunksRequest) GetChunkHashes() []string {
+	if x != nil {
+		return x.ChunkHashes
+	}
+	return nil
+}
+
+type HasChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MissingHashes []string               `protobuf:"bytes,1,rep,name=missing_hashes,json=missingHashes,proto3" json:"missing_hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasChunksResponse) Reset() {
+	*x = HasChunksResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasChunksResponse) ProtoMessage() {}
+
+func (x *HasChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasChunksResponse.ProtoReflect.Descriptor instead.
+func (*HasChunksResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *HasChunksResponse) GetMissingHashes() []string {
+	if x != nil {
+		return x.MissingHashes
+	}
+	return nil
+}
+
+type ShareFileRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	FileId      string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId      string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RecipientId string                 `protobuf:"bytes,3,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	WrappedKey  []byte                 `protobuf:"bytes,4,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	// Token of the owner sharing the file, as returned by SignIn.
+	Token         string `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareFileRequest) Reset() {
+	*x = ShareFileRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareFileRequest) ProtoMessage() {}
+
+func (x *ShareFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareFileRequest.ProtoReflect.Descriptor instead.
+func (*ShareFileRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShareFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetRecipientId() string {
+	if x != nil {
+		return x.RecipientId
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *ShareFileRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ShareFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareFileResponse) Reset() {
+	*x = ShareFileResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareFileResponse) ProtoMessage() {}
+
+func (x *ShareFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareFileResponse.ProtoReflect.Descriptor instead.
+func (*ShareFileResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ShareFileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ShareFileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type FileKeyRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	FileId string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Token of the user fetching their key, as returned by SignIn.
+	Token         string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileKeyRequest) Reset() {
+	*x = FileKeyRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileKeyRequest) ProtoMessage() {}
+
+func (x *FileKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileKeyRequest.ProtoReflect.Descriptor instead.
+func (*FileKeyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FileKeyRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *FileKeyRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type FileKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WrappedKey    []byte                 `protobuf:"bytes,1,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	EncryptedName []byte                 `protobuf:"bytes,2,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileKeyResponse) Reset() {
+	*x = FileKeyResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileKeyResponse) ProtoMessage() {}
+
+func (x *FileKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileKeyResponse.ProtoReflect.Descriptor instead.
+func (*FileKeyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FileKeyResponse) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *FileKeyResponse) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+type RetentionPolicy struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Scope              RetentionPolicy_Scope  `protobuf:"varint,1,opt,name=scope,proto3,enum=proto.RetentionPolicy_Scope" json:"scope,omitempty"`
+	ScopeId            string                 `protobuf:"bytes,2,opt,name=scope_id,json=scopeId,proto3" json:"scope_id,omitempty"`
+	FolderPath         string                 `protobuf:"bytes,3,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	KeepLast           int32                  `protobuf:"varint,4,opt,name=keep_last,json=keepLast,proto3" json:"keep_last,omitempty"`
+	KeepHourlyForHours int32                  `protobuf:"varint,5,opt,name=keep_hourly_for_hours,json=keepHourlyForHours,proto3" json:"keep_hourly_for_hours,omitempty"`
+	KeepDailyForDays   int32                  `protobuf:"varint,6,opt,name=keep_daily_for_days,json=keepDailyForDays,proto3" json:"keep_daily_for_days,omitempty"`
+	KeepAllForDays     int32                  `protobuf:"varint,7,opt,name=keep_all_for_days,json=keepAllForDays,proto3" json:"keep_all_for_days,omitempty"`
+	KeepForever        bool                   `protobuf:"varint,8,opt,name=keep_forever,json=keepForever,proto3" json:"keep_forever,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RetentionPolicy) Reset() {
+	*x = RetentionPolicy{}
+	mi := &file_internal_proto_file_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionPolicy) ProtoMessage() {}
+
+func (x *RetentionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionPolicy.ProtoReflect.Descriptor instead.
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RetentionPolicy) GetScope() RetentionPolicy_Scope {
+	if x != nil {
+		return x.Scope
+	}
+	return RetentionPolicy_USER
+}
+
+func (x *RetentionPolicy) GetScopeId() string {
+	if x != nil {
+		return x.ScopeId
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetKeepLast() int32 {
+	if x != nil {
+		return x.KeepLast
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetKeepHourlyForHours() int32 {
+	if x != nil {
+		return x.KeepHourlyForHours
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetKeepDailyForDays() int32 {
+	if x != nil {
+		return x.KeepDailyForDays
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetKeepAllForDays() int32 {
+	if x != nil {
+		return x.KeepAllForDays
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetKeepForever() bool {
+	if x != nil {
+		return x.KeepForever
+	}
+	return false
+}
+
+type SetRetentionPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Policy        *RetentionPolicy       `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRetentionPolicyRequest) Reset() {
+	*x = SetRetentionPolicyRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *SetRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetRetentionPolicyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetRetentionPolicyRequest) GetPolicy() *RetentionPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type SetRetentionPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRetentionPolicyResponse) Reset() {
+	*x = SetRetentionPolicyResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRetentionPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRetentionPolicyResponse) ProtoMessage() {}
+
+func (x *SetRetentionPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRetentionPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetRetentionPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetRetentionPolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetRetentionPolicyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetRetentionPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Scope         RetentionPolicy_Scope  `protobuf:"varint,2,opt,name=scope,proto3,enum=proto.RetentionPolicy_Scope" json:"scope,omitempty"`
+	ScopeId       string                 `protobuf:"bytes,3,opt,name=scope_id,json=scopeId,proto3" json:"scope_id,omitempty"`
+	FolderPath    string                 `protobuf:"bytes,4,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	FileId        string                 `protobuf:"bytes,5,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRetentionPolicyRequest) Reset() {
+	*x = GetRetentionPolicyRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *GetRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetRetentionPolicyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRetentionPolicyRequest) GetScope() RetentionPolicy_Scope {
+	if x != nil {
+		return x.Scope
+	}
+	return RetentionPolicy_USER
+}
+
+func (x *GetRetentionPolicyRequest) GetScopeId() string {
+	if x != nil {
+		return x.ScopeId
+	}
+	return ""
+}
+
+func (x *GetRetentionPolicyRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *GetRetentionPolicyRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type GetRetentionPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Policy        *RetentionPolicy       `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRetentionPolicyResponse) Reset() {
+	*x = GetRetentionPolicyResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetentionPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetentionPolicyResponse) ProtoMessage() {}
+
+func (x *GetRetentionPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetentionPolicyResponse.ProtoReflect.Descriptor instead.
+func (*GetRetentionPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRetentionPolicyResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetRetentionPolicyResponse) GetPolicy() *RetentionPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type PinVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     string                 `protobuf:"bytes,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Pinned        bool                   `protobuf:"varint,4,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinVersionRequest) Reset() {
+	*x = PinVersionRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinVersionRequest) ProtoMessage() {}
+
+func (x *PinVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinVersionRequest.ProtoReflect.Descriptor instead.
+func (*PinVersionRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PinVersionRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *PinVersionRequest) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *PinVersionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PinVersionRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+type PinVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinVersionResponse) Reset() {
+	*x = PinVersionResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinVersionResponse) ProtoMessage() {}
+
+func (x *PinVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinVersionResponse.ProtoReflect.Descriptor instead.
+func (*PinVersionResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PinVersionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PinVersionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DeleteFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFileRequest) Reset() {
+	*x = DeleteFileRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFileRequest) ProtoMessage() {}
+
+func (x *DeleteFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFileRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFileRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *DeleteFileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteFileRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type DeleteFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFileResponse) Reset() {
+	*x = DeleteFileResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFileResponse) ProtoMessage() {}
+
+func (x *DeleteFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFileResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFileResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeleteFileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteFileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type MoveFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	NewName       string                 `protobuf:"bytes,4,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	EncryptedName []byte                 `protobuf:"bytes,5,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFileRequest) Reset() {
+	*x = MoveFileRequest{}
+	mi := &file_internal_proto_file_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFileRequest) ProtoMessage() {}
+
+func (x *MoveFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFileRequest.ProtoReflect.Descriptor instead.
+func (*MoveFileRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MoveFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+type MoveFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFileResponse) Reset() {
+	*x = MoveFileResponse{}
+	mi := &file_internal_proto_file_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFileResponse) ProtoMessage() {}
+
+func (x *MoveFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_file_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFileResponse.ProtoReflect.Descriptor instead.
+func (*MoveFileResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_file_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *MoveFileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MoveFileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_internal_proto_file_proto protoreflect.FileDescriptor
+
+const file_internal_proto_file_proto_rawDesc = "" +
+	"\n" +
+	"\x19internal/proto/file.proto\x12\x05proto\"\xd5\x01\n" +
+	"\tFileChunk\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x1f\n" +
+	"\vchunk_index\x18\x04 \x01(\x03R\n" +
+	"chunkIndex\x12!\n" +
+	"\ftotal_chunks\x18\x05 \x01(\x03R\vtotalChunks\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\a \x01(\tR\bdeviceId\"b\n" +
+	"\x0eUploadResponse\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"b\n" +
+	"\x0fDownloadRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"G\n" +
+	"\x13FileMetadataRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xa7\x04\n" +
+	"\x14FileMetadataResponse\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\a \x01(\tR\tversionId\x12\x19\n" +
+	"\bowner_id\x18\b \x01(\tR\aownerId\x12\x1f\n" +
+	"\vshared_with\x18\t \x03(\tR\n" +
+	"sharedWith\x12+\n" +
+	"\x11compression_ratio\x18\n" +
+	" \x01(\x01R\x10compressionRatio\x12\x1c\n" +
+	"\tencrypted\x18\v \x01(\bR\tencrypted\x12%\n" +
+	"\x0eencrypted_name\x18\f \x01(\fR\rencryptedName\x12U\n" +
+	"\x0eversion_vector\x18\r \x03(\v2..proto.FileMetadataResponse.VersionVectorEntryR\rversionVector\x1a@\n" +
+	"\x12VersionVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"}\n" +
+	"\x10ListFilesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vfolder_path\x18\x02 \x01(\tR\n" +
+	"folderPath\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"g\n" +
+	"\x11ListFilesResponse\x121\n" +
+	"\x05files\x18\x01 \x03(\v2\x1b.proto.FileMetadataResponseR\x05files\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"\xa8\x03\n" +
+	"\x11FileUploadRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\fR\acontent\x12\x17\n" +
+	"\afile_id\x18\x05 \x01(\tR\x06fileId\x12\x1d\n" +
+	"\n" +
+	"chunk_hash\x18\x06 \x01(\tR\tchunkHash\x12\x1c\n" +
+	"\tencrypted\x18\a \x01(\bR\tencrypted\x12%\n" +
+	"\x0eencrypted_name\x18\b \x01(\fR\rencryptedName\x12\x1f\n" +
+	"\vwrapped_key\x18\t \x01(\fR\n" +
+	"wrappedKey\x12I\n" +
+	"\vbase_vector\x18\n" +
+	" \x03(\v2(.proto.FileUploadRequest.BaseVectorEntryR\n" +
+	"baseVector\x1a=\n" +
+	"\x0fBaseVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"\xb3\x02\n" +
+	"\x12FileUploadResponse\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\x16conflicting_version_id\x18\x03 \x01(\tR\x14conflictingVersionId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x04 \x01(\tR\tversionId\x12S\n" +
+	"\x0eversion_vector\x18\x05 \x03(\v2,.proto.FileUploadResponse.VersionVectorEntryR\rversionVector\x1a@\n" +
+	"\x12VersionVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"G\n" +
+	"\x13FileDownloadRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"F\n" +
+	"\x14FileDownloadResponse\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"N\n" +
+	"\x10HasChunksRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fchunk_hashes\x18\x02 \x03(\tR\vchunkHashes\":\n" +
+	"\x11HasChunksResponse\x12%\n" +
+	"\x0emissing_hashes\x18\x01 \x03(\tR\rmissingHashes\"\x9e\x01\n" +
+	"\x10ShareFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\frecipient_id\x18\x03 \x01(\tR\vrecipientId\x12\x1f\n" +
+	"\vwrapped_key\x18\x04 \x01(\fR\n" +
+	"wrappedKey\x12\x14\n" +
+	"\x05token\x18\x05 \x01(\tR\x05token\"G\n" +
+	"\x11ShareFileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"X\n" +
+	"\x0eFileKeyRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"Y\n" +
+	"\x0fFileKeyResponse\x12\x1f\n" +
+	"\vwrapped_key\x18\x01 \x01(\fR\n" +
+	"wrappedKey\x12%\n" +
+	"\x0eencrypted_name\x18\x02 \x01(\fR\rencryptedName\"\xf6\x02\n" +
+	"\x0fRetentionPolicy\x122\n" +
+	"\x05scope\x18\x01 \x01(\x0e2\x1c.proto.RetentionPolicy.ScopeR\x05scope\x12\x19\n" +
+	"\bscope_id\x18\x02 \x01(\tR\ascopeId\x12\x1f\n" +
+	"\vfolder_path\x18\x03 \x01(\tR\n" +
+	"folderPath\x12\x1b\n" +
+	"\tkeep_last\x18\x04 \x01(\x05R\bkeepLast\x121\n" +
+	"\x15keep_hourly_for_hours\x18\x05 \x01(\x05R\x12keepHourlyForHours\x12-\n" +
+	"\x13keep_daily_for_days\x18\x06 \x01(\x05R\x10keepDailyForDays\x12)\n" +
+	"\x11keep_all_for_days\x18\a \x01(\x05R\x0ekeepAllForDays\x12!\n" +
+	"\fkeep_forever\x18\b \x01(\bR\vkeepForever\"&\n" +
+	"\x05Scope\x12\b\n" +
+	"\x04USER\x10\x00\x12\a\n" +
+	"\x03ORG\x10\x01\x12\n" +
+	"\n" +
+	"\x06FOLDER\x10\x02\"d\n" +
+	"\x19SetRetentionPolicyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x06policy\x18\x02 \x01(\v2\x16.proto.RetentionPolicyR\x06policy\"P\n" +
+	"\x1aSetRetentionPolicyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xbd\x01\n" +
+	"\x19GetRetentionPolicyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x122\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\x1c.proto.RetentionPolicy.ScopeR\x05scope\x12\x19\n" +
+	"\bscope_id\x18\x03 \x01(\tR\ascopeId\x12\x1f\n" +
+	"\vfolder_path\x18\x04 \x01(\tR\n" +
+	"folderPath\x12\x17\n" +
+	"\afile_id\x18\x05 \x01(\tR\x06fileId\"b\n" +
+	"\x1aGetRetentionPolicyResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12.\n" +
+	"\x06policy\x18\x02 \x01(\v2\x16.proto.RetentionPolicyR\x06policy\"|\n" +
+	"\x11PinVersionRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06pinned\x18\x04 \x01(\bR\x06pinned\"H\n" +
+	"\x12PinVersionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"b\n" +
+	"\x11DeleteFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\"H\n" +
+	"\x12DeleteFileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xa2\x01\n" +
+	"\x0fMoveFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x19\n" +
+	"\bnew_name\x18\x04 \x01(\tR\anewName\x12%\n" +
+	"\x0eencrypted_name\x18\x05 \x01(\fR\rencryptedName\"F\n" +
+	"\x10MoveFileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xdf\x06\n" +
+	"\vFileService\x12C\n" +
+	"\n" +
+	"UploadFile\x12\x18.proto.FileUploadRequest\x1a\x19.proto.FileUploadResponse(\x01\x12I\n" +
+	"\fDownloadFile\x12\x1a.proto.FileDownloadRequest\x1a\x1b.proto.FileDownloadResponse0\x01\x12J\n" +
+	"\x0fGetFileMetadata\x12\x1a.proto.FileMetadataRequest\x1a\x1b.proto.FileMetadataResponse\x12>\n" +
+	"\tListFiles\x12\x17.proto.ListFilesRequest\x1a\x18.proto.ListFilesResponse\x12>\n" +
+	"\tHasChunks\x12\x17.proto.HasChunksRequest\x1a\x18.proto.HasChunksResponse\x12>\n" +
+	"\tShareFile\x12\x17.proto.ShareFileRequest\x1a\x18.proto.ShareFileResponse\x12;\n" +
+	"\n" +
+	"GetFileKey\x12\x15.proto.FileKeyRequest\x1a\x16.proto.FileKeyResponse\x12Y\n" +
+	"\x12SetRetentionPolicy\x12 .proto.SetRetentionPolicyRequest\x1a!.proto.SetRetentionPolicyResponse\x12Y\n" +
+	"\x12GetRetentionPolicy\x12 .proto.GetRetentionPolicyRequest\x1a!.proto.GetRetentionPolicyResponse\x12A\n" +
+	"\n" +
+	"PinVersion\x12\x18.proto.PinVersionRequest\x1a\x19.proto.PinVersionResponse\x12A\n" +
+	"\n" +
+	"DeleteFile\x12\x18.proto.DeleteFileRequest\x1a\x19.proto.DeleteFileResponse\x12;\n" +
+	"\bMoveFile\x12\x16.proto.MoveFileRequest\x1a\x17.proto.MoveFileResponseBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
 
 var (
 	file_internal_proto_file_proto_rawDescOnce sync.Once
@@ -830,35 +2128,80 @@ func file_internal_proto_file_proto_rawDescGZIP() []byte {
 	return file_internal_proto_file_proto_rawDescData
 }
 
-var file_internal_proto_file_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_internal_proto_file_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_internal_proto_file_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_internal_proto_file_proto_goTypes = []any{
-	(*FileChunk)(nil),            // 0: proto.FileChunk
-	(*UploadResponse)(nil),       // 1: proto.UploadResponse
-	(*DownloadRequest)(nil),      // 2: proto.DownloadRequest
-	(*FileMetadataRequest)(nil),  // 3: proto.FileMetadataRequest
-	(*FileMetadataResponse)(nil), // 4: proto.FileMetadataResponse
-	(*ListFilesRequest)(nil),     // 5: proto.ListFilesRequest
-	(*ListFilesResponse)(nil),    // 6: proto.ListFilesResponse
-	(*FileUploadRequest)(nil),    // 7: proto.FileUploadRequest
-	(*FileUploadResponse)(nil),   // 8: proto.FileUploadResponse
-	(*FileDownloadRequest)(nil),  // 9: proto.FileDownloadRequest
-	(*FileDownloadResponse)(nil), // 10: proto.FileDownloadResponse
+	(RetentionPolicy_Scope)(0),         // 0: proto.RetentionPolicy.Scope
+	(*FileChunk)(nil),                  // 1: proto.FileChunk
+	(*UploadResponse)(nil),             // 2: proto.UploadResponse
+	(*DownloadRequest)(nil),            // 3: proto.DownloadRequest
+	(*FileMetadataRequest)(nil),        // 4: proto.FileMetadataRequest
+	(*FileMetadataResponse)(nil),       // 5: proto.FileMetadataResponse
+	(*ListFilesRequest)(nil),           // 6: proto.ListFilesRequest
+	(*ListFilesResponse)(nil),          // 7: proto.ListFilesResponse
+	(*FileUploadRequest)(nil),          // 8: proto.FileUploadRequest
+	(*FileUploadResponse)(nil),         // 9: proto.FileUploadResponse
+	(*FileDownloadRequest)(nil),        // 10: proto.FileDownloadRequest
+	(*FileDownloadResponse)(nil),       // 11: proto.FileDownloadResponse
+	(*HasChunksRequest)(nil),           // 12: proto.HasChunksRequest
+	(*HasChunksResponse)(nil),          // 13: proto.HasChunksResponse
+	(*ShareFileRequest)(nil),           // 14: proto.ShareFileRequest
+	(*ShareFileResponse)(nil),          // 15: proto.ShareFileResponse
+	(*FileKeyRequest)(nil),             // 16: proto.FileKeyRequest
+	(*FileKeyResponse)(nil),            // 17: proto.FileKeyResponse
+	(*RetentionPolicy)(nil),            // 18: proto.RetentionPolicy
+	(*SetRetentionPolicyRequest)(nil),  // 19: proto.SetRetentionPolicyRequest
+	(*SetRetentionPolicyResponse)(nil), // 20: proto.SetRetentionPolicyResponse
+	(*GetRetentionPolicyRequest)(nil),  // 21: proto.GetRetentionPolicyRequest
+	(*GetRetentionPolicyResponse)(nil), // 22: proto.GetRetentionPolicyResponse
+	(*PinVersionRequest)(nil),          // 23: proto.PinVersionRequest
+	(*PinVersionResponse)(nil),         // 24: proto.PinVersionResponse
+	(*DeleteFileRequest)(nil),          // 25: proto.DeleteFileRequest
+	(*DeleteFileResponse)(nil),         // 26: proto.DeleteFileResponse
+	(*MoveFileRequest)(nil),            // 27: proto.MoveFileRequest
+	(*MoveFileResponse)(nil),           // 28: proto.MoveFileResponse
+	nil,                                // 29: proto.FileMetadataResponse.VersionVectorEntry
+	nil,                                // 30: proto.FileUploadRequest.BaseVectorEntry
+	nil,                                // 31: proto.FileUploadResponse.VersionVectorEntry
 }
 var file_internal_proto_file_proto_depIdxs = []int32{
-	4,  // 0: proto.ListFilesResponse.files:type_name -> proto.FileMetadataResponse
-	7,  // 1: proto.FileService.UploadFile:input_type -> proto.FileUploadRequest
-	9,  // 2: proto.FileService.DownloadFile:input_type -> proto.FileDownloadRequest
-	3,  // 3: proto.FileService.GetFileMetadata:input_type -> proto.FileMetadataRequest
-	5,  // 4: proto.FileService.ListFiles:input_type -> proto.ListFilesRequest
-	8,  // 5: proto.FileService.UploadFile:output_type -> proto.FileUploadResponse
-	10, // 6: proto.FileService.DownloadFile:output_type -> proto.FileDownloadResponse
-	4,  // 7: proto.FileService.GetFileMetadata:output_type -> proto.FileMetadataResponse
-	6,  // 8: proto.FileService.ListFiles:output_type -> proto.ListFilesResponse
-	5,  // [5:9] is the sub-list for method output_type
-	1,  // [1:5] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	29, // 0: proto.FileMetadataResponse.version_vector:type_name -> proto.FileMetadataResponse.VersionVectorEntry
+	5,  // 1: proto.ListFilesResponse.files:type_name -> proto.FileMetadataResponse
+	30, // 2: proto.FileUploadRequest.base_vector:type_name -> proto.FileUploadRequest.BaseVectorEntry
+	31, // 3: proto.FileUploadResponse.version_vector:type_name -> proto.FileUploadResponse.VersionVectorEntry
+	0,  // 4: proto.RetentionPolicy.scope:type_name -> proto.RetentionPolicy.Scope
+	18, // 5: proto.SetRetentionPolicyRequest.policy:type_name -> proto.RetentionPolicy
+	0,  // 6: proto.GetRetentionPolicyRequest.scope:type_name -> proto.RetentionPolicy.Scope
+	18, // 7: proto.GetRetentionPolicyResponse.policy:type_name -> proto.RetentionPolicy
+	8,  // 8: proto.FileService.UploadFile:input_type -> proto.FileUploadRequest
+	10, // 9: proto.FileService.DownloadFile:input_type -> proto.FileDownloadRequest
+	4,  // 10: proto.FileService.GetFileMetadata:input_type -> proto.FileMetadataRequest
+	6,  // 11: proto.FileService.ListFiles:input_type -> proto.ListFilesRequest
+	12, // 12: proto.FileService.HasChunks:input_type -> proto.HasChunksRequest
+	14, // 13: proto.FileService.ShareFile:input_type -> proto.ShareFileRequest
+	16, // 14: proto.FileService.GetFileKey:input_type -> proto.FileKeyRequest
+	19, // 15: proto.FileService.SetRetentionPolicy:input_type -> proto.SetRetentionPolicyRequest
+	21, // 16: proto.FileService.GetRetentionPolicy:input_type -> proto.GetRetentionPolicyRequest
+	23, // 17: proto.FileService.PinVersion:input_type -> proto.PinVersionRequest
+	25, // 18: proto.FileService.DeleteFile:input_type -> proto.DeleteFileRequest
+	27, // 19: proto.FileService.MoveFile:input_type -> proto.MoveFileRequest
+	9,  // 20: proto.FileService.UploadFile:output_type -> proto.FileUploadResponse
+	11, // 21: proto.FileService.DownloadFile:output_type -> proto.FileDownloadResponse
+	5,  // 22: proto.FileService.GetFileMetadata:output_type -> proto.FileMetadataResponse
+	7,  // 23: proto.FileService.ListFiles:output_type -> proto.ListFilesResponse
+	13, // 24: proto.FileService.HasChunks:output_type -> proto.HasChunksResponse
+	15, // 25: proto.FileService.ShareFile:output_type -> proto.ShareFileResponse
+	17, // 26: proto.FileService.GetFileKey:output_type -> proto.FileKeyResponse
+	20, // 27: proto.FileService.SetRetentionPolicy:output_type -> proto.SetRetentionPolicyResponse
+	22, // 28: proto.FileService.GetRetentionPolicy:output_type -> proto.GetRetentionPolicyResponse
+	24, // 29: proto.FileService.PinVersion:output_type -> proto.PinVersionResponse
+	26, // 30: proto.FileService.DeleteFile:output_type -> proto.DeleteFileResponse
+	28, // 31: proto.FileService.MoveFile:output_type -> proto.MoveFileResponse
+	20, // [20:32] is the sub-list for method output_type
+	8,  // [8:20] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_file_proto_init() }
@@ -871,13 +2214,14 @@ func file_internal_proto_file_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_file_proto_rawDesc), len(file_internal_proto_file_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_internal_proto_file_proto_goTypes,
 		DependencyIndexes: file_internal_proto_file_proto_depIdxs,
+		EnumInfos:         file_internal_proto_file_proto_enumTypes,
 		MessageInfos:      file_internal_proto_file_proto_msgTypes,
 	}.Build()
 	File_internal_proto_file_proto = out.File
@@ -28,6 +28,8 @@ const (
 	SyncResponse_CONFLICT     SyncResponse_SyncStatus = 1
 	SyncResponse_NEEDS_UPDATE SyncResponse_SyncStatus = 2
 	SyncResponse_ERROR        SyncResponse_SyncStatus = 3
+	SyncResponse_FAST_FORWARD SyncResponse_SyncStatus = 4
+	SyncResponse_MERGED       SyncResponse_SyncStatus = 5
 )
 
 // Enum value maps for SyncResponse_SyncStatus.
@@ -37,12 +39,16 @@ var (
 		1: "CONFLICT",
 		2: "NEEDS_UPDATE",
 		3: "ERROR",
+		4: "FAST_FORWARD",
+		5: "MERGED",
 	}
 	SyncResponse_SyncStatus_value = map[string]int32{
 		"SYNCED":       0,
 		"CONFLICT":     1,
 		"NEEDS_UPDATE": 2,
 		"ERROR":        3,
+		"FAST_FORWARD": 4,
+		"MERGED":       5,
 	}
 )
 
@@ -73,6 +79,58 @@ func (SyncResponse_SyncStatus) EnumDescriptor() ([]byte, []int) {
 	return file_internal_proto_sync_proto_rawDescGZIP(), []int{1, 0}
 }
 
+type ConflictResolutionRequest_Strategy int32
+
+const (
+	ConflictResolutionRequest_PICK_WINNER ConflictResolutionRequest_Strategy = 0
+	ConflictResolutionRequest_KEEP_BOTH   ConflictResolutionRequest_Strategy = 1
+	ConflictResolutionRequest_MERGED      ConflictResolutionRequest_Strategy = 2
+	ConflictResolutionRequest_AUTO_MERGE  ConflictResolutionRequest_Strategy = 3
+)
+
+// Enum value maps for ConflictResolutionRequest_Strategy.
+var (
+	ConflictResolutionRequest_Strategy_name = map[int32]string{
+		0: "PICK_WINNER",
+		1: "KEEP_BOTH",
+		2: "MERGED",
+		3: "AUTO_MERGE",
+	}
+	ConflictResolutionRequest_Strategy_value = map[string]int32{
+		"PICK_WINNER": 0,
+		"KEEP_BOTH":   1,
+		"MERGED":      2,
+		"AUTO_MERGE":  3,
+	}
+)
+
+func (x ConflictResolutionRequest_Strategy) Enum() *ConflictResolutionRequest_Strategy {
+	p := new(ConflictResolutionRequest_Strategy)
+	*p = x
+	return p
+}
+
+func (x ConflictResolutionRequest_Strategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConflictResolutionRequest_Strategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_sync_proto_enumTypes[1].Descriptor()
+}
+
+func (ConflictResolutionRequest_Strategy) Type() protoreflect.EnumType {
+	return &file_internal_proto_sync_proto_enumTypes[1]
+}
+
+func (x ConflictResolutionRequest_Strategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConflictResolutionRequest_Strategy.Descriptor instead.
+func (ConflictResolutionRequest_Strategy) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{5, 0}
+}
+
 type FileChangeEvent_ChangeType int32
 
 const (
@@ -80,6 +138,8 @@ const (
 	FileChangeEvent_MODIFIED FileChangeEvent_ChangeType = 1
 	FileChangeEvent_DELETED  FileChangeEvent_ChangeType = 2
 	FileChangeEvent_RENAMED  FileChangeEvent_ChangeType = 3
+	FileChangeEvent_ALERT    FileChangeEvent_ChangeType = 4
+	FileChangeEvent_SHARED   FileChangeEvent_ChangeType = 5
 )
 
 // Enum value maps for FileChangeEvent_ChangeType.
@@ -89,12 +149,16 @@ var (
 		1: "MODIFIED",
 		2: "DELETED",
 		3: "RENAMED",
+		4: "ALERT",
+		5: "SHARED",
 	}
 	FileChangeEvent_ChangeType_value = map[string]int32{
 		"CREATED":  0,
 		"MODIFIED": 1,
 		"DELETED":  2,
 		"RENAMED":  3,
+		"ALERT":    4,
+		"SHARED":   5,
 	}
 )
 
@@ -109,11 +173,11 @@ func (x FileChangeEvent_ChangeType) String() string {
 }
 
 func (FileChangeEvent_ChangeType) Descriptor() protoreflect.EnumDescriptor {
-	return file_internal_proto_sync_proto_enumTypes[1].Descriptor()
+	return file_internal_proto_sync_proto_enumTypes[2].Descriptor()
 }
 
 func (FileChangeEvent_ChangeType) Type() protoreflect.EnumType {
-	return &file_internal_proto_sync_proto_enumTypes[1]
+	return &file_internal_proto_sync_proto_enumTypes[2]
 }
 
 func (x FileChangeEvent_ChangeType) Number() protoreflect.EnumNumber {
@@ -125,14 +189,68 @@ func (FileChangeEvent_ChangeType) EnumDescriptor() ([]byte, []int) {
 	return file_internal_proto_sync_proto_rawDescGZIP(), []int{8, 0}
 }
 
+type RestoreFolderChange_Action int32
+
+const (
+	RestoreFolderChange_RESTORE  RestoreFolderChange_Action = 0
+	RestoreFolderChange_UNDELETE RestoreFolderChange_Action = 1
+	RestoreFolderChange_DELETE   RestoreFolderChange_Action = 2
+)
+
+// Enum value maps for RestoreFolderChange_Action.
+var (
+	RestoreFolderChange_Action_name = map[int32]string{
+		0: "RESTORE",
+		1: "UNDELETE",
+		2: "DELETE",
+	}
+	RestoreFolderChange_Action_value = map[string]int32{
+		"RESTORE":  0,
+		"UNDELETE": 1,
+		"DELETE":   2,
+	}
+)
+
+func (x RestoreFolderChange_Action) Enum() *RestoreFolderChange_Action {
+	p := new(RestoreFolderChange_Action)
+	*p = x
+	return p
+}
+
+func (x RestoreFolderChange_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RestoreFolderChange_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_sync_proto_enumTypes[3].Descriptor()
+}
+
+func (RestoreFolderChange_Action) Type() protoreflect.EnumType {
+	return &file_internal_proto_sync_proto_enumTypes[3]
+}
+
+func (x RestoreFolderChange_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RestoreFolderChange_Action.Descriptor instead.
+func (RestoreFolderChange_Action) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{14, 0}
+}
+
 type SyncRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	FileHash      string                 `protobuf:"bytes,4,opt,name=file_hash,json=fileHash,proto3" json:"file_hash,omitempty"`
-	FileSize      int64                  `protobuf:"varint,5,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
-	LastModified  string                 `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	FileId       string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId       string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId     string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	FileHash     string                 `protobuf:"bytes,4,opt,name=file_hash,json=fileHash,proto3" json:"file_hash,omitempty"`
+	FileSize     int64                  `protobuf:"varint,5,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	LastModified string                 `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	// The vector of the version the client's copy is based on.
+	VersionVector map[string]uint64 `protobuf:"bytes,7,rep,name=version_vector,json=versionVector,proto3" json:"version_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// The client's copy, sent to let the server merge concurrent edits of
+	// text files.
+	Content       []byte `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -209,13 +327,30 @@ func (x *SyncRequest) GetLastModified() string {
 	return ""
 }
 
+func (x *SyncRequest) GetVersionVector() map[string]uint64 {
+	if x != nil {
+		return x.VersionVector
+	}
+	return nil
+}
+
+func (x *SyncRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
 type SyncResponse struct {
-	state           protoimpl.MessageState  `protogen:"open.v1"`
-	Status          SyncResponse_SyncStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.SyncResponse_SyncStatus" json:"status,omitempty"`
-	Message         string                  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	LatestVersionId string                  `protobuf:"bytes,3,opt,name=latest_version_id,json=latestVersionId,proto3" json:"latest_version_id,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state                protoimpl.MessageState  `protogen:"open.v1"`
+	Status               SyncResponse_SyncStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.SyncResponse_SyncStatus" json:"status,omitempty"`
+	Message              string                  `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	LatestVersionId      string                  `protobuf:"bytes,3,opt,name=latest_version_id,json=latestVersionId,proto3" json:"latest_version_id,omitempty"`
+	VersionVector        map[string]uint64       `protobuf:"bytes,4,rep,name=version_vector,json=versionVector,proto3" json:"version_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ConflictedCopyFileId string                  `protobuf:"bytes,5,opt,name=conflicted_copy_file_id,json=conflictedCopyFileId,proto3" json:"conflicted_copy_file_id,omitempty"`
+	ConflictingPaths     []string                `protobuf:"bytes,6,rep,name=conflicting_paths,json=conflictingPaths,proto3" json:"conflicting_paths,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *SyncResponse) Reset() {
@@ -269,6 +404,27 @@ func (x *SyncResponse) GetLatestVersionId() string {
 	return ""
 }
 
+func (x *SyncResponse) GetVersionVector() map[string]uint64 {
+	if x != nil {
+		return x.VersionVector
+	}
+	return nil
+}
+
+func (x *SyncResponse) GetConflictedCopyFileId() string {
+	if x != nil {
+		return x.ConflictedCopyFileId
+	}
+	return ""
+}
+
+func (x *SyncResponse) GetConflictingPaths() []string {
+	if x != nil {
+		return x.ConflictingPaths
+	}
+	return nil
+}
+
 type FileVersionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
@@ -328,6 +484,11 @@ type FileVersion struct {
 	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
 	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
 	Hash          string                 `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	VersionNum    int32                  `protobuf:"varint,6,opt,name=version_num,json=versionNum,proto3" json:"version_num,omitempty"`
+	SupersededBy  string                 `protobuf:"bytes,7,opt,name=superseded_by,json=supersededBy,proto3" json:"superseded_by,omitempty"`
+	VersionVector map[string]uint64      `protobuf:"bytes,8,rep,name=version_vector,json=versionVector,proto3" json:"version_vector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// conflicts_with names the version this one was made concurrently with.
+	ConflictsWith string `protobuf:"bytes,9,opt,name=conflicts_with,json=conflictsWith,proto3" json:"conflicts_with,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -397,6 +558,34 @@ func (x *FileVersion) GetHash() string {
 	return ""
 }
 
+func (x *FileVersion) GetVersionNum() int32 {
+	if x != nil {
+		return x.VersionNum
+	}
+	return 0
+}
+
+func (x *FileVersion) GetSupersededBy() string {
+	if x != nil {
+		return x.SupersededBy
+	}
+	return ""
+}
+
+func (x *FileVersion) GetVersionVector() map[string]uint64 {
+	if x != nil {
+		return x.VersionVector
+	}
+	return nil
+}
+
+func (x *FileVersion) GetConflictsWith() string {
+	if x != nil {
+		return x.ConflictsWith
+	}
+	return ""
+}
+
 type FileVersionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Versions      []*FileVersion         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
@@ -442,11 +631,14 @@ func (x *FileVersionResponse) GetVersions() []*FileVersion {
 }
 
 type ConflictResolutionRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	UserId           string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	WinningVersionId string                 `protobuf:"bytes,3,opt,name=winning_version_id,json=winningVersionId,proto3" json:"winning_version_id,omitempty"`
-	LosingVersionIds []string               `protobuf:"bytes,4,rep,name=losing_version_ids,json=losingVersionIds,proto3" json:"losing_version_ids,omitempty"`
+	state            protoimpl.MessageState             `protogen:"open.v1"`
+	FileId           string                             `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId           string                             `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WinningVersionId string                             `protobuf:"bytes,3,opt,name=winning_version_id,json=winningVersionId,proto3" json:"winning_version_id,omitempty"`
+	LosingVersionIds []string                           `protobuf:"bytes,4,rep,name=losing_version_ids,json=losingVersionIds,proto3" json:"losing_version_ids,omitempty"`
+	Strategy         ConflictResolutionRequest_Strategy `protobuf:"varint,5,opt,name=strategy,proto3,enum=proto.ConflictResolutionRequest_Strategy" json:"strategy,omitempty"`
+	DeviceId         string                             `protobuf:"bytes,6,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	MergedContent    []byte                             `protobuf:"bytes,7,opt,name=merged_content,json=mergedContent,proto3" json:"merged_content,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -509,13 +701,37 @@ func (x *ConflictResolutionRequest) GetLosingVersionIds() []string {
 	return nil
 }
 
+func (x *ConflictResolutionRequest) GetStrategy() ConflictResolutionRequest_Strategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return ConflictResolutionRequest_PICK_WINNER
+}
+
+func (x *ConflictResolutionRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ConflictResolutionRequest) GetMergedContent() []byte {
+	if x != nil {
+		return x.MergedContent
+	}
+	return nil
+}
+
 type ConflictResolutionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	NewVersionId  string                 `protobuf:"bytes,3,opt,name=new_version_id,json=newVersionId,proto3" json:"new_version_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Success               bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message               string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	NewVersionId          string                 `protobuf:"bytes,3,opt,name=new_version_id,json=newVersionId,proto3" json:"new_version_id,omitempty"`
+	SupersededVersionIds  []string               `protobuf:"bytes,4,rep,name=superseded_version_ids,json=supersededVersionIds,proto3" json:"superseded_version_ids,omitempty"`
+	ConflictedCopyFileIds []string               `protobuf:"bytes,5,rep,name=conflicted_copy_file_ids,json=conflictedCopyFileIds,proto3" json:"conflicted_copy_file_ids,omitempty"`
+	ConflictingPaths      []string               `protobuf:"bytes,6,rep,name=conflicting_paths,json=conflictingPaths,proto3" json:"conflicting_paths,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *ConflictResolutionResponse) Reset() {
@@ -569,11 +785,37 @@ func (x *ConflictResolutionResponse) GetNewVersionId() string {
 	return ""
 }
 
+func (x *ConflictResolutionResponse) GetSupersededVersionIds() []string {
+	if x != nil {
+		return x.SupersededVersionIds
+	}
+	return nil
+}
+
+func (x *ConflictResolutionResponse) GetConflictedCopyFileIds() []string {
+	if x != nil {
+		return x.ConflictedCopyFileIds
+	}
+	return nil
+}
+
+func (x *ConflictResolutionResponse) GetConflictingPaths() []string {
+	if x != nil {
+		return x.ConflictingPaths
+	}
+	return nil
+}
+
 type WatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	FolderPaths   []string               `protobuf:"bytes,3,rep,name=folder_paths,json=folderPaths,proto3" json:"folder_paths,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Ignored: folders are watched by the client, which uploads its changes.
+	//
+	// Deprecated: Marked as deprecated in internal/proto/sync.proto.
+	FolderPaths []string `protobuf:"bytes,3,rep,name=folder_paths,json=folderPaths,proto3" json:"folder_paths,omitempty"`
+	// Resume after this cursor. Without one only new changes are sent.
+	Cursor        string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -622,6 +864,7 @@ func (x *WatchRequest) GetDeviceId() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in internal/proto/sync.proto.
 func (x *WatchRequest) GetFolderPaths() []string {
 	if x != nil {
 		return x.FolderPaths
@@ -629,14 +872,25 @@ func (x *WatchRequest) GetFolderPaths() []string {
 	return nil
 }
 
+func (x *WatchRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
 type FileChangeEvent struct {
-	state         protoimpl.MessageState     `protogen:"open.v1"`
-	FileId        string                     `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	FilePath      string                     `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
-	ChangeType    FileChangeEvent_ChangeType `protobuf:"varint,3,opt,name=change_type,json=changeType,proto3,enum=proto.FileChangeEvent_ChangeType" json:"change_type,omitempty"`
-	Timestamp     string                     `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	DeviceId      string                     `protobuf:"bytes,5,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	VersionId     string                     `protobuf:"bytes,6,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	state      protoimpl.MessageState     `protogen:"open.v1"`
+	FileId     string                     `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FilePath   string                     `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	ChangeType FileChangeEvent_ChangeType `protobuf:"varint,3,opt,name=change_type,json=changeType,proto3,enum=proto.FileChangeEvent_ChangeType" json:"change_type,omitempty"`
+	Timestamp  string                     `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	DeviceId   string                     `protobuf:"bytes,5,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	VersionId  string                     `protobuf:"bytes,6,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	AlertId    string                     `protobuf:"bytes,7,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Reason     string                     `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Pass to GetChanges or WatchFileChanges to resume after this event.
+	Cursor        string `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -713,75 +967,1012 @@ func (x *FileChangeEvent) GetVersionId() string {
 	return ""
 }
 
-var File_internal_proto_sync_proto protoreflect.FileDescriptor
+func (x *FileChangeEvent) GetAlertId() string {
+	if x != nil {
+		return x.AlertId
+	}
+	return ""
+}
 
-const file_internal_proto_sync_proto_rawDesc = "" +
-	"\n" +
-	"\x19internal/proto/sync.proto\x12\x05proto\"\xbb\x01\n" +
-	"\vSyncRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1b\n" +
-	"\tfile_hash\x18\x04 \x01(\tR\bfileHash\x12\x1b\n" +
-	"\tfile_size\x18\x05 \x01(\x03R\bfileSize\x12#\n" +
-	"\rlast_modified\x18\x06 \x01(\tR\flastModified\"\xd1\x01\n" +
-	"\fSyncResponse\x126\n" +
-	"\x06status\x18\x01 \x01(\x0e2\x1e.proto.SyncResponse.SyncStatusR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
-	"\x11latest_version_id\x18\x03 \x01(\tR\x0flatestVersionId\"C\n" +
-	"\n" +
-	"SyncStatus\x12\n" +
-	"\n" +
-	"\x06SYNCED\x10\x00\x12\f\n" +
-	"\bCONFLICT\x10\x01\x12\x10\n" +
-	"\fNEEDS_UPDATE\x10\x02\x12\t\n" +
-	"\x05ERROR\x10\x03\"F\n" +
-	"\x12FileVersionRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x90\x01\n" +
-	"\vFileVersion\x12\x1d\n" +
-	"\n" +
-	"version_id\x18\x01 \x01(\tR\tversionId\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x02 \x01(\tR\tcreatedAt\x12\x1b\n" +
-	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x12\n" +
-	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x12\n" +
-	"\x04hash\x18\x05 \x01(\tR\x04hash\"E\n" +
-	"\x13FileVersionResponse\x12.\n" +
-	"\bversions\x18\x01 \x03(\v2\x12.proto.FileVersionR\bversions\"\xa9\x01\n" +
-	"\x19ConflictResolutionRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12,\n" +
-	"\x12winning_version_id\x18\x03 \x01(\tR\x10winningVersionId\x12,\n" +
-	"\x12losing_version_ids\x18\x04 \x03(\tR\x10losingVersionIds\"v\n" +
-	"\x1aConflictResolutionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
-	"\x0enew_version_id\x18\x03 \x01(\tR\fnewVersionId\"g\n" +
-	"\fWatchRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12!\n" +
-	"\ffolder_paths\x18\x03 \x03(\tR\vfolderPaths\"\xa8\x02\n" +
-	"\x0fFileChangeEvent\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
-	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x12B\n" +
-	"\vchange_type\x18\x03 \x01(\x0e2!.proto.FileChangeEvent.ChangeTypeR\n" +
-	"changeType\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x1b\n" +
-	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\x12\x1d\n" +
-	"\n" +
-	"version_id\x18\x06 \x01(\tR\tversionId\"A\n" +
-	"\n" +
-	"ChangeType\x12\v\n" +
-	"\aCREATED\x10\x00\x12\f\n" +
-	"\bMODIFIED\x10\x01\x12\v\n" +
-	"\aDELETED\x10\x02\x12\v\n" +
-	"\aRENAMED\x10\x032\xa7\x02\n" +
-	"\vSyncService\x123\n" +
-	"\bSyncFile\x12\x12.proto.SyncRequest\x1a\x13.proto.SyncResponse\x12H\n" +
-	"\x0fGetFileVersions\x12\x19.proto.FileVersionRequest\x1a\x1a.proto.FileVersionResponse\x12V\n" +
-	"\x0fResolveConflict\x12 .proto.ConflictResolutionRequest\x1a!.proto.ConflictResolutionResponse\x12A\n" +
-	"\x10WatchFileChanges\x12\x13.proto.WatchRequest\x1a\x16.proto.FileChangeEvent0\x01BPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
+func (x *FileChangeEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FileChangeEvent) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChangesRequest) Reset() {
+	*x = GetChangesRequest{}
+	mi := &file_internal_proto_sync_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesRequest) ProtoMessage() {}
+
+func (x *GetChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetChangesRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetChangesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetChangesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetChangesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*FileChangeEvent     `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChangesResponse) Reset() {
+	*x = GetChangesResponse{}
+	mi := &file_internal_proto_sync_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangesResponse) ProtoMessage() {}
+
+func (x *GetChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetChangesResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetChangesResponse) GetChanges() []*FileChangeEvent {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GetChangesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetChangesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type RestoreVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     string                 `protobuf:"bytes,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersionRequest) Reset() {
+	*x = RestoreVersionRequest{}
+	mi := &file_internal_proto_sync_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionRequest) ProtoMessage() {}
+
+func (x *RestoreVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreVersionRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RestoreVersionRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RestoreVersionRequest) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *RestoreVersionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RestoreVersionRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type RestoreVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	NewVersionId  string                 `protobuf:"bytes,3,opt,name=new_version_id,json=newVersionId,proto3" json:"new_version_id,omitempty"`
+	VersionNum    int32                  `protobuf:"varint,4,opt,name=version_num,json=versionNum,proto3" json:"version_num,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersionResponse) Reset() {
+	*x = RestoreVersionResponse{}
+	mi := &file_internal_proto_sync_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionResponse) ProtoMessage() {}
+
+func (x *RestoreVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreVersionResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RestoreVersionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RestoreVersionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RestoreVersionResponse) GetNewVersionId() string {
+	if x != nil {
+		return x.NewVersionId
+	}
+	return ""
+}
+
+func (x *RestoreVersionResponse) GetVersionNum() int32 {
+	if x != nil {
+		return x.VersionNum
+	}
+	return 0
+}
+
+type RestoreFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderPath    string                 `protobuf:"bytes,2,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,5,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFolderRequest) Reset() {
+	*x = RestoreFolderRequest{}
+	mi := &file_internal_proto_sync_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFolderRequest) ProtoMessage() {}
+
+func (x *RestoreFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFolderRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFolderRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RestoreFolderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RestoreFolderRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *RestoreFolderRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *RestoreFolderRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RestoreFolderRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type RestoreFolderChange struct {
+	state            protoimpl.MessageState     `protogen:"open.v1"`
+	FileId           string                     `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FilePath         string                     `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Action           RestoreFolderChange_Action `protobuf:"varint,3,opt,name=action,proto3,enum=proto.RestoreFolderChange_Action" json:"action,omitempty"`
+	CurrentVersionId string                     `protobuf:"bytes,4,opt,name=current_version_id,json=currentVersionId,proto3" json:"current_version_id,omitempty"`
+	TargetVersionId  string                     `protobuf:"bytes,5,opt,name=target_version_id,json=targetVersionId,proto3" json:"target_version_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RestoreFolderChange) Reset() {
+	*x = RestoreFolderChange{}
+	mi := &file_internal_proto_sync_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFolderChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFolderChange) ProtoMessage() {}
+
+func (x *RestoreFolderChange) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFolderChange.ProtoReflect.Descriptor instead.
+func (*RestoreFolderChange) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RestoreFolderChange) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RestoreFolderChange) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *RestoreFolderChange) GetAction() RestoreFolderChange_Action {
+	if x != nil {
+		return x.Action
+	}
+	return RestoreFolderChange_RESTORE
+}
+
+func (x *RestoreFolderChange) GetCurrentVersionId() string {
+	if x != nil {
+		return x.CurrentVersionId
+	}
+	return ""
+}
+
+func (x *RestoreFolderChange) GetTargetVersionId() string {
+	if x != nil {
+		return x.TargetVersionId
+	}
+	return ""
+}
+
+type RestoreFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	JobId         string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Applied       bool                   `protobuf:"varint,4,opt,name=applied,proto3" json:"applied,omitempty"`
+	Changes       []*RestoreFolderChange `protobuf:"bytes,5,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFolderResponse) Reset() {
+	*x = RestoreFolderResponse{}
+	mi := &file_internal_proto_sync_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFolderResponse) ProtoMessage() {}
+
+func (x *RestoreFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFolderResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFolderResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreFolderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RestoreFolderResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RestoreFolderResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *RestoreFolderResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *RestoreFolderResponse) GetChanges() []*RestoreFolderChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type DeviceAlert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       string                 `protobuf:"bytes,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	HeldChanges   int32                  `protobuf:"varint,7,opt,name=held_changes,json=heldChanges,proto3" json:"held_changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceAlert) Reset() {
+	*x = DeviceAlert{}
+	mi := &file_internal_proto_sync_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceAlert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceAlert) ProtoMessage() {}
+
+func (x *DeviceAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceAlert.ProtoReflect.Descriptor instead.
+func (*DeviceAlert) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeviceAlert) GetAlertId() string {
+	if x != nil {
+		return x.AlertId
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *DeviceAlert) GetHeldChanges() int32 {
+	if x != nil {
+		return x.HeldChanges
+	}
+	return 0
+}
+
+type ListDeviceAlertsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IncludeResolved bool                   `protobuf:"varint,2,opt,name=include_resolved,json=includeResolved,proto3" json:"include_resolved,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListDeviceAlertsRequest) Reset() {
+	*x = ListDeviceAlertsRequest{}
+	mi := &file_internal_proto_sync_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceAlertsRequest) ProtoMessage() {}
+
+func (x *ListDeviceAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeviceAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListDeviceAlertsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListDeviceAlertsRequest) GetIncludeResolved() bool {
+	if x != nil {
+		return x.IncludeResolved
+	}
+	return false
+}
+
+type ListDeviceAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*DeviceAlert         `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceAlertsResponse) Reset() {
+	*x = ListDeviceAlertsResponse{}
+	mi := &file_internal_proto_sync_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceAlertsResponse) ProtoMessage() {}
+
+func (x *ListDeviceAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeviceAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListDeviceAlertsResponse) GetAlerts() []*DeviceAlert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type ConfirmDeviceChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AlertId       string                 `protobuf:"bytes,2,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Approve       bool                   `protobuf:"varint,3,opt,name=approve,proto3" json:"approve,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmDeviceChangesRequest) Reset() {
+	*x = ConfirmDeviceChangesRequest{}
+	mi := &file_internal_proto_sync_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmDeviceChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmDeviceChangesRequest) ProtoMessage() {}
+
+func (x *ConfirmDeviceChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmDeviceChangesRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmDeviceChangesRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ConfirmDeviceChangesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ConfirmDeviceChangesRequest) GetAlertId() string {
+	if x != nil {
+		return x.AlertId
+	}
+	return ""
+}
+
+func (x *ConfirmDeviceChangesRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+type ConfirmDeviceChangesResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ReleasedChanges  int32                  `protobuf:"varint,3,opt,name=released_changes,json=releasedChanges,proto3" json:"released_changes,omitempty"`
+	DiscardedChanges int32                  `protobuf:"varint,4,opt,name=discarded_changes,json=discardedChanges,proto3" json:"discarded_changes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ConfirmDeviceChangesResponse) Reset() {
+	*x = ConfirmDeviceChangesResponse{}
+	mi := &file_internal_proto_sync_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmDeviceChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmDeviceChangesResponse) ProtoMessage() {}
+
+func (x *ConfirmDeviceChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_sync_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmDeviceChangesResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmDeviceChangesResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_sync_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ConfirmDeviceChangesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ConfirmDeviceChangesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ConfirmDeviceChangesResponse) GetReleasedChanges() int32 {
+	if x != nil {
+		return x.ReleasedChanges
+	}
+	return 0
+}
+
+func (x *ConfirmDeviceChangesResponse) GetDiscardedChanges() int32 {
+	if x != nil {
+		return x.DiscardedChanges
+	}
+	return 0
+}
+
+var File_internal_proto_sync_proto protoreflect.FileDescriptor
+
+const file_internal_proto_sync_proto_rawDesc = "" +
+	"\n" +
+	"\x19internal/proto/sync.proto\x12\x05proto\"\xe5\x02\n" +
+	"\vSyncRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1b\n" +
+	"\tfile_hash\x18\x04 \x01(\tR\bfileHash\x12\x1b\n" +
+	"\tfile_size\x18\x05 \x01(\x03R\bfileSize\x12#\n" +
+	"\rlast_modified\x18\x06 \x01(\tR\flastModified\x12L\n" +
+	"\x0eversion_vector\x18\a \x03(\v2%.proto.SyncRequest.VersionVectorEntryR\rversionVector\x12\x18\n" +
+	"\acontent\x18\b \x01(\fR\acontent\x1a@\n" +
+	"\x12VersionVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"\xe4\x03\n" +
+	"\fSyncResponse\x126\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x1e.proto.SyncResponse.SyncStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\x11latest_version_id\x18\x03 \x01(\tR\x0flatestVersionId\x12M\n" +
+	"\x0eversion_vector\x18\x04 \x03(\v2&.proto.SyncResponse.VersionVectorEntryR\rversionVector\x125\n" +
+	"\x17conflicted_copy_file_id\x18\x05 \x01(\tR\x14conflictedCopyFileId\x12+\n" +
+	"\x11conflicting_paths\x18\x06 \x03(\tR\x10conflictingPaths\x1a@\n" +
+	"\x12VersionVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"a\n" +
+	"\n" +
+	"SyncStatus\x12\n" +
+	"\n" +
+	"\x06SYNCED\x10\x00\x12\f\n" +
+	"\bCONFLICT\x10\x01\x12\x10\n" +
+	"\fNEEDS_UPDATE\x10\x02\x12\t\n" +
+	"\x05ERROR\x10\x03\x12\x10\n" +
+	"\fFAST_FORWARD\x10\x04\x12\n" +
+	"\n" +
+	"\x06MERGED\x10\x05\"F\n" +
+	"\x12FileVersionRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x8d\x03\n" +
+	"\vFileVersion\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x01 \x01(\tR\tversionId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\tR\tcreatedAt\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04hash\x18\x05 \x01(\tR\x04hash\x12\x1f\n" +
+	"\vversion_num\x18\x06 \x01(\x05R\n" +
+	"versionNum\x12#\n" +
+	"\rsuperseded_by\x18\a \x01(\tR\fsupersededBy\x12L\n" +
+	"\x0eversion_vector\x18\b \x03(\v2%.proto.FileVersion.VersionVectorEntryR\rversionVector\x12%\n" +
+	"\x0econflicts_with\x18\t \x01(\tR\rconflictsWith\x1a@\n" +
+	"\x12VersionVectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"E\n" +
+	"\x13FileVersionResponse\x12.\n" +
+	"\bversions\x18\x01 \x03(\v2\x12.proto.FileVersionR\bversions\"\xfc\x02\n" +
+	"\x19ConflictResolutionRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12,\n" +
+	"\x12winning_version_id\x18\x03 \x01(\tR\x10winningVersionId\x12,\n" +
+	"\x12losing_version_ids\x18\x04 \x03(\tR\x10losingVersionIds\x12E\n" +
+	"\bstrategy\x18\x05 \x01(\x0e2).proto.ConflictResolutionRequest.StrategyR\bstrategy\x12\x1b\n" +
+	"\tdevice_id\x18\x06 \x01(\tR\bdeviceId\x12%\n" +
+	"\x0emerged_content\x18\a \x01(\fR\rmergedContent\"F\n" +
+	"\bStrategy\x12\x0f\n" +
+	"\vPICK_WINNER\x10\x00\x12\r\n" +
+	"\tKEEP_BOTH\x10\x01\x12\n" +
+	"\n" +
+	"\x06MERGED\x10\x02\x12\x0e\n" +
+	"\n" +
+	"AUTO_MERGE\x10\x03\"\x92\x02\n" +
+	"\x1aConflictResolutionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x0enew_version_id\x18\x03 \x01(\tR\fnewVersionId\x124\n" +
+	"\x16superseded_version_ids\x18\x04 \x03(\tR\x14supersededVersionIds\x127\n" +
+	"\x18conflicted_copy_file_ids\x18\x05 \x03(\tR\x15conflictedCopyFileIds\x12+\n" +
+	"\x11conflicting_paths\x18\x06 \x03(\tR\x10conflictingPaths\"\x83\x01\n" +
+	"\fWatchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12%\n" +
+	"\ffolder_paths\x18\x03 \x03(\tB\x02\x18\x01R\vfolderPaths\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\x8a\x03\n" +
+	"\x0fFileChangeEvent\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x12B\n" +
+	"\vchange_type\x18\x03 \x01(\x0e2!.proto.FileChangeEvent.ChangeTypeR\n" +
+	"changeType\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12\x1b\n" +
+	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x06 \x01(\tR\tversionId\x12\x19\n" +
+	"\balert_id\x18\a \x01(\tR\aalertId\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\"X\n" +
+	"\n" +
+	"ChangeType\x12\v\n" +
+	"\aCREATED\x10\x00\x12\f\n" +
+	"\bMODIFIED\x10\x01\x12\v\n" +
+	"\aDELETED\x10\x02\x12\v\n" +
+	"\aRENAMED\x10\x03\x12\t\n" +
+	"\x05ALERT\x10\x04\x12\n" +
+	"\n" +
+	"\x06SHARED\x10\x05\"Z\n" +
+	"\x11GetChangesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x82\x01\n" +
+	"\x12GetChangesResponse\x120\n" +
+	"\achanges\x18\x01 \x03(\v2\x16.proto.FileChangeEventR\achanges\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMore\"\x85\x01\n" +
+	"\x15RestoreVersionRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\"\x93\x01\n" +
+	"\x16RestoreVersionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x0enew_version_id\x18\x03 \x01(\tR\fnewVersionId\x12\x1f\n" +
+	"\vversion_num\x18\x04 \x01(\x05R\n" +
+	"versionNum\"\xa4\x01\n" +
+	"\x14RestoreFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vfolder_path\x18\x02 \x01(\tR\n" +
+	"folderPath\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\tR\ttimestamp\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x1b\n" +
+	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\"\x91\x02\n" +
+	"\x13RestoreFolderChange\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x129\n" +
+	"\x06action\x18\x03 \x01(\x0e2!.proto.RestoreFolderChange.ActionR\x06action\x12,\n" +
+	"\x12current_version_id\x18\x04 \x01(\tR\x10currentVersionId\x12*\n" +
+	"\x11target_version_id\x18\x05 \x01(\tR\x0ftargetVersionId\"/\n" +
+	"\x06Action\x12\v\n" +
+	"\aRESTORE\x10\x00\x12\f\n" +
+	"\bUNDELETE\x10\x01\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x02\"\xb2\x01\n" +
+	"\x15RestoreFolderResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\x12\x18\n" +
+	"\aapplied\x18\x04 \x01(\bR\aapplied\x124\n" +
+	"\achanges\x18\x05 \x03(\v2\x1a.proto.RestoreFolderChangeR\achanges\"\xcb\x01\n" +
+	"\vDeviceAlert\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\tR\aalertId\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12!\n" +
+	"\fheld_changes\x18\a \x01(\x05R\vheldChanges\"]\n" +
+	"\x17ListDeviceAlertsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
+	"\x10include_resolved\x18\x02 \x01(\bR\x0fincludeResolved\"F\n" +
+	"\x18ListDeviceAlertsResponse\x12*\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x12.proto.DeviceAlertR\x06alerts\"k\n" +
+	"\x1bConfirmDeviceChangesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\balert_id\x18\x02 \x01(\tR\aalertId\x12\x18\n" +
+	"\aapprove\x18\x03 \x01(\bR\aapprove\"\xaa\x01\n" +
+	"\x1cConfirmDeviceChangesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12)\n" +
+	"\x10released_changes\x18\x03 \x01(\x05R\x0freleasedChanges\x12+\n" +
+	"\x11discarded_changes\x18\x04 \x01(\x05R\x10discardedChanges2\xbb\x05\n" +
+	"\vSyncService\x123\n" +
+	"\bSyncFile\x12\x12.proto.SyncRequest\x1a\x13.proto.SyncResponse\x12H\n" +
+	"\x0fGetFileVersions\x12\x19.proto.FileVersionRequest\x1a\x1a.proto.FileVersionResponse\x12V\n" +
+	"\x0fResolveConflict\x12 .proto.ConflictResolutionRequest\x1a!.proto.ConflictResolutionResponse\x12A\n" +
+	"\x10WatchFileChanges\x12\x13.proto.WatchRequest\x1a\x16.proto.FileChangeEvent0\x01\x12A\n" +
+	"\n" +
+	"GetChanges\x12\x18.proto.GetChangesRequest\x1a\x19.proto.GetChangesResponse\x12M\n" +
+	"\x0eRestoreVersion\x12\x1c.proto.RestoreVersionRequest\x1a\x1d.proto.RestoreVersionResponse\x12J\n" +
+	"\rRestoreFolder\x12\x1b.proto.RestoreFolderRequest\x1a\x1c.proto.RestoreFolderResponse\x12S\n" +
+	"\x10ListDeviceAlerts\x12\x1e.proto.ListDeviceAlertsRequest\x1a\x1f.proto.ListDeviceAlertsResponse\x12_\n" +
+	"\x14ConfirmDeviceChanges\x12\".proto.ConfirmDeviceChangesRequest\x1a#.proto.ConfirmDeviceChangesResponseBPZNgithub.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/protob\x06proto3"
 
 var (
 	file_internal_proto_sync_proto_rawDescOnce sync.Once
@@ -795,38 +1986,73 @@ func file_internal_proto_sync_proto_rawDescGZIP() []byte {
 	return file_internal_proto_sync_proto_rawDescData
 }
 
-var file_internal_proto_sync_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_internal_proto_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_internal_proto_sync_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_internal_proto_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_internal_proto_sync_proto_goTypes = []any{
-	(SyncResponse_SyncStatus)(0),       // 0: proto.SyncResponse.SyncStatus
-	(FileChangeEvent_ChangeType)(0),    // 1: proto.FileChangeEvent.ChangeType
-	(*SyncRequest)(nil),                // 2: proto.SyncRequest
-	(*SyncResponse)(nil),               // 3: proto.SyncResponse
-	(*FileVersionRequest)(nil),         // 4: proto.FileVersionRequest
-	(*FileVersion)(nil),                // 5: proto.FileVersion
-	(*FileVersionResponse)(nil),        // 6: proto.FileVersionResponse
-	(*ConflictResolutionRequest)(nil),  // 7: proto.ConflictResolutionRequest
-	(*ConflictResolutionResponse)(nil), // 8: proto.ConflictResolutionResponse
-	(*WatchRequest)(nil),               // 9: proto.WatchRequest
-	(*FileChangeEvent)(nil),            // 10: proto.FileChangeEvent
+	(SyncResponse_SyncStatus)(0),            // 0: proto.SyncResponse.SyncStatus
+	(ConflictResolutionRequest_Strategy)(0), // 1: proto.ConflictResolutionRequest.Strategy
+	(FileChangeEvent_ChangeType)(0),         // 2: proto.FileChangeEvent.ChangeType
+	(RestoreFolderChange_Action)(0),         // 3: proto.RestoreFolderChange.Action
+	(*SyncRequest)(nil),                     // 4: proto.SyncRequest
+	(*SyncResponse)(nil),                    // 5: proto.SyncResponse
+	(*FileVersionRequest)(nil),              // 6: proto.FileVersionRequest
+	(*FileVersion)(nil),                     // 7: proto.FileVersion
+	(*FileVersionResponse)(nil),             // 8: proto.FileVersionResponse
+	(*ConflictResolutionRequest)(nil),       // 9: proto.ConflictResolutionRequest
+	(*ConflictResolutionResponse)(nil),      // 10: proto.ConflictResolutionResponse
+	(*WatchRequest)(nil),                    // 11: proto.WatchRequest
+	(*FileChangeEvent)(nil),                 // 12: proto.FileChangeEvent
+	(*GetChangesRequest)(nil),               // 13: proto.GetChangesRequest
+	(*GetChangesResponse)(nil),              // 14: proto.GetChangesResponse
+	(*RestoreVersionRequest)(nil),           // 15: proto.RestoreVersionRequest
+	(*RestoreVersionResponse)(nil),          // 16: proto.RestoreVersionResponse
+	(*RestoreFolderRequest)(nil),            // 17: proto.RestoreFolderRequest
+	(*RestoreFolderChange)(nil),             // 18: proto.RestoreFolderChange
+	(*RestoreFolderResponse)(nil),           // 19: proto.RestoreFolderResponse
+	(*DeviceAlert)(nil),                     // 20: proto.DeviceAlert
+	(*ListDeviceAlertsRequest)(nil),         // 21: proto.ListDeviceAlertsRequest
+	(*ListDeviceAlertsResponse)(nil),        // 22: proto.ListDeviceAlertsResponse
+	(*ConfirmDeviceChangesRequest)(nil),     // 23: proto.ConfirmDeviceChangesRequest
+	(*ConfirmDeviceChangesResponse)(nil),    // 24: proto.ConfirmDeviceChangesResponse
+	nil,                                     // 25: proto.SyncRequest.VersionVectorEntry
+	nil,                                     // 26: proto.SyncResponse.VersionVectorEntry
+	nil,                                     // 27: proto.FileVersion.VersionVectorEntry
 }
 var file_internal_proto_sync_proto_depIdxs = []int32{
-	0,  // 0: proto.SyncResponse.status:type_name -> proto.SyncResponse.SyncStatus
-	5,  // 1: proto.FileVersionResponse.versions:type_name -> proto.FileVersion
-	1,  // 2: proto.FileChangeEvent.change_type:type_name -> proto.FileChangeEvent.ChangeType
-	2,  // 3: proto.SyncService.SyncFile:input_type -> proto.SyncRequest
-	4,  // 4: proto.SyncService.GetFileVersions:input_type -> proto.FileVersionRequest
-	7,  // 5: proto.SyncService.ResolveConflict:input_type -> proto.ConflictResolutionRequest
-	9,  // 6: proto.SyncService.WatchFileChanges:input_type -> proto.WatchRequest
-	3,  // 7: proto.SyncService.SyncFile:output_type -> proto.SyncResponse
-	6,  // 8: proto.SyncService.GetFileVersions:output_type -> proto.FileVersionResponse
-	8,  // 9: proto.SyncService.ResolveConflict:output_type -> proto.ConflictResolutionResponse
-	10, // 10: proto.SyncService.WatchFileChanges:output_type -> proto.FileChangeEvent
-	7,  // [7:11] is the sub-list for method output_type
-	3,  // [3:7] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	25, // 0: proto.SyncRequest.version_vector:type_name -> proto.SyncRequest.VersionVectorEntry
+	0,  // 1: proto.SyncResponse.status:type_name -> proto.SyncResponse.SyncStatus
+	26, // 2: proto.SyncResponse.version_vector:type_name -> proto.SyncResponse.VersionVectorEntry
+	27, // 3: proto.FileVersion.version_vector:type_name -> proto.FileVersion.VersionVectorEntry
+	7,  // 4: proto.FileVersionResponse.versions:type_name -> proto.FileVersion
+	1,  // 5: proto.ConflictResolutionRequest.strategy:type_name -> proto.ConflictResolutionRequest.Strategy
+	2,  // 6: proto.FileChangeEvent.change_type:type_name -> proto.FileChangeEvent.ChangeType
+	12, // 7: proto.GetChangesResponse.changes:type_name -> proto.FileChangeEvent
+	3,  // 8: proto.RestoreFolderChange.action:type_name -> proto.RestoreFolderChange.Action
+	18, // 9: proto.RestoreFolderResponse.changes:type_name -> proto.RestoreFolderChange
+	20, // 10: proto.ListDeviceAlertsResponse.alerts:type_name -> proto.DeviceAlert
+	4,  // 11: proto.SyncService.SyncFile:input_type -> proto.SyncRequest
+	6,  // 12: proto.SyncService.GetFileVersions:input_type -> proto.FileVersionRequest
+	9,  // 13: proto.SyncService.ResolveConflict:input_type -> proto.ConflictResolutionRequest
+	11, // 14: proto.SyncService.WatchFileChanges:input_type -> proto.WatchRequest
+	13, // 15: proto.SyncService.GetChanges:input_type -> proto.GetChangesRequest
+	15, // 16: proto.SyncService.RestoreVersion:input_type -> proto.RestoreVersionRequest
+	17, // 17: proto.SyncService.RestoreFolder:input_type -> proto.RestoreFolderRequest
+	21, // 18: proto.SyncService.ListDeviceAlerts:input_type -> proto.ListDeviceAlertsRequest
+	23, // 19: proto.SyncService.ConfirmDeviceChanges:input_type -> proto.ConfirmDeviceChangesRequest
+	5,  // 20: proto.SyncService.SyncFile:output_type -> proto.SyncResponse
+	8,  // 21: proto.SyncService.GetFileVersions:output_type -> proto.FileVersionResponse
+	10, // 22: proto.SyncService.ResolveConflict:output_type -> proto.ConflictResolutionResponse
+	12, // 23: proto.SyncService.WatchFileChanges:output_type -> proto.FileChangeEvent
+	14, // 24: proto.SyncService.GetChanges:output_type -> proto.GetChangesResponse
+	16, // 25: proto.SyncService.RestoreVersion:output_type -> proto.RestoreVersionResponse
+	19, // 26: proto.SyncService.RestoreFolder:output_type -> proto.RestoreFolderResponse
+	22, // 27: proto.SyncService.ListDeviceAlerts:output_type -> proto.ListDeviceAlertsResponse
+	24, // 28: proto.SyncService.ConfirmDeviceChanges:output_type -> proto.ConfirmDeviceChangesResponse
+	20, // [20:29] is the sub-list for method output_type
+	11, // [11:20] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_sync_proto_init() }
@@ -839,8 +2065,8 @@ func file_internal_proto_sync_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_sync_proto_rawDesc), len(file_internal_proto_sync_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   9,
+			NumEnums:      4,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
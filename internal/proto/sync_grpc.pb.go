@@ -19,10 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SyncService_SyncFile_FullMethodName         = "/proto.SyncService/SyncFile"
-	SyncService_GetFileVersions_FullMethodName  = "/proto.SyncService/GetFileVersions"
-	SyncService_ResolveConflict_FullMethodName  = "/proto.SyncService/ResolveConflict"
-	SyncService_WatchFileChanges_FullMethodName = "/proto.SyncService/WatchFileChanges"
+	SyncService_SyncFile_FullMethodName             = "/proto.SyncService/SyncFile"
+	SyncService_GetFileVersions_FullMethodName      = "/proto.SyncService/GetFileVersions"
+	SyncService_ResolveConflict_FullMethodName      = "/proto.SyncService/ResolveConflict"
+	SyncService_WatchFileChanges_FullMethodName     = "/proto.SyncService/WatchFileChanges"
+	SyncService_GetChanges_FullMethodName           = "/proto.SyncService/GetChanges"
+	SyncService_RestoreVersion_FullMethodName       = "/proto.SyncService/RestoreVersion"
+	SyncService_RestoreFolder_FullMethodName        = "/proto.SyncService/RestoreFolder"
+	SyncService_ListDeviceAlerts_FullMethodName     = "/proto.SyncService/ListDeviceAlerts"
+	SyncService_ConfirmDeviceChanges_FullMethodName = "/proto.SyncService/ConfirmDeviceChanges"
 )
 
 // SyncServiceClient is the client API for SyncService service.
@@ -33,6 +38,11 @@ type SyncServiceClient interface {
 	GetFileVersions(ctx context.Context, in *FileVersionRequest, opts ...grpc.CallOption) (*FileVersionResponse, error)
 	ResolveConflict(ctx context.Context, in *ConflictResolutionRequest, opts ...grpc.CallOption) (*ConflictResolutionResponse, error)
 	WatchFileChanges(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChangeEvent], error)
+	GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error)
+	RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error)
+	RestoreFolder(ctx context.Context, in *RestoreFolderRequest, opts ...grpc.CallOption) (*RestoreFolderResponse, error)
+	ListDeviceAlerts(ctx context.Context, in *ListDeviceAlertsRequest, opts ...grpc.CallOption) (*ListDeviceAlertsResponse, error)
+	ConfirmDeviceChanges(ctx context.Context, in *ConfirmDeviceChangesRequest, opts ...grpc.CallOption) (*ConfirmDeviceChangesResponse, error)
 }
 
 type syncServiceClient struct {
@@ -92,6 +102,56 @@ func (c *syncServiceClient) WatchFileChanges(ctx context.Context, in *WatchReque
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SyncService_WatchFileChangesClient = grpc.ServerStreamingClient[FileChangeEvent]
 
+func (c *syncServiceClient) GetChanges(ctx context.Context, in *GetChangesRequest, opts ...grpc.CallOption) (*GetChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChangesResponse)
+	err := c.cc.Invoke(ctx, SyncService_GetChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncServiceClient) RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*RestoreVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreVersionResponse)
+	err := c.cc.Invoke(ctx, SyncService_RestoreVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncServiceClient) RestoreFolder(ctx context.Context, in *RestoreFolderRequest, opts ...grpc.CallOption) (*RestoreFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFolderResponse)
+	err := c.cc.Invoke(ctx, SyncService_RestoreFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncServiceClient) ListDeviceAlerts(ctx context.Context, in *ListDeviceAlertsRequest, opts ...grpc.CallOption) (*ListDeviceAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeviceAlertsResponse)
+	err := c.cc.Invoke(ctx, SyncService_ListDeviceAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncServiceClient) ConfirmDeviceChanges(ctx context.Context, in *ConfirmDeviceChangesRequest, opts ...grpc.CallOption) (*ConfirmDeviceChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmDeviceChangesResponse)
+	err := c.cc.Invoke(ctx, SyncService_ConfirmDeviceChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SyncServiceServer is the server API for SyncService service.
 // All implementations must embed UnimplementedSyncServiceServer
 // for forward compatibility.
@@ -100,6 +160,11 @@ type SyncServiceServer interface {
 	GetFileVersions(context.Context, *FileVersionRequest) (*FileVersionResponse, error)
 	ResolveConflict(context.Context, *ConflictResolutionRequest) (*ConflictResolutionResponse, error)
 	WatchFileChanges(*WatchRequest, grpc.ServerStreamingServer[FileChangeEvent]) error
+	GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error)
+	RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error)
+	RestoreFolder(context.Context, *RestoreFolderRequest) (*RestoreFolderResponse, error)
+	ListDeviceAlerts(context.Context, *ListDeviceAlertsRequest) (*ListDeviceAlertsResponse, error)
+	ConfirmDeviceChanges(context.Context, *ConfirmDeviceChangesRequest) (*ConfirmDeviceChangesResponse, error)
 	mustEmbedUnimplementedSyncServiceServer()
 }
 
@@ -122,6 +187,21 @@ func (UnimplementedSyncServiceServer) ResolveConflict(context.Context, *Conflict
 func (UnimplementedSyncServiceServer) WatchFileChanges(*WatchRequest, grpc.ServerStreamingServer[FileChangeEvent]) error {
 	return status.Errorf(codes.Unimplemented, "method WatchFileChanges not implemented")
 }
+func (UnimplementedSyncServiceServer) GetChanges(context.Context, *GetChangesRequest) (*GetChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChanges not implemented")
+}
+func (UnimplementedSyncServiceServer) RestoreVersion(context.Context, *RestoreVersionRequest) (*RestoreVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+}
+func (UnimplementedSyncServiceServer) RestoreFolder(context.Context, *RestoreFolderRequest) (*RestoreFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFolder not implemented")
+}
+func (UnimplementedSyncServiceServer) ListDeviceAlerts(context.Context, *ListDeviceAlertsRequest) (*ListDeviceAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeviceAlerts not implemented")
+}
+func (UnimplementedSyncServiceServer) ConfirmDeviceChanges(context.Context, *ConfirmDeviceChangesRequest) (*ConfirmDeviceChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmDeviceChanges not implemented")
+}
 func (UnimplementedSyncServiceServer) mustEmbedUnimplementedSyncServiceServer() {}
 func (UnimplementedSyncServiceServer) testEmbeddedByValue()                     {}
 
@@ -208,6 +288,96 @@ func _SyncService_WatchFileChanges_Handler(srv interface{}, stream grpc.ServerSt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SyncService_WatchFileChangesServer = grpc.ServerStreamingServer[FileChangeEvent]
 
+func _SyncService_GetChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).GetChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_GetChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).GetChanges(ctx, req.(*GetChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncService_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).RestoreVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_RestoreVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).RestoreVersion(ctx, req.(*RestoreVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncService_RestoreFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).RestoreFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_RestoreFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).RestoreFolder(ctx, req.(*RestoreFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncService_ListDeviceAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeviceAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).ListDeviceAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_ListDeviceAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).ListDeviceAlerts(ctx, req.(*ListDeviceAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncService_ConfirmDeviceChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmDeviceChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).ConfirmDeviceChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_ConfirmDeviceChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).ConfirmDeviceChanges(ctx, req.(*ConfirmDeviceChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SyncService_ServiceDesc is the grpc.ServiceDesc for SyncService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -227,6 +397,26 @@ var SyncService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResolveConflict",
 			Handler:    _SyncService_ResolveConflict_Handler,
 		},
+		{
+			MethodName: "GetChanges",
+			Handler:    _SyncService_GetChanges_Handler,
+		},
+		{
+			MethodName: "RestoreVersion",
+			Handler:    _SyncService_RestoreVersion_Handler,
+		},
+		{
+			MethodName: "RestoreFolder",
+			Handler:    _SyncService_RestoreFolder_Handler,
+		},
+		{
+			MethodName: "ListDeviceAlerts",
+			Handler:    _SyncService_ListDeviceAlerts_Handler,
+		},
+		{
+			MethodName: "ConfirmDeviceChanges",
+			Handler:    _SyncService_ConfirmDeviceChanges_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
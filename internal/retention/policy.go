@@ -0,0 +1,166 @@
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func Validate(policy *models.RetentionPolicy) error {
+	switch policy.Scope {
+	case models.RetentionScopeUser, models.RetentionScopeOrg:
+		if policy.FolderPath != "" {
+			return fmt.Errorf("folder path is only valid for folder policies")
+		}
+	case models.RetentionScopeFolder:
+		if policy.FolderPath == "" {
+			return fmt.Errorf("folder policies require a folder path")
+		}
+	default:
+		return fmt.Errorf("unknown retention scope: %s", policy.Scope)
+	}
+
+	if policy.ScopeID == "" {
+		return fmt.Errorf("scope id is required")
+	}
+	if policy.KeepLast < 0 || policy.KeepHourlyForHours < 0 || policy.KeepDailyForDays < 0 || policy.KeepAllForDays < 0 {
+		return fmt.Errorf("retention rules cannot be negative")
+	}
+
+	// A policy without rules would prune everything but the latest version,
+	// which is never what anyone means.
+	if !policy.KeepForever && policy.KeepLast == 0 && policy.KeepHourlyForHours == 0 &&
+		policy.KeepDailyForDays == 0 && policy.KeepAllForDays == 0 {
+		return fmt.Errorf("retention policy must keep something")
+	}
+
+	return nil
+}
+
+// Resolve returns the policy that governs a file: the deepest folder
+// policy of its owner, then the owner's policy, then the owner's
+// organization policy. It returns nil when no policy applies, in which
+// case every version is kept.
+func Resolve(db *gorm.DB, file *models.File) (*models.RetentionPolicy, error) {
+	var folderPolicies []models.RetentionPolicy
+	if err := db.Where("scope = ? AND scope_id = ?", models.RetentionScopeFolder, file.OwnerID).
+		Find(&folderPolicies).Error; err != nil {
+		return nil, err
+	}
+
+	var best *models.RetentionPolicy
+	for i := range folderPolicies {
+		policy := &folderPolicies[i]
+		if !strings.HasPrefix(file.Path, policy.FolderPath) {
+			continue
+		}
+		if best == nil || len(policy.FolderPath) > len(best.FolderPath) {
+			best = policy
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+
+	var policy models.RetentionPolicy
+	err := db.Where("scope = ? AND scope_id = ?", models.RetentionScopeUser, file.OwnerID).First(&policy).Error
+	if err == nil {
+		return &policy, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var owner models.User
+	if err := db.First(&owner, "id = ?", file.OwnerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if owner.OrgID == "" {
+		return nil, nil
+	}
+
+	err = db.Where("scope = ? AND scope_id = ?", models.RetentionScopeOrg, owner.OrgID).First(&policy).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Expired returns the versions the policy no longer keeps. The latest
+// version and pinned versions are always kept.
+func Expired(versions []models.FileVersion, policy *models.RetentionPolicy, now time.Time) []models.FileVersion {
+	if policy == nil || policy.KeepForever || len(versions) == 0 {
+		return nil
+	}
+
+	sorted := append([]models.FileVersion(nil), versions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].VersionNum != sorted[j].VersionNum {
+			return sorted[i].VersionNum > sorted[j].VersionNum
+		}
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	keep := make(map[string]bool, len(sorted))
+	keep[sorted[0].ID] = true
+
+	for i, v := range sorted {
+		if v.Pinned || i < policy.KeepLast {
+			keep[v.ID] = true
+		}
+		if policy.KeepAllForDays > 0 && now.Sub(v.CreatedAt) < days(policy.KeepAllForDays) {
+			keep[v.ID] = true
+		}
+	}
+
+	// Keep the newest version in every bucket inside the window. Versions
+	// are sorted newest first, so the first one seen in a bucket wins.
+	keepBuckets := func(window time.Duration, bucket func(time.Time) time.Time) {
+		seen := make(map[time.Time]bool)
+		for _, v := range sorted {
+			if now.Sub(v.CreatedAt) >= window {
+				continue
+			}
+			b := bucket(v.CreatedAt)
+			if !seen[b] {
+				seen[b] = true
+				keep[v.ID] = true
+			}
+		}
+	}
+
+	if policy.KeepHourlyForHours > 0 {
+		keepBuckets(time.Duration(policy.KeepHourlyForHours)*time.Hour, func(t time.Time) time.Time {
+			return t.UTC().Truncate(time.Hour)
+		})
+	}
+	if policy.KeepDailyForDays > 0 {
+		keepBuckets(days(policy.KeepDailyForDays), func(t time.Time) time.Time {
+			y, m, d := t.UTC().Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		})
+	}
+
+	var expired []models.FileVersion
+	for _, v := range sorted {
+		if !keep[v.ID] {
+			expired = append(expired, v)
+		}
+	}
+	return expired
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
@@ -0,0 +1,152 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunkstore"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/versioning"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	pruneBatchSize = 100
+	// chunkGracePeriod keeps chunks that an upload in progress may still
+	// reference.
+	chunkGracePeriod = time.Hour
+)
+
+// Pruner deletes versions that their retention policy no longer keeps,
+// together with blobs that nothing references any more.
+type Pruner struct {
+	db     *gorm.DB
+	chunks *chunkstore.Store
+}
+
+func NewPruner(db *gorm.DB, chunks *chunkstore.Store) *Pruner {
+	return &Pruner{
+		db:     db,
+		chunks: chunks,
+	}
+}
+
+// Start prunes on the given interval until the context is cancelled.
+func (p *Pruner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := p.PruneOnce(ctx)
+			if err != nil {
+				log.Printf("Version pruning failed: %v", err)
+				continue
+			}
+			if pruned > 0 {
+				log.Printf("Pruned %d expired versions", pruned)
+			}
+		}
+	}
+}
+
+// PruneOnce walks every file once and returns the number of versions
+// deleted.
+func (p *Pruner) PruneOnce(ctx context.Context) (int, error) {
+	pruned := 0
+	lastID := ""
+
+	for {
+		var fileIDs []string
+		if err := p.db.WithContext(ctx).Model(&models.File{}).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(pruneBatchSize).
+			Pluck("id", &fileIDs).Error; err != nil {
+			return pruned, err
+		}
+		if len(fileIDs) == 0 {
+			return pruned, nil
+		}
+
+		for _, fileID := range fileIDs {
+			if ctx.Err() != nil {
+				return pruned, ctx.Err()
+			}
+
+			n, err := p.pruneFile(ctx, fileID)
+			if err != nil {
+				log.Printf("Failed to prune file %s: %v", fileID, err)
+				continue
+			}
+			pruned += n
+		}
+		lastID = fileIDs[len(fileIDs)-1]
+	}
+}
+
+func (p *Pruner) pruneFile(ctx context.Context, fileID string) (int, error) {
+	var hashes []string
+	var objectKeys []string
+	var expired []models.FileVersion
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the file so no version is added while the set is decided.
+		var file models.File
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(versioning.PreloadVersions).
+			First(&file, "id = ?", fileID).Error; err != nil {
+			return err
+		}
+
+		policy, err := Resolve(tx, &file)
+		if err != nil {
+			return err
+		}
+
+		expired = Expired(file.Versions, policy, time.Now())
+		if len(expired) == 0 {
+			return nil
+		}
+
+		versionIDs := make([]string, len(expired))
+		for i, v := range expired {
+			versionIDs[i] = v.ID
+			objectKeys = append(objectKeys, v.S3Key)
+		}
+
+		if err := tx.Model(&models.VersionChunk{}).
+			Where("version_id IN ?", versionIDs).
+			Distinct().
+			Pluck("chunk_hash", &hashes).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("version_id IN ?", versionIDs).Delete(&models.VersionChunk{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", versionIDs).Delete(&models.FileVersion{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Blobs go only after the versions are committed as deleted, and only
+	// when no other version shares them.
+	if _, err := p.chunks.DeleteUnreferenced(ctx, hashes, chunkGracePeriod); err != nil {
+		return len(expired), err
+	}
+	for _, key := range objectKeys {
+		if err := p.chunks.ReleaseObject(ctx, key); err != nil {
+			return len(expired), err
+		}
+	}
+
+	return len(expired), nil
+}
@@ -0,0 +1,215 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// screenChange decides, within the transaction that handles a change,
+// whether it may be propagated. Changes from a paused device, and the
+// change that gets a device paused, are held until the user confirms them.
+// Changes no device made, such as shares, pass unscreened.
+func (s *SyncService) screenChange(tx *gorm.DB, env *proto.EventEnvelope) (bool, error) {
+	if env.Confirmed || env.DeviceId == "" || env.DeviceId == systemDeviceID {
+		return true, nil
+	}
+
+	var alert models.DeviceAlert
+	err := tx.Where("user_id = ? AND device_id = ? AND status = ?", env.UserId, env.DeviceId, models.DeviceAlertPaused).
+		First(&alert).Error
+	if err == nil {
+		return false, holdChange(tx, alert.ID, env)
+	}
+	if err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+
+	detected, err := s.detector.Observe(tx, env)
+	if err != nil || detected == nil {
+		return err == nil, err
+	}
+
+	alert = models.DeviceAlert{
+		UserID:   detected.UserID,
+		DeviceID: detected.DeviceID,
+		Kind:     string(detected.Kind),
+		Reason:   detected.Reason,
+		Status:   models.DeviceAlertPaused,
+	}
+	// The predicate is spelled out as in the index, so that the database
+	// can match the index to it.
+	result := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'paused'"}}},
+		DoNothing:   true,
+	}).Create(&alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Another instance paused the device first.
+		if err := tx.Where("user_id = ? AND device_id = ? AND status = ?", env.UserId, env.DeviceId, models.DeviceAlertPaused).
+			First(&alert).Error; err != nil {
+			return false, err
+		}
+		return false, holdChange(tx, alert.ID, env)
+	}
+
+	log.Printf("Paused device %s of user %s: %s", alert.DeviceID, alert.UserID, alert.Reason)
+
+	if err := holdChange(tx, alert.ID, env); err != nil {
+		return false, err
+	}
+	if _, err := journal.RecordAlert(tx, &alert, events.Anomaly); err != nil {
+		return false, err
+	}
+
+	anomaly, err := events.New(tx, alert.UserID, alert.DeviceID)
+	if err != nil {
+		return false, err
+	}
+	events.CausedBy(anomaly, env)
+	anomaly.Payload = &proto.EventEnvelope_DeviceAnomaly{DeviceAnomaly: &proto.DeviceAnomaly{
+		AlertId: alert.ID,
+		Kind:    alert.Kind,
+		Reason:  alert.Reason,
+	}}
+	return false, outbox.EnqueueEvent(tx, anomaly)
+}
+
+func holdChange(tx *gorm.DB, alertID string, env *proto.EventEnvelope) error {
+	payload, err := events.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.HeldChange{
+		AlertID: alertID,
+		Payload: payload,
+	}).Error
+}
+
+func (s *SyncService) ListDeviceAlerts(ctx context.Context, req *proto.ListDeviceAlertsRequest) (*proto.ListDeviceAlertsResponse, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", req.UserId)
+	if !req.IncludeResolved {
+		query = query.Where("status = ?", models.DeviceAlertPaused)
+	}
+
+	var alerts []models.DeviceAlert
+	if err := query.Order("created_at DESC").Find(&alerts).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list alerts: %v", err)
+	}
+
+	result := make([]*proto.DeviceAlert, len(alerts))
+	for i, alert := range alerts {
+		var held int64
+		if err := s.db.WithContext(ctx).Model(&models.HeldChange{}).
+			Where("alert_id = ?", alert.ID).
+			Count(&held).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to count held changes: %v", err)
+		}
+
+		result[i] = &proto.DeviceAlert{
+			AlertId:     alert.ID,
+			DeviceId:    alert.DeviceID,
+			Kind:        alert.Kind,
+			Reason:      alert.Reason,
+			Status:      alert.Status,
+			CreatedAt:   alert.CreatedAt.Format(time.RFC3339),
+			HeldChanges: int32(held),
+		}
+	}
+
+	return &proto.ListDeviceAlertsResponse{Alerts: result}, nil
+}
+
+// ConfirmDeviceChanges resumes a paused device. Approved changes are
+// released to the other devices in the order they were made; rejected ones
+// are dropped, and the user can restore the folder from its history.
+func (s *SyncService) ConfirmDeviceChanges(ctx context.Context, req *proto.ConfirmDeviceChangesRequest) (*proto.ConfirmDeviceChangesResponse, error) {
+	var alert models.DeviceAlert
+	var held []models.HeldChange
+	released := 0
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&alert, "id = ? AND user_id = ? AND status = ?", req.AlertId, req.UserId, models.DeviceAlertPaused).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("alert_id = ?", alert.ID).Order("id ASC").Find(&held).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("alert_id = ?", alert.ID).Delete(&models.HeldChange{}).Error; err != nil {
+			return err
+		}
+
+		if req.Approve {
+			for _, change := range held {
+				held, err := events.Unmarshal(change.Payload)
+				if err != nil {
+					log.Printf("Failed to decode held change %d: %v", change.ID, err)
+					continue
+				}
+				// Released changes are new events; the originals were
+				// consumed when they were held.
+				release, err := events.New(tx, held.UserId, held.DeviceId)
+				if err != nil {
+					return err
+				}
+				events.CausedBy(release, held)
+				release.OccurredAt = held.OccurredAt
+				release.Payload = held.Payload
+				release.Confirmed = true
+				if err := outbox.EnqueueEvent(tx, release); err != nil {
+					return err
+				}
+				released++
+			}
+		}
+
+		now := time.Now()
+		alert.Status = models.DeviceAlertRejected
+		if req.Approve {
+			alert.Status = models.DeviceAlertApproved
+		}
+		alert.ResolvedAt = &now
+		if err := tx.Save(&alert).Error; err != nil {
+			return err
+		}
+		return s.detector.Reset(tx, alert.UserID, alert.DeviceID)
+	})
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, status.Errorf(codes.NotFound, "no paused alert found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to confirm changes: %v", err)
+	}
+
+	if !req.Approve {
+		return &proto.ConfirmDeviceChangesResponse{
+			Success:          true,
+			Message:          fmt.Sprintf("Discarded %d changes from device %s", len(held), alert.DeviceID),
+			DiscardedChanges: int32(len(held)),
+		}, nil
+	}
+
+	return &proto.ConfirmDeviceChangesResponse{
+		Success:         true,
+		Message:         fmt.Sprintf("Released %d changes from device %s", released, alert.DeviceID),
+		ReleasedChanges: int32(released),
+	}, nil
+}
@@ -0,0 +1,152 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/anomaly"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+)
+
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "sync.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestScreenChangeSharedDeviceID(t *testing.T) {
+	db := newTestDB(t, &models.User{}, &models.DeviceAlert{}, &models.DeviceActivity{}, &models.FileEntropy{}, &models.HeldChange{}, &models.OutboxEvent{},
+		&models.ChangeEntry{}, &models.JournalHead{})
+	s := &SyncService{
+		db:       db,
+		detector: anomaly.NewDetector(anomaly.Thresholds{Window: time.Minute, MaxDeletions: 1}),
+	}
+
+	deletion := func(userID string) *proto.EventEnvelope {
+		return &proto.EventEnvelope{
+			UserId:     userID,
+			DeviceId:   "laptop",
+			OccurredAt: time.Now().Format(time.RFC3339Nano),
+			Payload:    &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{Path: "notes.txt"}},
+		}
+	}
+	screen := func(userID string) bool {
+		t.Helper()
+		allowed, err := s.screenChange(db, deletion(userID))
+		if err != nil {
+			t.Fatalf("screen change of %s: %v", userID, err)
+		}
+		return allowed
+	}
+
+	// Alice's laptop goes over the limit and is paused.
+	if !screen("alice") || screen("alice") {
+		t.Fatal("alice's laptop was not paused on its second deletion")
+	}
+
+	// Bob's device of the same name is unaffected, and is paused on its
+	// own once it goes over the limit too.
+	if !screen("bob") {
+		t.Fatal("bob's laptop was held by alice's alert")
+	}
+	if screen("bob") {
+		t.Fatal("bob's laptop was not paused on its second deletion")
+	}
+
+	var alerts []models.DeviceAlert
+	if err := db.Order("user_id").Find(&alerts, "status = ?", models.DeviceAlertPaused).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 || alerts[0].UserID != "alice" || alerts[1].UserID != "bob" {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+
+	var held int64
+	db.Model(&models.HeldChange{}).Where("alert_id = ?", alerts[1].ID).Count(&held)
+	if held != 1 {
+		t.Fatalf("bob has %d held changes, want 1", held)
+	}
+}
+
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *proto.FileChangeEvent
+}
+
+func (w *watchStream) Context() context.Context { return w.ctx }
+
+func (w *watchStream) Send(event *proto.FileChangeEvent) error {
+	w.events <- event
+	return nil
+}
+
+// An alert raised by the instance that consumed the change reaches the
+// streams connected to any other instance, the paused device's included.
+func TestAlertReachesOtherInstances(t *testing.T) {
+	db := newTestDB(t, &models.User{}, &models.DeviceAlert{}, &models.DeviceActivity{}, &models.FileEntropy{}, &models.HeldChange{}, &models.OutboxEvent{},
+		&models.ChangeEntry{}, &models.JournalHead{})
+	owner := &SyncService{
+		db:       db,
+		detector: anomaly.NewDetector(anomaly.Thresholds{Window: time.Minute, MaxDeletions: 1}),
+		hub:      newHub(),
+	}
+	other := &SyncService{db: db, hub: newHub()}
+
+	for i := 0; i < 2; i++ {
+		if _, err := owner.screenChange(db, &proto.EventEnvelope{
+			UserId:     "alice",
+			DeviceId:   "laptop",
+			OccurredAt: time.Now().Format(time.RFC3339Nano),
+			Payload:    &proto.EventEnvelope_FileDeleted{FileDeleted: &proto.FileDeleted{Path: "notes.txt"}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var alert models.DeviceAlert
+	if err := db.First(&alert, "user_id = ?", "alice").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, device := range []string{"phone", "laptop"} {
+		stream := &watchStream{ctx: ctx, events: make(chan *proto.FileChangeEvent, 1)}
+		go other.WatchFileChanges(&proto.WatchRequest{UserId: "alice", DeviceId: device, Cursor: "0"}, stream)
+
+		select {
+		case event := <-stream.events:
+			if event.ChangeType != proto.FileChangeEvent_ALERT || event.AlertId != alert.ID ||
+				event.DeviceId != "laptop" || event.Reason != alert.Reason || event.FileId != "" {
+				t.Fatalf("%s got %+v, want alert %s", device, event, alert.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s got no alert", device)
+		}
+	}
+
+	changes, err := other.GetChanges(ctx, &proto.GetChangesRequest{UserId: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Changes) != 1 || changes.Changes[0].AlertId != alert.ID {
+		t.Fatalf("journal holds %+v, want the alert", changes.Changes)
+	}
+}
@@ -0,0 +1,231 @@
+package sync
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+
+	consumerRestartDelay = 5 * time.Second
+
+	// wakeTopic tells every instance that a user's journal has grown. Its
+	// messages are keyed by user ID and carry nothing else.
+	wakeTopic = "journal-wakes"
+)
+
+var changeTypes = map[string]proto.FileChangeEvent_ChangeType{
+	events.Created:  proto.FileChangeEvent_CREATED,
+	events.Modified: proto.FileChangeEvent_MODIFIED,
+	events.Deleted:  proto.FileChangeEvent_DELETED,
+	events.Renamed:  proto.FileChangeEvent_RENAMED,
+	events.Shared:   proto.FileChangeEvent_SHARED,
+	events.Anomaly:  proto.FileChangeEvent_ALERT,
+}
+
+func toChangeEvent(entry *models.ChangeEntry) *proto.FileChangeEvent {
+	return &proto.FileChangeEvent{
+		FileId:     entry.FileID,
+		FilePath:   entry.FilePath,
+		ChangeType: changeTypes[entry.ChangeType],
+		Timestamp:  entry.CreatedAt.Format(time.RFC3339),
+		DeviceId:   entry.DeviceID,
+		VersionId:  entry.VersionID,
+		AlertId:    entry.AlertID,
+		Reason:     entry.Reason,
+		Cursor:     journal.FormatCursor(entry.Seq),
+	}
+}
+
+// GetChanges returns a page of a user's changes after a cursor, oldest
+// first. An empty cursor starts from the beginning.
+func (s *SyncService) GetChanges(ctx context.Context, req *proto.GetChangesRequest) (*proto.GetChangesResponse, error) {
+	after, err := journal.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	if limit > maxChangesLimit {
+		limit = maxChangesLimit
+	}
+
+	// One extra entry tells whether there are more.
+	entries, err := journal.Read(s.db.WithContext(ctx), req.UserId, after, limit+1)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read changes: %v", err)
+	}
+
+	response := &proto.GetChangesResponse{
+		NextCursor: journal.FormatCursor(after),
+		HasMore:    len(entries) > limit,
+	}
+	if response.HasMore {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		response.Changes = append(response.Changes, toChangeEvent(&entries[i]))
+	}
+	if len(entries) > 0 {
+		response.NextCursor = journal.FormatCursor(entries[len(entries)-1].Seq)
+	}
+	return response, nil
+}
+
+// WatchFileChanges streams a user's changes made by other devices. With a
+// cursor it first replays the journal from there. Events are always read
+// from the journal, so replayed and live events follow each other without
+// gaps or duplicates; the instance's consumer only wakes the stream up.
+func (s *SyncService) WatchFileChanges(req *proto.WatchRequest, stream proto.SyncService_WatchFileChangesServer) error {
+	ctx := stream.Context()
+
+	// Subscribe first, so no change lands between reading the journal head
+	// and listening for new ones.
+	sub := s.hub.subscribe(req.UserId)
+	defer s.hub.unsubscribe(req.UserId, sub)
+
+	after, err := journal.ParseCursor(req.Cursor)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if req.Cursor == "" {
+		if after, err = journal.Head(s.db.WithContext(ctx), req.UserId); err != nil {
+			return status.Errorf(codes.Internal, "failed to read journal: %v", err)
+		}
+	}
+
+	for {
+		if after, err = s.sendChanges(ctx, stream, req, after); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.wake:
+		}
+	}
+}
+
+// sendChanges sends the journal entries after a sequence number that were
+// made by other devices, along with every alert, and returns the last
+// sequence number read.
+func (s *SyncService) sendChanges(ctx context.Context, stream proto.SyncService_WatchFileChangesServer, req *proto.WatchRequest, after int64) (int64, error) {
+	for {
+		entries, err := journal.Read(s.db.WithContext(ctx), req.UserId, after, defaultChangesLimit)
+		if err != nil {
+			return after, status.Errorf(codes.Internal, "failed to read journal: %v", err)
+		}
+
+		for i := range entries {
+			if entries[i].DeviceID != req.DeviceId || entries[i].AlertID != "" {
+				if err := stream.Send(toChangeEvent(&entries[i])); err != nil {
+					return after, err
+				}
+			}
+			after = entries[i].Seq
+		}
+
+		if len(entries) < defaultChangesLimit {
+			return after, nil
+		}
+	}
+}
+
+// Start consumes file changes for this instance until the context is
+// cancelled, along with the changes dead-lettered by the group and the
+// wake-ups meant for every instance. A consumer that stops with an error
+// is restarted.
+func (s *SyncService) Start(ctx context.Context, group string, maxAttempts int) {
+	go restartOnError(ctx, "dead letter consumer", func() error {
+		return s.consumeDeadLetters(ctx, group+"-dead-letters")
+	})
+	// A group of its own gives this instance every wake-up.
+	wakeGroup := group + "-wake-" + uuid.New().String()
+	go restartOnError(ctx, "journal wake consumer", func() error {
+		return s.consumeWakes(ctx, wakeGroup)
+	})
+	restartOnError(ctx, "file change consumer", func() error {
+		return s.consumeFileChanges(ctx, group, maxAttempts)
+	})
+}
+
+// restartOnError runs a consumer until the context is cancelled.
+func restartOnError(ctx context.Context, name string, run func() error) {
+	for {
+		err := run()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Restarting %s after error: %v", name, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(consumerRestartDelay):
+		}
+	}
+}
+
+// consumeFileChanges claims, screens and journals each change in one
+// transaction, so a redelivered change is skipped; then the streams of its
+// user on every instance are woken up. A redelivered change wakes them
+// again, in case the first wake-up was never published. Anomalies were
+// journaled when the device was paused, so they only wake the streams up.
+func (s *SyncService) consumeFileChanges(ctx context.Context, group string, maxAttempts int) error {
+	return events.Consume(ctx, s.bus, group, maxAttempts, func(env *proto.EventEnvelope) error {
+		anomaly := env.GetDeviceAnomaly()
+
+		var propagate bool
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			claimed, err := outbox.Claim(tx, env.EventId)
+			if err != nil {
+				return err
+			}
+
+			if !claimed || anomaly != nil {
+				propagate = true
+				return nil
+			}
+
+			propagate, err = s.screenChange(tx, env)
+			if err != nil || !propagate {
+				return err
+			}
+			return s.handleFileChange(tx, env)
+		})
+		if err != nil || !propagate {
+			return err
+		}
+
+		return s.bus.Publish(ctx, &utils.Message{Topic: wakeTopic, Key: env.UserId})
+	})
+}
+
+// consumeWakes wakes the streams on this instance of each user whose
+// journal has grown.
+func (s *SyncService) consumeWakes(ctx context.Context, group string) error {
+	opts := utils.SubscribeOptions{Group: group, Start: utils.StartLatest}
+	return s.bus.Subscribe(ctx, wakeTopic, opts, func(ctx context.Context, msg *utils.Message) error {
+		s.hub.notify(msg.Key)
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+// A change consumed by one instance wakes the streams connected to
+// another at once, rather than when they next look at the journal.
+func TestChangeWakesStreamsOnOtherInstances(t *testing.T) {
+	db := newTestDB(t, &models.ProcessedEvent{}, &models.ChangeEntry{}, &models.JournalHead{})
+	bus := utils.NewMemoryEventBus()
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	consumer := &SyncService{db: db, bus: bus, hub: newHub()}
+	other := &SyncService{db: db, bus: bus, hub: newHub()}
+	go consumer.Start(ctx, "sync", 3)
+	go other.consumeWakes(ctx, "sync-wake-other")
+
+	stream := &watchStream{ctx: ctx, events: make(chan *proto.FileChangeEvent, 1)}
+	go other.WatchFileChanges(&proto.WatchRequest{UserId: "alice", DeviceId: "phone"}, stream)
+	// Let the stream read the journal head and the consumers subscribe.
+	time.Sleep(100 * time.Millisecond)
+
+	alert := &models.DeviceAlert{ID: "alert-1", UserID: "alice", DeviceID: "laptop", Reason: "too many deletions"}
+	if _, err := journal.RecordAlert(db, alert, events.Anomaly); err != nil {
+		t.Fatal(err)
+	}
+	env := &proto.EventEnvelope{
+		EventId: "event-1",
+		UserId:  "alice",
+		Payload: &proto.EventEnvelope_DeviceAnomaly{DeviceAnomaly: &proto.DeviceAnomaly{AlertId: alert.ID}},
+	}
+	value, headers, err := events.Encode(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(ctx, &utils.Message{Topic: events.Topic, Key: env.UserId, Value: value, Headers: headers}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-stream.events:
+		if event.AlertId != alert.ID {
+			t.Fatalf("stream got %+v, want alert %s", event, alert.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream on the other instance was not woken")
+	}
+}
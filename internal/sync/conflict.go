@@ -0,0 +1,389 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/merge"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/versioning"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResolveConflict settles concurrent versions of a file:
+//
+//   - PICK_WINNER makes the winning version current again. Its content is
+//     referenced, not copied.
+//   - KEEP_BOTH does the same and moves every losing version to a
+//     "conflicted copy" next to the file.
+//   - MERGED stores the merged content as a new version.
+//   - AUTO_MERGE merges the winning and the one losing version on the
+//     server with the merge driver for the file. When they conflict nothing
+//     changes and the conflicting paths are reported.
+//
+// The losing versions are marked as superseded by the resulting version.
+func (s *SyncService) ResolveConflict(ctx context.Context, req *proto.ConflictResolutionRequest) (*proto.ConflictResolutionResponse, error) {
+	switch {
+	case req.Strategy == proto.ConflictResolutionRequest_MERGED:
+		if len(req.MergedContent) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "merged content is required")
+		}
+	case req.WinningVersionId == "":
+		return nil, status.Errorf(codes.InvalidArgument, "winning version is required")
+	case req.Strategy == proto.ConflictResolutionRequest_AUTO_MERGE && len(req.LosingVersionIds) != 1:
+		return nil, status.Errorf(codes.InvalidArgument, "automatic merges take exactly one losing version")
+	}
+
+	mergedContent := req.MergedContent
+	var mergedVector vclock.Vector
+	if req.Strategy == proto.ConflictResolutionRequest_AUTO_MERGE {
+		result, vector, err := s.autoMerge(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Clean() {
+			return &proto.ConflictResolutionResponse{
+				Success:          false,
+				Message:          "Versions conflict and must be resolved by hand",
+				ConflictingPaths: result.Conflicts,
+			}, nil
+		}
+		mergedContent, mergedVector = result.Merged, vector
+	}
+
+	var file models.File
+	var result *models.FileVersion
+	var losing []models.FileVersion
+	var copies []models.File
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(versioning.PreloadVersions).
+			First(&file, "id = ? AND owner_id = ?", req.FileId, req.UserId).Error; err != nil {
+			return err
+		}
+
+		var err error
+		losing, err = losingVersions(&file, req)
+		if err != nil {
+			return err
+		}
+		if req.Strategy == proto.ConflictResolutionRequest_AUTO_MERGE {
+			// Both merged versions are replaced by the merge.
+			for _, v := range file.Versions {
+				if v.ID == req.WinningVersionId {
+					losing = append(losing, v)
+				}
+			}
+		}
+
+		switch req.Strategy {
+		case proto.ConflictResolutionRequest_MERGED, proto.ConflictResolutionRequest_AUTO_MERGE:
+			result, err = s.storeMerged(ctx, tx, &file, mergedContent, req.DeviceId, mergedVector)
+		default:
+			result, err = pickWinner(tx, &file, req.WinningVersionId, req.DeviceId)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := recordChange(tx, &file, result, events.Modified); err != nil {
+			return err
+		}
+
+		if req.Strategy == proto.ConflictResolutionRequest_KEEP_BOTH {
+			for i := range losing {
+				sibling, version, err := conflictedCopy(tx, &file, &losing[i])
+				if err != nil {
+					return err
+				}
+				if err := recordChange(tx, sibling, version, events.Created); err != nil {
+					return err
+				}
+				copies = append(copies, *sibling)
+			}
+		}
+
+		if len(losing) == 0 {
+			return nil
+		}
+		ids := make([]string, len(losing))
+		for i, v := range losing {
+			ids[i] = v.ID
+		}
+		return tx.Model(&models.FileVersion{}).
+			Where("id IN ?", ids).
+			Update("superseded_by", result.ID).Error
+	})
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, status.Errorf(codes.NotFound, "file or version not found")
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resolve conflict: %v", err)
+	}
+
+	response := &proto.ConflictResolutionResponse{
+		Success:      true,
+		Message:      "Conflict resolved successfully",
+		NewVersionId: result.ID,
+	}
+	for _, v := range losing {
+		response.SupersededVersionIds = append(response.SupersededVersionIds, v.ID)
+	}
+	for _, c := range copies {
+		response.ConflictedCopyFileIds = append(response.ConflictedCopyFileIds, c.ID)
+	}
+	return response, nil
+}
+
+// autoMerge merges the winning and losing version of a conflict against
+// their latest common ancestor. It returns the merge and the vector of
+// the changes it includes.
+func (s *SyncService) autoMerge(ctx context.Context, req *proto.ConflictResolutionRequest) (*merge.Result, vclock.Vector, error) {
+	var file models.File
+	if err := s.db.WithContext(ctx).Scopes(versioning.PreloadVersions).
+		First(&file, "id = ? AND owner_id = ?", req.FileId, req.UserId).Error; err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if file.Encrypted {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "encrypted files cannot be merged on the server")
+	}
+
+	driver := merge.Lookup(file.Name, file.ContentType)
+	if driver == nil {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "no merge driver for %s", file.Name)
+	}
+
+	var winner, loser *models.FileVersion
+	for i := range file.Versions {
+		switch file.Versions[i].ID {
+		case req.WinningVersionId:
+			winner = &file.Versions[i]
+		case req.LosingVersionIds[0]:
+			loser = &file.Versions[i]
+		}
+	}
+	if winner == nil || loser == nil {
+		return nil, nil, status.Errorf(codes.NotFound, "version not found")
+	}
+
+	var base []byte
+	if ancestor := commonAncestor(file.Versions, winner, loser); ancestor != nil {
+		var err error
+		if base, err = s.readVersion(ctx, ancestor); err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "failed to read common ancestor: %v", err)
+		}
+	}
+	local, err := s.readVersion(ctx, winner)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to read winning version: %v", err)
+	}
+	remote, err := s.readVersion(ctx, loser)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to read losing version: %v", err)
+	}
+
+	result, err := driver.Merge(base, local, remote)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "failed to merge: %v", err)
+	}
+	return result, vclock.Merge(winner.Vector, loser.Vector), nil
+}
+
+// commonAncestor returns the latest version both a and b have seen.
+func commonAncestor(versions []models.FileVersion, a, b *models.FileVersion) *models.FileVersion {
+	var ancestor *models.FileVersion
+	for i := range versions {
+		v := &versions[i]
+		if len(v.Vector) == 0 || !vclock.Descends(a.Vector, v.Vector) || !vclock.Descends(b.Vector, v.Vector) {
+			continue
+		}
+		if ancestor == nil || v.VersionNum > ancestor.VersionNum {
+			ancestor = v
+		}
+	}
+	return ancestor
+}
+
+// losingVersions returns the versions named as losing, or the current
+// version when none are named and it is not the winner.
+func losingVersions(file *models.File, req *proto.ConflictResolutionRequest) ([]models.FileVersion, error) {
+	byID := make(map[string]models.FileVersion, len(file.Versions))
+	for _, v := range file.Versions {
+		byID[v.ID] = v
+	}
+
+	if len(req.LosingVersionIds) == 0 {
+		current := versioning.Current(file)
+		if current == nil || current.ID == req.WinningVersionId {
+			return nil, nil
+		}
+		return []models.FileVersion{*current}, nil
+	}
+
+	losing := make([]models.FileVersion, 0, len(req.LosingVersionIds))
+	for _, id := range req.LosingVersionIds {
+		if id == req.WinningVersionId {
+			return nil, status.Errorf(codes.InvalidArgument, "version %s cannot both win and lose", id)
+		}
+		v, ok := byID[id]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "version %s not found", id)
+		}
+		losing = append(losing, v)
+	}
+	return losing, nil
+}
+
+// pickWinner makes a version current. A winner that already is current is
+// returned as it is; otherwise it is restored as a new version.
+func pickWinner(tx *gorm.DB, file *models.File, winnerID, deviceID string) (*models.FileVersion, error) {
+	for i := range file.Versions {
+		if file.Versions[i].ID != winnerID {
+			continue
+		}
+		if current := versioning.Current(file); current != nil && current.ID == winnerID {
+			return current, nil
+		}
+		return restoreVersion(tx, file, &file.Versions[i], deviceID)
+	}
+	return nil, status.Errorf(codes.NotFound, "winning version %s not found", winnerID)
+}
+
+// storeMerged stores content as the next version of a locked file. The
+// version has seen the changes in vector as well as the current ones.
+func (s *SyncService) storeMerged(ctx context.Context, tx *gorm.DB, file *models.File, content []byte, deviceID string, vector vclock.Vector) (*models.FileVersion, error) {
+	codec := utils.CodecIdentity
+	if !file.Encrypted {
+		codec = utils.SelectCodec(file.ContentType)
+	}
+
+	// The chunk rows are written in the transaction, so none are left
+	// behind when it rolls back.
+	chunks := s.chunks.WithTx(tx)
+	manifest, err := chunks.Write(ctx, file.OwnerID, content, codec)
+	if err != nil {
+		return nil, err
+	}
+	storedSize, err := chunks.StoredSize(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceID == "" {
+		deviceID = systemDeviceID
+	}
+
+	sum := sha256.Sum256(content)
+	version := &models.FileVersion{
+		ID:         uuid.New().String(),
+		FileID:     file.ID,
+		Hash:       hex.EncodeToString(sum[:]),
+		Size:       int64(len(content)),
+		StoredSize: storedSize,
+		Codec:      codec,
+		DeviceID:   deviceID,
+		Vector:     vector,
+	}
+	if err := versioning.Append(tx, version); err != nil {
+		return nil, err
+	}
+
+	for i := range manifest {
+		manifest[i].VersionID = version.ID
+	}
+	if err := tx.Create(&manifest).Error; err != nil {
+		return nil, err
+	}
+
+	file.CurrentVersionID = &version.ID
+	file.Size = version.Size
+	if err := tx.Model(file).Updates(map[string]interface{}{
+		"size":       file.Size,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// conflictedCopy creates a sibling of file holding the content of a losing
+// version.
+func conflictedCopy(tx *gorm.DB, file *models.File, losing *models.FileVersion) (*models.File, *models.FileVersion, error) {
+	sibling, err := newConflictedSibling(tx, file, losing.DeviceID, losing.CreatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version, err := restoreVersion(tx, sibling, losing, losing.DeviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sibling, version, nil
+}
+
+// newConflictedSibling creates an empty "conflicted copy" next to file,
+// shared with the same recipients.
+func newConflictedSibling(tx *gorm.DB, file *models.File, deviceID string, at time.Time) (*models.File, error) {
+	sibling := &models.File{
+		ID:            uuid.New().String(),
+		ContentType:   file.ContentType,
+		Encrypted:     file.Encrypted,
+		EncryptedName: file.EncryptedName,
+		OwnerID:       file.OwnerID,
+	}
+
+	sibling.Name = conflictedCopyName(file.Name, deviceID, at)
+	if file.Encrypted {
+		// Like every encrypted file, the copy is named by its ID.
+		sibling.Name = sibling.ID
+	}
+	sibling.Path = path.Join(path.Dir(file.Path), sibling.Name)
+
+	if err := tx.Create(sibling).Error; err != nil {
+		return nil, err
+	}
+
+	var keys []models.FileKey
+	if err := tx.Where("file_id = ?", file.ID).Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i].FileID = sibling.ID
+		keys[i].CreatedAt = time.Time{}
+	}
+	if len(keys) > 0 {
+		if err := tx.Create(&keys).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return sibling, nil
+}
+
+// conflictedCopyName turns "notes.md" into
+// "notes (conflicted copy from laptop 2024-05-01).md".
+func conflictedCopyName(name, deviceID string, at time.Time) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	return fmt.Sprintf("%s (conflicted copy from %s %s)%s", base, deviceID, at.Format("2006-01-02"), ext)
+}
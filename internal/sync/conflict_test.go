@@ -0,0 +1,45 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunkstore"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+// A merge stored in a transaction that rolls back leaves no chunks behind.
+func TestStoreMergedRollsBackChunks(t *testing.T) {
+	db := newTestDB(t, &models.File{}, &models.FileVersion{}, &models.Chunk{}, &models.ChunkOwner{}, &models.VersionChunk{})
+	s := &SyncService{db: db, chunks: chunkstore.New(db, utils.NewMemoryBlobStore())}
+
+	file := &models.File{Name: "notes.txt", Path: "alice/laptop/notes.txt", OwnerID: "alice", ContentType: "text/plain"}
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	errRollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.storeMerged(context.Background(), tx, file, []byte("merged notes\n"), "laptop", nil); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.Chunk{}, &models.ChunkOwner{}, &models.VersionChunk{}, &models.FileVersion{}} {
+		var count int64
+		if err := db.Model(model).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%d rows of %T left after the rollback", count, model)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultDeadLettersLimit = 100
+	maxDeadLettersLimit     = 1000
+)
+
+// consumeDeadLetters stores the file changes the group dead-lettered, so
+// that operators can inspect them. A dead letter that cannot be stored is
+// redelivered rather than lost.
+func (s *SyncService) consumeDeadLetters(ctx context.Context, group string) error {
+	opts := utils.SubscribeOptions{Group: group, Start: utils.StartEarliest}
+	topic := utils.DeadLetterTopic(events.Topic)
+
+	return s.bus.Subscribe(ctx, topic, opts, func(ctx context.Context, msg *utils.Message) error {
+		return s.db.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(toDeadLetter(msg)).Error
+	})
+}
+
+// toDeadLetter unpacks the failure metadata of a dead-lettered message.
+func toDeadLetter(msg *utils.Message) *models.DeadLetter {
+	headers := make(map[string]string)
+	for key, value := range msg.Headers {
+		if !strings.HasPrefix(key, utils.DeadLetterHeaderPrefix) {
+			headers[key] = value
+		}
+	}
+
+	letter := &models.DeadLetter{
+		EventID: msg.Headers[utils.EventIDHeader],
+		Topic:   msg.Headers[utils.DeadLetterTopicHeader],
+		Group:   msg.Headers[utils.DeadLetterGroupHeader],
+		Key:     msg.Key,
+		Payload: msg.Value,
+		Headers: headers,
+		Error:   msg.Headers[utils.DeadLetterErrorHeader],
+		Status:  models.DeadLetterPending,
+	}
+	letter.Partition, _ = strconv.Atoi(msg.Headers[utils.DeadLetterPartitionHeader])
+	letter.Offset, _ = strconv.ParseInt(msg.Headers[utils.DeadLetterOffsetHeader], 10, 64)
+	letter.Attempts, _ = strconv.Atoi(msg.Headers[utils.DeadLetterAttemptsHeader])
+
+	failedAt, err := time.Parse(time.RFC3339Nano, msg.Headers[utils.DeadLetterFailedAtHeader])
+	if err != nil {
+		failedAt = msg.Timestamp
+	}
+	letter.FailedAt = failedAt
+
+	return letter
+}
+
+// AdminService lets operators inspect, replay and discard dead letters.
+type AdminService struct {
+	proto.UnimplementedEventAdminServiceServer
+	db *gorm.DB
+}
+
+func NewAdminService(db *gorm.DB) *AdminService {
+	return &AdminService{db: db}
+}
+
+func (a *AdminService) ListDeadLetters(ctx context.Context, req *proto.ListDeadLettersRequest) (*proto.ListDeadLettersResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultDeadLettersLimit
+	}
+	if limit > maxDeadLettersLimit {
+		limit = maxDeadLettersLimit
+	}
+
+	query := a.db.WithContext(ctx)
+	if req.Topic != "" {
+		query = query.Where("topic = ?", req.Topic)
+	}
+	if !req.IncludeResolved {
+		query = query.Where("status = ?", models.DeadLetterPending)
+	}
+
+	var letters []models.DeadLetter
+	if err := query.Order("failed_at DESC").Limit(limit).Find(&letters).Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list dead letters: %v", err)
+	}
+
+	result := make([]*proto.DeadLetter, len(letters))
+	for i, letter := range letters {
+		result[i] = &proto.DeadLetter{
+			Id:        letter.ID,
+			EventId:   letter.EventID,
+			Topic:     letter.Topic,
+			Key:       letter.Key,
+			Payload:   letter.Payload,
+			Headers:   letter.Headers,
+			Group:     letter.Group,
+			Partition: int32(letter.Partition),
+			Offset:    letter.Offset,
+			Attempts:  int32(letter.Attempts),
+			Error:     letter.Error,
+			FailedAt:  letter.FailedAt.Format(time.RFC3339),
+			Status:    letter.Status,
+		}
+		if letter.ResolvedAt != nil {
+			result[i].ResolvedAt = letter.ResolvedAt.Format(time.RFC3339)
+		}
+	}
+
+	return &proto.ListDeadLettersResponse{DeadLetters: result}, nil
+}
+
+// ReplayDeadLetters publishes pending dead letters to their original topic
+// again, through the outbox. A replayed event gets a new event ID, since
+// the original was never processed.
+func (a *AdminService) ReplayDeadLetters(ctx context.Context, req *proto.DeadLetterActionRequest) (*proto.DeadLetterActionResponse, error) {
+	if err := validateDeadLetterIDs(req.Ids); err != nil {
+		return nil, err
+	}
+
+	affected, err := a.resolve(ctx, req.Ids, models.DeadLetterReplayed, func(tx *gorm.DB, letter *models.DeadLetter) error {
+		_, err := outbox.Enqueue(tx, letter.Topic, letter.Key, letter.Payload, letter.Headers)
+		return err
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to replay dead letters: %v", err)
+	}
+
+	return &proto.DeadLetterActionResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("Replayed %d dead letters", affected),
+		Affected: int32(affected),
+	}, nil
+}
+
+func (a *AdminService) DiscardDeadLetters(ctx context.Context, req *proto.DeadLetterActionRequest) (*proto.DeadLetterActionResponse, error) {
+	if err := validateDeadLetterIDs(req.Ids); err != nil {
+		return nil, err
+	}
+
+	affected, err := a.resolve(ctx, req.Ids, models.DeadLetterDiscarded, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to discard dead letters: %v", err)
+	}
+
+	return &proto.DeadLetterActionResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("Discarded %d dead letters", affected),
+		Affected: int32(affected),
+	}, nil
+}
+
+func validateDeadLetterIDs(ids []string) error {
+	if len(ids) == 0 {
+		return status.Errorf(codes.InvalidArgument, "no dead letter ids given")
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid dead letter id %q", id)
+		}
+	}
+	return nil
+}
+
+// resolve moves the pending dead letters among ids to the given status,
+// applying fn to each in the same transaction. Letters already resolved
+// are skipped.
+func (a *AdminService) resolve(ctx context.Context, ids []string, resolution string, fn func(tx *gorm.DB, letter *models.DeadLetter) error) (int, error) {
+	var letters []models.DeadLetter
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND status = ?", ids, models.DeadLetterPending).
+			Order("failed_at ASC").
+			Find(&letters).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range letters {
+			if fn != nil {
+				if err := fn(tx, &letters[i]); err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&letters[i]).Updates(map[string]interface{}{
+				"status":      resolution,
+				"resolved_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return len(letters), err
+}
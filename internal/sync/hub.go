@@ -0,0 +1,55 @@
+package sync
+
+import (
+	gosync "sync"
+)
+
+// hub routes the wake-ups this instance receives to the streams watching
+// the same user on this instance.
+type hub struct {
+	mu          gosync.RWMutex
+	subscribers map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	// wake is signalled when the user's journal may have grown.
+	wake chan struct{}
+}
+
+func newHub() *hub {
+	return &hub{subscribers: make(map[string]map[*subscriber]struct{})}
+}
+
+func (h *hub) subscribe(userID string) *subscriber {
+	sub := &subscriber{wake: make(chan struct{}, 1)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	return sub
+}
+
+func (h *hub) unsubscribe(userID string, sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers[userID], sub)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+}
+
+// notify wakes every stream of a user. Streams that are already awake are
+// left alone, since they read the journal up to its end anyway.
+func (h *hub) notify(userID string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+}
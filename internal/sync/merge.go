@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/merge"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxMergeSize is the largest file merged on the server.
+const maxMergeSize = 4 * 1024 * 1024
+
+var errMergeStale = errors.New("file changed during merge")
+
+// mergeConcurrent merges a client's concurrent edit of a text file with
+// the current version, using the file's merge driver and the version the
+// client started from as the common ancestor. A clean merge becomes the
+// next version. Otherwise the client's copy is kept as a conflicted copy
+// and the current version stays. It returns nil when the edit cannot be
+// merged on the server.
+func (s *SyncService) mergeConcurrent(ctx context.Context, file *models.File, current, base *models.FileVersion, clientVector vclock.Vector, req *proto.SyncRequest) (*proto.SyncResponse, error) {
+	if base == nil || file.Encrypted || len(req.Content) == 0 ||
+		len(req.Content) > maxMergeSize || current.Size > maxMergeSize || base.Size > maxMergeSize {
+		return nil, nil
+	}
+
+	sum := sha256.Sum256(req.Content)
+	if hex.EncodeToString(sum[:]) != req.FileHash {
+		return nil, status.Errorf(codes.InvalidArgument, "content does not match file hash")
+	}
+	driver := merge.Lookup(file.Name, file.ContentType)
+	if driver == nil || !merge.IsText(file.ContentType, req.Content) {
+		return nil, nil
+	}
+
+	baseContent, err := s.readVersion(ctx, base)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read common ancestor: %v", err)
+	}
+	currentContent, err := s.readVersion(ctx, current)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read current version: %v", err)
+	}
+
+	result, err := driver.Merge(baseContent, req.Content, currentContent)
+	if err != nil {
+		// Documents the driver cannot parse are left to the user.
+		return nil, nil
+	}
+
+	var version *models.FileVersion
+	var sibling *models.File
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked models.File
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "current_version_id").
+			First(&locked, "id = ?", file.ID).Error; err != nil {
+			return err
+		}
+		if locked.CurrentVersionID == nil || *locked.CurrentVersionID != current.ID {
+			return errMergeStale
+		}
+
+		var err error
+		if result.Clean() {
+			version, err = s.storeMerged(ctx, tx, file, result.Merged, req.DeviceId, vclock.Merge(current.Vector, clientVector))
+			if err != nil {
+				return err
+			}
+			return recordChange(tx, file, version, events.Modified)
+		}
+
+		sibling, err = newConflictedSibling(tx, file, req.DeviceId, time.Now())
+		if err != nil {
+			return err
+		}
+		version, err = s.storeMerged(ctx, tx, sibling, req.Content, req.DeviceId, nil)
+		if err != nil {
+			return err
+		}
+		return recordChange(tx, sibling, version, events.Created)
+	})
+	if err == errMergeStale {
+		// Another version landed meanwhile; the client syncs again.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store merge: %v", err)
+	}
+
+	if result.Clean() {
+		return &proto.SyncResponse{
+			Status:          proto.SyncResponse_MERGED,
+			Message:         "Concurrent edits merged",
+			LatestVersionId: version.ID,
+			VersionVector:   version.Vector.Copy(),
+		}, nil
+	}
+
+	return &proto.SyncResponse{
+		Status:               proto.SyncResponse_CONFLICT,
+		Message:              fmt.Sprintf("Concurrent edits conflict at %s; local copy saved as %s", strings.Join(result.Conflicts, ", "), sibling.Name),
+		LatestVersionId:      current.ID,
+		VersionVector:        current.Vector.Copy(),
+		ConflictedCopyFileId: sibling.ID,
+		ConflictingPaths:     result.Conflicts,
+	}, nil
+}
+
+func (s *SyncService) readVersion(ctx context.Context, version *models.FileVersion) ([]byte, error) {
+	reader, err := s.chunks.Open(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
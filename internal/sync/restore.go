@@ -0,0 +1,317 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/outbox"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/versioning"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// systemDeviceID marks changes made by the server, so that every device,
+// including the one that asked for them, picks them up.
+const systemDeviceID = "system"
+
+func (s *SyncService) RestoreVersion(ctx context.Context, req *proto.RestoreVersionRequest) (*proto.RestoreVersionResponse, error) {
+	var file models.File
+	var restored *models.FileVersion
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&file, "id = ? AND owner_id = ?", req.FileId, req.UserId).Error; err != nil {
+			return err
+		}
+
+		var source models.FileVersion
+		if err := tx.First(&source, "id = ? AND file_id = ?", req.VersionId, file.ID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		restored, err = restoreVersion(tx, &file, &source, req.DeviceId)
+		if err != nil {
+			return err
+		}
+		return recordChange(tx, &file, restored, events.Modified)
+	})
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, status.Errorf(codes.NotFound, "file or version not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to restore version: %v", err)
+	}
+
+	return &proto.RestoreVersionResponse{
+		Success:      true,
+		Message:      "Version restored successfully",
+		NewVersionId: restored.ID,
+		VersionNum:   int32(restored.VersionNum),
+	}, nil
+}
+
+// restoreVersion adds a new version of a locked file that points at the
+// content of source. Chunk references are copied, the bytes are not.
+func restoreVersion(tx *gorm.DB, file *models.File, source *models.FileVersion, deviceID string) (*models.FileVersion, error) {
+	if deviceID == "" {
+		deviceID = systemDeviceID
+	}
+
+	version := &models.FileVersion{
+		ID:         uuid.New().String(),
+		FileID:     file.ID,
+		Hash:       source.Hash,
+		Size:       source.Size,
+		StoredSize: source.StoredSize,
+		Codec:      source.Codec,
+		S3Key:      source.S3Key,
+		DeviceID:   deviceID,
+	}
+	if err := versioning.Append(tx, version); err != nil {
+		return nil, err
+	}
+	file.CurrentVersionID = &version.ID
+
+	var manifest []models.VersionChunk
+	if err := tx.Where("version_id = ?", source.ID).Order(clause.OrderByColumn{Column: clause.Column{Name: "index"}}).Find(&manifest).Error; err != nil {
+		return nil, err
+	}
+	if len(manifest) > 0 {
+		for i := range manifest {
+			manifest[i].VersionID = version.ID
+		}
+		if err := tx.Create(&manifest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	file.Size = source.Size
+	file.UpdatedAt = time.Now()
+	if err := tx.Model(file).Updates(map[string]interface{}{
+		"size":       file.Size,
+		"updated_at": file.UpdatedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// recordChange journals a change the server makes, as made by no device so
+// that every device picks it up, and queues its event in the same
+// transaction.
+func recordChange(tx *gorm.DB, file *models.File, version *models.FileVersion, changeType string) error {
+	versionID := ""
+	if version != nil {
+		versionID = version.ID
+	}
+
+	if _, err := journal.Record(tx, file, changeType, versionID, systemDeviceID); err != nil {
+		return err
+	}
+
+	env, err := events.NewFileChange(tx, file.OwnerID, systemDeviceID, changeType, file.ID, file.Path, versionID)
+	if err != nil {
+		return err
+	}
+	return outbox.EnqueueEvent(tx, env)
+}
+
+// folderChange is one step of a folder restore.
+type folderChange struct {
+	file    models.File
+	action  proto.RestoreFolderChange_Action
+	current *models.FileVersion
+	target  *models.FileVersion
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// RestoreFolder brings every file below a folder back to how it was at a
+// point in time. Files deleted since are brought back and files created
+// since are deleted. A dry run returns the changes without making them;
+// otherwise they are applied in a single transaction and recorded as a
+// restore job.
+func (s *SyncService) RestoreFolder(ctx context.Context, req *proto.RestoreFolderRequest) (*proto.RestoreFolderResponse, error) {
+	if req.FolderPath == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "folder path is required")
+	}
+	restoreTo, err := time.Parse(time.RFC3339, req.Timestamp)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid timestamp: %v", err)
+	}
+
+	if req.DryRun {
+		changes, err := planFolderRestore(s.db.WithContext(ctx), req.UserId, req.FolderPath, restoreTo, false)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to plan restore: %v", err)
+		}
+		return &proto.RestoreFolderResponse{
+			Success: true,
+			Message: fmt.Sprintf("%d files would be changed", len(changes)),
+			Changes: toRestoreChangesProto(changes),
+		}, nil
+	}
+
+	job := &models.RestoreJob{
+		UserID:     req.UserId,
+		FolderPath: req.FolderPath,
+		RestoreTo:  restoreTo,
+	}
+
+	var changes []folderChange
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		changes, err = planFolderRestore(tx, req.UserId, req.FolderPath, restoreTo, true)
+		if err != nil {
+			return err
+		}
+
+		for i := range changes {
+			if err := applyFolderChange(tx, &changes[i], req.DeviceId); err != nil {
+				return fmt.Errorf("%s: %v", changes[i].file.Path, err)
+			}
+		}
+
+		now := time.Now()
+		job.Status = models.RestoreJobCompleted
+		job.FilesChanged = len(changes)
+		job.CompletedAt = &now
+		return tx.Create(job).Error
+	})
+	if err != nil {
+		job.ID = ""
+		job.Status = models.RestoreJobFailed
+		job.Error = err.Error()
+		if jobErr := s.db.WithContext(ctx).Create(job).Error; jobErr != nil {
+			log.Printf("Failed to record restore job: %v", jobErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to restore folder: %v", err)
+	}
+
+	return &proto.RestoreFolderResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d files restored", len(changes)),
+		JobId:   job.ID,
+		Applied: true,
+		Changes: toRestoreChangesProto(changes),
+	}, nil
+}
+
+// planFolderRestore works out what has to change for the folder to look
+// as it did at restoreTo. Deleted files are included. With lock set the
+// files are locked until the transaction ends.
+func planFolderRestore(tx *gorm.DB, userID, folderPath string, restoreTo time.Time, lock bool) ([]folderChange, error) {
+	// The folder is matched whole, so restoring docs leaves docs-old alone.
+	folder := strings.TrimSuffix(folderPath, "/")
+	query := tx.Unscoped().
+		Where("owner_id = ?", userID).
+		Where("path = ? OR path LIKE ?", folder, likeEscaper.Replace(folder)+"/%").
+		Order("path ASC")
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var files []models.File
+	if err := query.Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	var changes []folderChange
+	for _, file := range files {
+		var versions []models.FileVersion
+		if err := tx.Scopes(versioning.Ordered).Where("file_id = ?", file.ID).Find(&versions).Error; err != nil {
+			return nil, err
+		}
+
+		file.Versions = versions
+		current := versioning.Current(&file)
+
+		var target *models.FileVersion
+		for i := range versions {
+			if !versions[i].CreatedAt.After(restoreTo) {
+				target = &versions[i]
+			}
+		}
+
+		deleted := file.DeletedAt.Valid
+		existed := target != nil && !file.CreatedAt.After(restoreTo) &&
+			(!deleted || file.DeletedAt.Time.After(restoreTo))
+
+		change := folderChange{file: file, current: current, target: target}
+		switch {
+		case !existed && deleted:
+			continue
+		case !existed:
+			change.action = proto.RestoreFolderChange_DELETE
+		case deleted:
+			change.action = proto.RestoreFolderChange_UNDELETE
+		case current.Hash != target.Hash:
+			change.action = proto.RestoreFolderChange_RESTORE
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// applyFolderChange makes one planned change. Restored files get a new
+// version, so the one replaced stays in their history.
+func applyFolderChange(tx *gorm.DB, change *folderChange, deviceID string) error {
+	switch change.action {
+	case proto.RestoreFolderChange_DELETE:
+		if err := tx.Delete(&change.file).Error; err != nil {
+			return err
+		}
+		return recordChange(tx, &change.file, nil, events.Deleted)
+	case proto.RestoreFolderChange_UNDELETE:
+		if err := tx.Unscoped().Model(&change.file).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		change.file.DeletedAt = gorm.DeletedAt{}
+	}
+
+	restored, err := restoreVersion(tx, &change.file, change.target, deviceID)
+	if err != nil {
+		return err
+	}
+
+	changeType := events.Modified
+	if change.action == proto.RestoreFolderChange_UNDELETE {
+		changeType = events.Created
+	}
+	return recordChange(tx, &change.file, restored, changeType)
+}
+
+func toRestoreChangesProto(changes []folderChange) []*proto.RestoreFolderChange {
+	result := make([]*proto.RestoreFolderChange, len(changes))
+	for i, change := range changes {
+		result[i] = &proto.RestoreFolderChange{
+			FileId:   change.file.ID,
+			FilePath: change.file.Path,
+			Action:   change.action,
+		}
+		if change.current != nil {
+			result[i].CurrentVersionId = change.current.ID
+		}
+		if change.target != nil {
+			result[i].TargetVersionId = change.target.ID
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
+)
+
+// Restoring a folder touches only what is in it, not folders that merely
+// share its name as a prefix.
+func TestRestoreFolderMatchesWholeFolder(t *testing.T) {
+	db := newTestDB(t, &models.File{}, &models.FileVersion{})
+	s := &SyncService{db: db}
+
+	restoreTo := time.Now().Add(-time.Hour)
+	for _, path := range []string{"alice/docs/notes.txt", "alice/docs/drafts/plan.txt", "alice/docs-old/notes.txt", "alice/docs_/notes.txt"} {
+		if err := db.Create(&models.File{Name: "notes.txt", Path: path, OwnerID: "alice"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, folder := range []string{"alice/docs", "alice/docs/"} {
+		resp, err := s.RestoreFolder(context.Background(), &proto.RestoreFolderRequest{
+			UserId:     "alice",
+			FolderPath: folder,
+			Timestamp:  restoreTo.Format(time.RFC3339),
+			DryRun:     true,
+		})
+		if err != nil {
+			t.Fatalf("restore %s: %v", folder, err)
+		}
+
+		var paths []string
+		for _, change := range resp.Changes {
+			paths = append(paths, change.FilePath)
+		}
+		if len(paths) != 2 || paths[0] != "alice/docs/drafts/plan.txt" || paths[1] != "alice/docs/notes.txt" {
+			t.Fatalf("restore %s plans changes to %v", folder, paths)
+		}
+	}
+}
@@ -2,36 +2,47 @@ package sync
 
 import (
 	"context"
-	"log"
 	"time"
 
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/anomaly"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/chunkstore"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/events"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/journal"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/proto"
 	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/versioning"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SyncService struct {
 	proto.UnimplementedSyncServiceServer
-	db          *gorm.DB
-	kafka       *utils.KafkaClient
-	fileWatcher *FileWatcher
+	db       *gorm.DB
+	bus      utils.EventBus
+	detector *anomaly.Detector
+	chunks   *chunkstore.Store
+	hub      *hub
 }
 
-func NewSyncService(db *gorm.DB, kafka *utils.KafkaClient) *SyncService {
+func NewSyncService(db *gorm.DB, bus utils.EventBus, storage utils.BlobStore, detector *anomaly.Detector) *SyncService {
 	return &SyncService{
-		db:    db,
-		kafka: kafka,
+		db:       db,
+		bus:      bus,
+		detector: detector,
+		chunks:   chunkstore.New(db, storage),
+		hub:      newHub(),
 	}
 }
 
 func (s *SyncService) SyncFile(ctx context.Context, req *proto.SyncRequest) (*proto.SyncResponse, error) {
 	var file models.File
-	if err := s.db.Preload("Versions").First(&file, "id = ?", req.FileId).Error; err != nil {
+	if err := s.db.Scopes(versioning.PreloadVersions).First(&file, "id = ?", req.FileId).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &proto.SyncResponse{
 				Status:  proto.SyncResponse_ERROR,
@@ -41,45 +52,97 @@ func (s *SyncService) SyncFile(ctx context.Context, req *proto.SyncRequest) (*pr
 		return nil, err
 	}
 
-	latestVersion := file.Versions[len(file.Versions)-1]
-
-	if latestVersion.Hash == req.FileHash {
+	latestVersion := versioning.Current(&file)
+	if latestVersion == nil {
 		return &proto.SyncResponse{
-			Status:          proto.SyncResponse_SYNCED,
-			Message:         "File is up to date",
-			LatestVersionId: latestVersion.ID,
+			Status:  proto.SyncResponse_FAST_FORWARD,
+			Message: "File has no versions",
 		}, nil
 	}
 
-	if latestVersion.Hash != req.FileHash && latestVersion.DeviceID != req.DeviceId {
-		return &proto.SyncResponse{
-			Status:          proto.SyncResponse_CONFLICT,
-			Message:         "Conflict detected",
-			LatestVersionId: latestVersion.ID,
-		}, nil
+	response := &proto.SyncResponse{
+		LatestVersionId: latestVersion.ID,
+		VersionVector:   latestVersion.Vector.Copy(),
 	}
 
-	return &proto.SyncResponse{
-		Status:          proto.SyncResponse_NEEDS_UPDATE,
-		Message:         "File needs update",
-		LatestVersionId: latestVersion.ID,
-	}, nil
+	if latestVersion.Hash == req.FileHash {
+		response.Status = proto.SyncResponse_SYNCED
+		response.Message = "File is up to date"
+		return response, nil
+	}
+
+	base, clientVector, localChanges := clientBase(&file, req)
+
+	switch vclock.Classify(latestVersion.Vector, clientVector, localChanges) {
+	case vclock.InSync:
+		response.Status = proto.SyncResponse_SYNCED
+		response.Message = "File is up to date"
+	case vclock.FastForward:
+		response.Status = proto.SyncResponse_FAST_FORWARD
+		response.Message = "Local changes can be uploaded"
+	case vclock.Behind:
+		response.Status = proto.SyncResponse_NEEDS_UPDATE
+		response.Message = "File needs update"
+	default:
+		merged, err := s.mergeConcurrent(ctx, &file, latestVersion, base, clientVector, req)
+		if err != nil {
+			return nil, err
+		}
+		if merged != nil {
+			return merged, nil
+		}
+		response.Status = proto.SyncResponse_CONFLICT
+		response.Message = "Conflict detected"
+	}
+	return response, nil
+}
+
+// clientBase finds the version a client's copy is based on, its vector,
+// and whether the client has changed the copy since. Clients that send no
+// vector are matched by the hash of their copy.
+func clientBase(file *models.File, req *proto.SyncRequest) (*models.FileVersion, vclock.Vector, bool) {
+	clientVector := vclock.Vector(req.VersionVector)
+
+	if len(clientVector) > 0 {
+		for i := range file.Versions {
+			v := &file.Versions[i]
+			if vclock.Compare(v.Vector, clientVector) == vclock.Equal {
+				return v, clientVector, v.Hash != req.FileHash
+			}
+		}
+	}
+
+	for i := len(file.Versions) - 1; i >= 0; i-- {
+		v := &file.Versions[i]
+		if v.Hash == req.FileHash {
+			return v, v.Vector, false
+		}
+	}
+	return nil, clientVector, true
 }
 
 func (s *SyncService) GetFileVersions(ctx context.Context, req *proto.FileVersionRequest) (*proto.FileVersionResponse, error) {
 	var file models.File
-	if err := s.db.Preload("Versions").First(&file, "id = ?", req.FileId).Error; err != nil {
+	if err := s.db.Scopes(versioning.PreloadVersions).First(&file, "id = ?", req.FileId).Error; err != nil {
 		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
 	}
 
 	versions := make([]*proto.FileVersion, len(file.Versions))
 	for i, v := range file.Versions {
 		versions[i] = &proto.FileVersion{
-			VersionId: v.ID,
-			CreatedAt: v.CreatedAt.Format(time.RFC3339),
-			DeviceId:  v.DeviceID,
-			Size:      v.Size,
-			Hash:      v.Hash,
+			VersionId:  v.ID,
+			CreatedAt:  v.CreatedAt.Format(time.RFC3339),
+			DeviceId:   v.DeviceID,
+			Size:       v.Size,
+			Hash:       v.Hash,
+			VersionNum: int32(v.VersionNum),
+		}
+		versions[i].VersionVector = v.Vector.Copy()
+		if v.SupersededBy != nil {
+			versions[i].SupersededBy = *v.SupersededBy
+		}
+		if v.ConflictsWith != nil {
+			versions[i].ConflictsWith = *v.ConflictsWith
 		}
 	}
 
@@ -88,115 +151,56 @@ func (s *SyncService) GetFileVersions(ctx context.Context, req *proto.FileVersio
 	}, nil
 }
 
-func (s *SyncService) ResolveConflict(ctx context.Context, req *proto.ConflictResolutionRequest) (*proto.ConflictResolutionResponse, error) {
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		newVersion := &models.FileVersion{
-			ID:       uuid.New().String(),
-			FileID:   req.FileId,
-			DeviceID: "system", // Mark as system-resolved
-		}
-
-		if err := tx.Create(newVersion).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Model(&models.FileVersion{}).
-			Where("id IN ?", req.LosingVersionIds).
-			Update("resolved_by", newVersion.ID).Error; err != nil {
-			return err
-		}
-
+// handleFileChange records a change reported by a device within tx.
+func (s *SyncService) handleFileChange(tx *gorm.DB, env *proto.EventEnvelope) error {
+	change := events.Describe(env)
+	// Changes that carry a version, and changes made by the server, were
+	// recorded before they were published. Sharing and permission changes
+	// are recorded by the request that makes them.
+	if change.VersionID != "" || env.DeviceId == systemDeviceID {
 		return nil
-	})
-
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to resolve conflict: %v", err)
 	}
-
-	return &proto.ConflictResolutionResponse{
-		Success:      true,
-		Message:      "Conflict resolved successfully",
-		NewVersionId: uuid.New().String(),
-	}, nil
-}
-
-func (s *SyncService) WatchFileChanges(req *proto.WatchRequest, stream proto.SyncService_WatchFileChangesServer) error {
-	ctx := stream.Context()
-
-	watcher, err := NewFileWatcher(s.kafka, req.UserId, req.DeviceId)
-	if err != nil {
-		return status.Errorf(codes.Internal, "failed to create file watcher: %v", err)
+	switch change.Type {
+	case events.Created, events.Modified, events.Deleted, events.Renamed:
+	default:
+		return nil
 	}
-	defer watcher.Close()
 
-	for _, path := range req.FolderPaths {
-		if err := watcher.AddPath(path); err != nil {
-			log.Fatalf("Failed to add watch path: %s", path)
-		}
+	file := &models.File{
+		ID:      change.FileID,
+		Path:    change.Path,
+		OwnerID: env.UserId,
 	}
 
-	watchCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
-	go watcher.Start(watchCtx)
-
-	return s.kafka.SubscribeToFileChanges(ctx, req.UserId, func(msg *utils.FileChangeMessage) {
-		if msg.DeviceID == req.DeviceId {
-			return
-		}
-
-		if err := s.handleFileChange(ctx, msg); err != nil {
-			log.Fatalf("Failed to handle file change: %v", err)
-			return
-		}
-
-		event := &proto.FileChangeEvent{
-			FileId:    msg.FileID,
-			FilePath:  msg.FilePath,
-			DeviceId:  msg.DeviceID,
-			Timestamp: msg.Timestamp.Format(time.RFC3339),
-			VersionId: msg.VersionID,
-		}
-
-		switch msg.ChangeType {
-		case "CREATED":
-			event.ChangeType = proto.FileChangeEvent_CREATED
-		case "MODIFIED":
-			event.ChangeType = proto.FileChangeEvent_MODIFIED
-		case "DELETED":
-			event.ChangeType = proto.FileChangeEvent_DELETED
-		case "RENAMED":
-			event.ChangeType = proto.FileChangeEvent_RENAMED
-		}
-
-		if err := stream.Send(event); err != nil {
-			log.Fatalf("Failed to send file change event: %v", err)
-			cancel()
-		}
-	})
-}
-
-func (s *SyncService) handleFileChange(ctx context.Context, msg *utils.FileChangeMessage) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		file := &models.File{
-			ID:      msg.FileID,
-			Path:    msg.FilePath,
-			OwnerID: msg.UserID,
+	// Deleted files are kept as soft-deleted rows so that their history
+	// can still be restored. Deletions made through the gateway were
+	// recorded already and find nothing left to delete.
+	if change.Type == events.Deleted {
+		result := tx.Delete(&models.File{}, "id = ?", change.FileID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
 		}
+		_, err := journal.Record(tx, file, change.Type, "", env.DeviceId)
+		return err
+	}
 
-		if err := tx.Save(file).Error; err != nil {
-			return err
-		}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"path", "updated_at"}),
+	}).Create(file).Error; err != nil {
+		return err
+	}
 
-		version := &models.FileVersion{
-			ID:       uuid.New().String(),
-			FileID:   msg.FileID,
-			DeviceID: msg.DeviceID,
-		}
+	version := &models.FileVersion{
+		ID:       uuid.New().String(),
+		FileID:   change.FileID,
+		DeviceID: env.DeviceId,
+	}
 
-		if err := tx.Create(version).Error; err != nil {
-			return err
-		}
+	if err := versioning.Append(tx, version); err != nil {
+		return err
+	}
 
-		return nil
-	})
+	_, err := journal.Record(tx, file, change.Type, version.ID, env.DeviceId)
+	return err
 }
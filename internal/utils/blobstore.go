@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type BlobInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+type CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// BlobStore is the storage backend for file content. Keys are
+// slash-separated paths.
+type BlobStore interface {
+	Put(ctx context.Context, key string, reader io.Reader) error
+	// Get returns length bytes of the blob starting at offset. A negative
+	// length reads to the end of the blob.
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	Head(ctx context.Context, key string) (*BlobInfo, error)
+	// List returns the blobs whose keys start with prefix, sorted by key.
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+
+	CreateMultipartUpload(ctx context.Context, key string) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int, reader io.Reader) (string, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// NewBlobStore builds the storage backend selected by the configuration.
+func NewBlobStore(ctx context.Context, cfg *Config) (BlobStore, error) {
+	switch cfg.StorageBackend {
+	case "s3":
+		return NewS3Client(ctx, cfg.AWSRegion, cfg.AWSBucketName)
+	case "local":
+		return NewLocalBlobStore(cfg.LocalStoragePath)
+	case "memory":
+		return NewMemoryBlobStore(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", cfg.StorageBackend)
+	}
+}
+
+// ReadBlob returns the full content of a blob.
+func ReadBlob(ctx context.Context, store BlobStore, key string) ([]byte, error) {
+	reader, err := store.Get(ctx, key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// sliceRange applies Get's offset and length to a blob held in memory.
+func sliceRange(data []byte, offset, length int64) ([]byte, error) {
+	if offset < 0 || offset > int64(len(data)) {
+		return nil, fmt.Errorf("offset %d out of range", offset)
+	}
+
+	end := int64(len(data))
+	if length >= 0 && offset+length < end {
+		end = offset + length
+	}
+	return data[offset:end], nil
+}
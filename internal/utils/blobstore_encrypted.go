@@ -0,0 +1,400 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gcmOverhead is the nonce and tag added to every encrypted blob.
+const gcmOverhead = 12 + 16
+
+// encryptedPrefix holds the blobs of an EncryptedBlobStore in the store it
+// wraps, apart from the keys its callers use.
+const encryptedPrefix = "_encrypted/"
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EncryptedBlobStore encrypts blobs at rest with AES-GCM under a fresh data
+// key per blob. Data keys are wrapped by a KeyManager and kept in the
+// database, so rotating the wrapping key never rewrites the blobs.
+//
+// Every write goes to a new blob, and the database row that names it and
+// its key is switched over only once the blob is stored. Concurrent writes
+// to one key, as happen with content-addressed chunks, therefore never
+// pair a blob with another write's key.
+type EncryptedBlobStore struct {
+	store BlobStore
+	keys  KeyManager
+	db    *gorm.DB
+}
+
+func NewEncryptedBlobStore(store BlobStore, keys KeyManager, db *gorm.DB) *EncryptedBlobStore {
+	return &EncryptedBlobStore{
+		store: store,
+		keys:  keys,
+		db:    db,
+	}
+}
+
+func (e *EncryptedBlobStore) Put(ctx context.Context, key string, reader io.Reader) error {
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %v", err)
+	}
+
+	dataKey, wrapped, keyID, err := e.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := sealAESGCM(dataKey, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt blob: %v", err)
+	}
+
+	blobKey := encryptedPrefix + uuid.New().String()
+	if err := e.store.Put(ctx, blobKey, bytes.NewReader(ciphertext)); err != nil {
+		return err
+	}
+
+	replaced, err := e.swapBlob(ctx, &models.DataKey{
+		ObjectKey:  key,
+		BlobKey:    blobKey,
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+		Size:       int64(len(plaintext)),
+	})
+	if err != nil {
+		// Nothing names the new blob, so it can go.
+		e.store.Delete(ctx, blobKey)
+		return err
+	}
+	return e.deleteBlob(ctx, key, replaced)
+}
+
+// swapBlob points an object at a newly stored blob and returns the blob it
+// named before, if any. The row is compared and swapped, so of concurrent
+// writes each replaces exactly one blob and the last one's stays.
+func (e *EncryptedBlobStore) swapBlob(ctx context.Context, record *models.DataKey) (*models.DataKey, error) {
+	db := e.db.WithContext(ctx)
+	for {
+		var current models.DataKey
+		err := db.First(&current, "object_key = ?", record.ObjectKey).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if result.RowsAffected == 1 {
+				return nil, nil
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result := db.Model(&models.DataKey{}).
+			Where("object_key = ? AND blob_key = ?", current.ObjectKey, current.BlobKey).
+			Updates(map[string]interface{}{
+				"blob_key":    record.BlobKey,
+				"key_id":      record.KeyID,
+				"wrapped_key": record.WrappedKey,
+				"size":        record.Size,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return &current, nil
+		}
+	}
+}
+
+// deleteBlob deletes the blob a row named. Older versions stored it under
+// the object's own key.
+func (e *EncryptedBlobStore) deleteBlob(ctx context.Context, key string, record *models.DataKey) error {
+	if record == nil {
+		return nil
+	}
+	if record.BlobKey == "" {
+		return e.store.Delete(ctx, key)
+	}
+	return e.store.Delete(ctx, record.BlobKey)
+}
+
+// Get decrypts the whole blob, since AES-GCM cannot authenticate a partial
+// read, and then applies the requested range.
+func (e *EncryptedBlobStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	var record models.DataKey
+	err := e.db.WithContext(ctx).First(&record, "object_key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Blobs written before encryption was enabled are served as is.
+		return e.store.Get(ctx, key, offset, length)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := e.keys.Unwrap(ctx, record.KeyID, record.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	blobKey := record.BlobKey
+	if blobKey == "" {
+		blobKey = key
+	}
+	ciphertext, err := ReadBlob(ctx, e.store, blobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := openAESGCM(dataKey, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt blob %s: %v", key, err)
+	}
+
+	data, err := sliceRange(plaintext, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (e *EncryptedBlobStore) Delete(ctx context.Context, key string) error {
+	var record models.DataKey
+	err := e.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("object_key = ?", key).Delete(&record).Error
+	if err != nil {
+		return err
+	}
+	if record.BlobKey != "" {
+		if err := e.store.Delete(ctx, record.BlobKey); err != nil {
+			return err
+		}
+	}
+	// Blobs stored before encryption, or by older versions, are under
+	// the key itself.
+	return e.store.Delete(ctx, key)
+}
+
+func (e *EncryptedBlobStore) Head(ctx context.Context, key string) (*BlobInfo, error) {
+	var record models.DataKey
+	err := e.db.WithContext(ctx).First(&record, "object_key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.store.Head(ctx, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.BlobKey == "" {
+		info, err := e.store.Head(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		info.Size -= gcmOverhead
+		return info, nil
+	}
+	return &BlobInfo{Key: key, Size: record.Size, ModTime: record.UpdatedAt}, nil
+}
+
+// List lists the encrypted objects from the database, along with the blobs
+// stored before encryption was enabled.
+func (e *EncryptedBlobStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	var records []models.DataKey
+	if err := e.db.WithContext(ctx).
+		Where(`object_key LIKE ? ESCAPE '\'`, likeEscaper.Replace(prefix)+"%").
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	stored, err := e.store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(stored))
+	for _, blob := range stored {
+		sizes[blob.Key] = blob.Size
+	}
+
+	blobs := make([]BlobInfo, 0, len(records)+len(stored))
+	encrypted := make(map[string]bool, len(records))
+	for _, record := range records {
+		encrypted[record.ObjectKey] = true
+		size := record.Size
+		if record.BlobKey == "" {
+			size = sizes[record.ObjectKey] - gcmOverhead
+		}
+		blobs = append(blobs, BlobInfo{Key: record.ObjectKey, Size: size, ModTime: record.UpdatedAt})
+	}
+	for _, blob := range stored {
+		if !encrypted[blob.Key] && !strings.HasPrefix(blob.Key, encryptedPrefix) {
+			blobs = append(blobs, blob)
+		}
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+const stagingPrefix = "_multipart"
+
+func multipartPartKey(key, uploadID string, partNumber int) string {
+	return fmt.Sprintf("%s/%s/%s/%d", stagingPrefix, uploadID, key, partNumber)
+}
+
+// Parts of an encrypted multipart upload are staged as separate encrypted
+// blobs and joined into a single encrypted blob when the upload completes.
+func (e *EncryptedBlobStore) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	return uuid.New().String(), nil
+}
+
+func (e *EncryptedBlobStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int, reader io.Reader) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	if err := e.Put(ctx, multipartPartKey(key, uploadID, partNumber), bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (e *EncryptedBlobStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	sorted := append([]CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	var buffer bytes.Buffer
+	for _, part := range sorted {
+		data, err := ReadBlob(ctx, e, multipartPartKey(key, uploadID, part.PartNumber))
+		if err != nil {
+			return fmt.Errorf("missing part %d: %v", part.PartNumber, err)
+		}
+		buffer.Write(data)
+	}
+
+	if err := e.Put(ctx, key, &buffer); err != nil {
+		return err
+	}
+	return e.AbortMultipartUpload(ctx, key, uploadID)
+}
+
+func (e *EncryptedBlobStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	staged, err := e.List(ctx, fmt.Sprintf("%s/%s/%s/", stagingPrefix, uploadID, key))
+	if err != nil {
+		return err
+	}
+	for _, blob := range staged {
+		if err := e.Delete(ctx, blob.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RotateDataKeys re-wraps every data key that is not wrapped with the
+// current key. It works in batches and returns the number of keys moved.
+func (e *EncryptedBlobStore) RotateDataKeys(ctx context.Context, batchSize int) (int, error) {
+	rotated := 0
+
+	for {
+		currentKeyID := e.keys.KeyID()
+
+		var records []models.DataKey
+		if err := e.db.WithContext(ctx).
+			Where("key_id <> ?", currentKeyID).
+			Order("object_key ASC").
+			Limit(batchSize).
+			Find(&records).Error; err != nil {
+			return rotated, err
+		}
+		if len(records) == 0 {
+			return rotated, nil
+		}
+
+		for _, record := range records {
+			dataKey, err := e.keys.Unwrap(ctx, record.KeyID, record.WrappedKey)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to unwrap key for %s: %v", record.ObjectKey, err)
+			}
+
+			wrapped, keyID, err := e.keys.Wrap(ctx, dataKey)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to re-wrap key for %s: %v", record.ObjectKey, err)
+			}
+
+			// Only replace the key that was read, in case the object was
+			// rewritten in the meantime.
+			result := e.db.WithContext(ctx).Model(&models.DataKey{}).
+				Where("object_key = ? AND blob_key = ? AND key_id = ?", record.ObjectKey, record.BlobKey, record.KeyID).
+				Updates(map[string]interface{}{
+					"key_id":      keyID,
+					"wrapped_key": wrapped,
+				})
+			if result.Error != nil {
+				return rotated, result.Error
+			}
+			rotated += int(result.RowsAffected)
+		}
+	}
+}
+
+// StartKeyRotation rotates the master key once it reaches maxAge, where the
+// key manager holds it, and re-wraps data keys onto the current master key.
+// It checks at start and then on the given interval until the context is
+// cancelled.
+func (e *EncryptedBlobStore) StartKeyRotation(ctx context.Context, interval, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		e.rotateKeys(ctx, maxAge)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *EncryptedBlobStore) rotateKeys(ctx context.Context, maxAge time.Duration) {
+	if rotator, ok := e.keys.(MasterKeyRotator); ok {
+		rotated, err := rotator.RotateOlderThan(maxAge)
+		if err != nil {
+			log.Printf("Master key rotation failed: %v", err)
+			return
+		}
+		if rotated {
+			log.Printf("Rotated master key to %s", e.keys.KeyID())
+		}
+	}
+
+	rotated, err := e.RotateDataKeys(ctx, 500)
+	if err != nil {
+		log.Printf("Key rotation failed: %v", err)
+		return
+	}
+	if rotated > 0 {
+		log.Printf("Re-wrapped %d data keys with key %s", rotated, e.keys.KeyID())
+	}
+}
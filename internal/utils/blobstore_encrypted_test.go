@@ -0,0 +1,224 @@
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/blobstoretest"
+)
+
+func newKeyDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "keys.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.DataKey{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newKeyManager(t *testing.T) *utils.LocalKeyManager {
+	t.Helper()
+	keys, err := utils.NewLocalKeyManager(filepath.Join(t.TempDir(), "master.json"))
+	if err != nil {
+		t.Fatalf("create key manager: %v", err)
+	}
+	return keys
+}
+
+func readAll(t *testing.T, store utils.BlobStore, key string) string {
+	t.Helper()
+	data, err := utils.ReadBlob(context.Background(), store, key)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestEncryptedBlobStore(t *testing.T) {
+	blobstoretest.Run(t, func(t *testing.T) utils.BlobStore {
+		return utils.NewEncryptedBlobStore(utils.NewMemoryBlobStore(), newKeyManager(t), newKeyDB(t))
+	})
+}
+
+func TestEncryptedConcurrentPut(t *testing.T) {
+	ctx := context.Background()
+	backing := utils.NewMemoryBlobStore()
+	store := utils.NewEncryptedBlobStore(backing, newKeyManager(t), newKeyDB(t))
+
+	const writers = 16
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Put(ctx, "chunks/shared", bytes.NewReader([]byte(fmt.Sprintf("writer %02d", i)))); err != nil {
+				t.Errorf("put %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got := readAll(t, store, "chunks/shared")
+	if len(got) != len("writer 00") || got[:7] != "writer " {
+		t.Fatalf("got %q, want one writer's content", got)
+	}
+
+	// Every replaced blob is gone, leaving the one the row names.
+	blobs, err := backing.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 {
+		t.Fatalf("backing store holds %d blobs, want 1", len(blobs))
+	}
+}
+
+func TestEncryptedPutFailedKeyWrite(t *testing.T) {
+	ctx := context.Background()
+	db := newKeyDB(t)
+	backing := utils.NewMemoryBlobStore()
+	store := utils.NewEncryptedBlobStore(backing, newKeyManager(t), db)
+
+	if err := db.Migrator().DropTable(&models.DataKey{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "chunks/a", bytes.NewReader([]byte("secret"))); err == nil {
+		t.Fatal("put succeeded without a key table")
+	}
+
+	blobs, err := backing.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("ciphertext left behind: %v", blobs)
+	}
+	if _, err := backing.Get(ctx, "chunks/a", 0, -1); !errors.Is(err, utils.ErrBlobNotFound) {
+		t.Fatalf("got %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestEncryptedReadsLegacyBlobs(t *testing.T) {
+	ctx := context.Background()
+	db := newKeyDB(t)
+	backing := utils.NewMemoryBlobStore()
+	store := utils.NewEncryptedBlobStore(backing, newKeyManager(t), db)
+
+	if err := backing.Put(ctx, "chunks/plain", bytes.NewReader([]byte("plaintext"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "chunks/sealed", bytes.NewReader([]byte("sealed"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readAll(t, store, "chunks/plain"); got != "plaintext" {
+		t.Fatalf("got %q, want %q", got, "plaintext")
+	}
+
+	blobs, err := store.List(ctx, "chunks/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 || blobs[0].Key != "chunks/plain" || blobs[0].Size != 9 ||
+		blobs[1].Key != "chunks/sealed" || blobs[1].Size != 6 {
+		t.Fatalf("unexpected listing: %+v", blobs)
+	}
+}
+
+func TestEncryptedKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	db := newKeyDB(t)
+	path := filepath.Join(t.TempDir(), "master.json")
+	keys, err := utils.NewLocalKeyManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := utils.NewEncryptedBlobStore(utils.NewMemoryBlobStore(), keys, db)
+
+	if err := store.Put(ctx, "chunks/a", bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatal(err)
+	}
+	original := keys.KeyID()
+
+	rotated, err := keys.RotateOlderThan(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated {
+		t.Fatal("rotated a fresh master key")
+	}
+
+	// Age the current key on disk, as if it had been created long ago.
+	var file struct {
+		CurrentKeyID string               `json:"current_key_id"`
+		Keys         map[string]string    `json:"keys"`
+		Created      map[string]time.Time `json:"created"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	file.Created[original] = time.Now().Add(-48 * time.Hour)
+	if data, err = json.Marshal(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err = keys.RotateOlderThan(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rotated || keys.KeyID() == original {
+		t.Fatal("expired master key was not rotated")
+	}
+
+	count, err := store.RotateDataKeys(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("re-wrapped %d data keys, want 1", count)
+	}
+
+	var record models.DataKey
+	if err := db.First(&record, "object_key = ?", "chunks/a").Error; err != nil {
+		t.Fatal(err)
+	}
+	if record.KeyID != keys.KeyID() {
+		t.Fatalf("data key wrapped with %s, want %s", record.KeyID, keys.KeyID())
+	}
+	if got := readAll(t, store, "chunks/a"); got != "hello" {
+		t.Fatalf("got %q after rotation, want %q", got, "hello")
+	}
+}
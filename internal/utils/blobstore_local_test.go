@@ -0,0 +1,18 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/blobstoretest"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	blobstoretest.Run(t, func(t *testing.T) utils.BlobStore {
+		store, err := utils.NewLocalBlobStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("create local store: %v", err)
+		}
+		return store
+	})
+}
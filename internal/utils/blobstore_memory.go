@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type memoryBlob struct {
+	data    []byte
+	modTime time.Time
+}
+
+// MemoryBlobStore keeps blobs in memory. It is meant for tests.
+type MemoryBlobStore struct {
+	mu      sync.RWMutex
+	blobs   map[string]memoryBlob
+	uploads map[string]map[int][]byte
+}
+
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{
+		blobs:   make(map[string]memoryBlob),
+		uploads: make(map[string]map[int][]byte),
+	}
+}
+
+func (m *MemoryBlobStore) Put(ctx context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = memoryBlob{data: data, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemoryBlobStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	m.mu.RLock()
+	blob, ok := m.blobs[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+
+	data, err := sliceRange(blob.data, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MemoryBlobStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *MemoryBlobStore) Head(ctx context.Context, key string) (*BlobInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blob, ok := m.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return &BlobInfo{Key: key, Size: int64(len(blob.data)), ModTime: blob.modTime}, nil
+}
+
+func (m *MemoryBlobStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var blobs []BlobInfo
+	for key, blob := range m.blobs {
+		if strings.HasPrefix(key, prefix) {
+			blobs = append(blobs, BlobInfo{Key: key, Size: int64(len(blob.data)), ModTime: blob.modTime})
+		}
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Key < blobs[j].Key })
+	return blobs, nil
+}
+
+func (m *MemoryBlobStore) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uploadID := uuid.New().String()
+	m.uploads[uploadID] = make(map[int][]byte)
+	return uploadID, nil
+}
+
+func (m *MemoryBlobStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int, reader io.Reader) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		return "", fmt.Errorf("unknown upload: %s", uploadID)
+	}
+	parts[partNumber] = data
+
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (m *MemoryBlobStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uploaded, ok := m.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("unknown upload: %s", uploadID)
+	}
+
+	sorted := append([]CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	var buffer bytes.Buffer
+	for _, part := range sorted {
+		data, ok := uploaded[part.PartNumber]
+		if !ok {
+			return fmt.Errorf("missing part %d", part.PartNumber)
+		}
+		buffer.Write(data)
+	}
+
+	m.blobs[key] = memoryBlob{data: buffer.Bytes(), modTime: time.Now()}
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+func (m *MemoryBlobStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, uploadID)
+	return nil
+}
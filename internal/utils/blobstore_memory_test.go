@@ -0,0 +1,14 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/blobstoretest"
+)
+
+func TestMemoryBlobStore(t *testing.T) {
+	blobstoretest.Run(t, func(t *testing.T) utils.BlobStore {
+		return utils.NewMemoryBlobStore()
+	})
+}
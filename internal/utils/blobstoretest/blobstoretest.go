@@ -0,0 +1,238 @@
+// Package blobstoretest is the conformance suite every utils.BlobStore
+// driver must pass. A driver's tests call Run with a constructor that
+// returns an empty store.
+package blobstoretest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+func Run(t *testing.T, newStore func(t *testing.T) utils.BlobStore) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, store utils.BlobStore)
+	}{
+		{"PutGet", testPutGet},
+		{"Overwrite", testOverwrite},
+		{"GetRange", testGetRange},
+		{"GetMissing", testGetMissing},
+		{"Delete", testDelete},
+		{"Head", testHead},
+		{"List", testList},
+		{"Multipart", testMultipart},
+		{"AbortMultipart", testAbortMultipart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+func put(t *testing.T, store utils.BlobStore, key, content string) {
+	t.Helper()
+	if err := store.Put(context.Background(), key, strings.NewReader(content)); err != nil {
+		t.Fatalf("Put(%q) failed: %v", key, err)
+	}
+}
+
+func get(t *testing.T, store utils.BlobStore, key string, offset, length int64) string {
+	t.Helper()
+	reader, err := store.Get(context.Background(), key, offset, length)
+	if err != nil {
+		t.Fatalf("Get(%q, %d, %d) failed: %v", key, offset, length, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading %q failed: %v", key, err)
+	}
+	return string(data)
+}
+
+func testPutGet(t *testing.T, store utils.BlobStore) {
+	put(t, store, "a/b/c.txt", "hello world")
+	put(t, store, "empty", "")
+
+	if got := get(t, store, "a/b/c.txt", 0, -1); got != "hello world" {
+		t.Errorf("Get returned %q, want %q", got, "hello world")
+	}
+	if got := get(t, store, "empty", 0, -1); got != "" {
+		t.Errorf("Get of empty blob returned %q", got)
+	}
+}
+
+func testOverwrite(t *testing.T, store utils.BlobStore) {
+	put(t, store, "key", "first")
+	put(t, store, "key", "second version")
+
+	if got := get(t, store, "key", 0, -1); got != "second version" {
+		t.Errorf("Get returned %q after overwrite", got)
+	}
+}
+
+func testGetRange(t *testing.T, store utils.BlobStore) {
+	put(t, store, "range", "0123456789")
+
+	cases := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, 4, "0123"},
+		{3, 4, "3456"},
+		{6, -1, "6789"},
+		{8, 10, "89"},
+		{5, 0, ""},
+	}
+	for _, c := range cases {
+		if got := get(t, store, "range", c.offset, c.length); got != c.want {
+			t.Errorf("Get(range, %d, %d) returned %q, want %q", c.offset, c.length, got, c.want)
+		}
+	}
+}
+
+func testGetMissing(t *testing.T, store utils.BlobStore) {
+	_, err := store.Get(context.Background(), "missing", 0, -1)
+	if !errors.Is(err, utils.ErrBlobNotFound) {
+		t.Errorf("Get of missing blob returned %v, want ErrBlobNotFound", err)
+	}
+}
+
+func testDelete(t *testing.T, store utils.BlobStore) {
+	ctx := context.Background()
+	put(t, store, "doomed", "bye")
+
+	if err := store.Delete(ctx, "doomed"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Head(ctx, "doomed"); !errors.Is(err, utils.ErrBlobNotFound) {
+		t.Errorf("Head after Delete returned %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, "doomed"); err != nil {
+		t.Errorf("Delete of missing blob returned %v", err)
+	}
+}
+
+func testHead(t *testing.T, store utils.BlobStore) {
+	ctx := context.Background()
+	put(t, store, "dir/file", "12345")
+
+	info, err := store.Head(ctx, "dir/file")
+	if err != nil {
+		t.Fatalf("Head failed: %v", err)
+	}
+	if info.Key != "dir/file" || info.Size != 5 {
+		t.Errorf("Head returned %+v, want key dir/file and size 5", info)
+	}
+	if info.ModTime.IsZero() {
+		t.Errorf("Head returned a zero modification time")
+	}
+
+	if _, err := store.Head(ctx, "dir/missing"); !errors.Is(err, utils.ErrBlobNotFound) {
+		t.Errorf("Head of missing blob returned %v, want ErrBlobNotFound", err)
+	}
+}
+
+func testList(t *testing.T, store utils.BlobStore) {
+	put(t, store, "user1/b", "bb")
+	put(t, store, "user1/a", "a")
+	put(t, store, "user1/nested/c", "ccc")
+	put(t, store, "user2/a", "a")
+
+	blobs, err := store.List(context.Background(), "user1/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	want := []utils.BlobInfo{
+		{Key: "user1/a", Size: 1},
+		{Key: "user1/b", Size: 2},
+		{Key: "user1/nested/c", Size: 3},
+	}
+	if len(blobs) != len(want) {
+		t.Fatalf("List returned %d blobs, want %d: %+v", len(blobs), len(want), blobs)
+	}
+	for i := range want {
+		if blobs[i].Key != want[i].Key || blobs[i].Size != want[i].Size {
+			t.Errorf("List[%d] = %+v, want key %s and size %d", i, blobs[i], want[i].Key, want[i].Size)
+		}
+	}
+
+	none, err := store.List(context.Background(), "nobody/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("List of unused prefix returned %+v", none)
+	}
+}
+
+func testMultipart(t *testing.T, store utils.BlobStore) {
+	ctx := context.Background()
+
+	uploadID, err := store.CreateMultipartUpload(ctx, "big/object")
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload failed: %v", err)
+	}
+
+	contents := map[int]string{1: "first-", 2: "second-", 3: "third"}
+	var parts []utils.CompletedPart
+	for _, number := range []int{2, 1, 3} {
+		etag, err := store.UploadPart(ctx, "big/object", uploadID, number, bytes.NewReader([]byte(contents[number])))
+		if err != nil {
+			t.Fatalf("UploadPart(%d) failed: %v", number, err)
+		}
+		parts = append(parts, utils.CompletedPart{PartNumber: number, ETag: etag})
+	}
+
+	if err := store.CompleteMultipartUpload(ctx, "big/object", uploadID, parts); err != nil {
+		t.Fatalf("CompleteMultipartUpload failed: %v", err)
+	}
+
+	if got := get(t, store, "big/object", 0, -1); got != "first-second-third" {
+		t.Errorf("Get after multipart upload returned %q", got)
+	}
+
+	blobs, err := store.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(blobs) != 1 {
+		t.Errorf("List after multipart upload returned %+v, want only the completed blob", blobs)
+	}
+}
+
+func testAbortMultipart(t *testing.T, store utils.BlobStore) {
+	ctx := context.Background()
+
+	uploadID, err := store.CreateMultipartUpload(ctx, "aborted")
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload failed: %v", err)
+	}
+	if _, err := store.UploadPart(ctx, "aborted", uploadID, 1, strings.NewReader("data")); err != nil {
+		t.Fatalf("UploadPart failed: %v", err)
+	}
+	if err := store.AbortMultipartUpload(ctx, "aborted", uploadID); err != nil {
+		t.Fatalf("AbortMultipartUpload failed: %v", err)
+	}
+
+	if _, err := store.Head(ctx, "aborted"); !errors.Is(err, utils.ErrBlobNotFound) {
+		t.Errorf("Head after abort returned %v, want ErrBlobNotFound", err)
+	}
+	blobs, err := store.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(blobs) != 0 {
+		t.Errorf("List after abort returned %+v", blobs)
+	}
+}
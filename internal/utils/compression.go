@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CodecIdentity = "identity"
+	CodecZstd     = "zstd"
+)
+
+// incompressibleTypes lists content types, as reported by
+// http.DetectContentType, that are already compressed and gain nothing
+// from another pass.
+var incompressibleTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"video/",
+	"audio/mpeg",
+	"audio/aac",
+	"audio/ogg",
+	"application/zip",
+	"application/x-gzip",
+	"application/x-rar-compressed",
+	"application/pdf",
+	"font/woff",
+	"font/woff2",
+}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// SelectCodec picks the storage codec for content of the given type.
+func SelectCodec(contentType string) string {
+	for _, skip := range incompressibleTypes {
+		if strings.HasPrefix(contentType, skip) {
+			return CodecIdentity
+		}
+	}
+	return CodecZstd
+}
+
+func Compress(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecIdentity, "":
+		return data, nil
+	case CodecZstd:
+		return zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	default:
+		return nil, fmt.Errorf("unsupported codec: %s", codec)
+	}
+}
+
+func Decompress(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecIdentity, "":
+		return data, nil
+	case CodecZstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unsupported codec: %s", codec)
+	}
+}
+
+// NewDecompressReader wraps a stored object so that reads return the
+// original bytes. Closing the returned reader closes the underlying one.
+func NewDecompressReader(codec string, reader io.ReadCloser) (io.ReadCloser, error) {
+	switch codec {
+	case CodecIdentity, "":
+		return reader, nil
+	case CodecZstd:
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			reader.Close()
+			return nil, fmt.Errorf("failed to create zstd reader: %v", err)
+		}
+		return &zstdReadCloser{decoder: decoder, source: reader}, nil
+	default:
+		reader.Close()
+		return nil, fmt.Errorf("unsupported codec: %s", codec)
+	}
+}
+
+type zstdReadCloser struct {
+	decoder *zstd.Decoder
+	source  io.ReadCloser
+}
+
+func (z *zstdReadCloser) Read(p []byte) (int, error) {
+	return z.decoder.Read(p)
+}
+
+func (z *zstdReadCloser) Close() error {
+	z.decoder.Close()
+	return z.source.Close()
+}
@@ -3,8 +3,10 @@ package utils
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -23,15 +25,49 @@ type Config struct {
 	AWSSecretAccessKey string
 	AWSBucketName      string
 
+	// Storage
+	StorageBackend   string
+	LocalStoragePath string
+
 	// Service
 	JWTSecret          string
 	AuthServicePort    int
 	GatewayServicePort int
 	SyncServicePort    int
+	AdminHost          string
+	AuthAdminPort      int
+	SyncAdminPort      int
 
-	// Kafka Configuration
+	// Event bus
+	EventBus     string
+	EventGroupID string
 	KafkaBrokers []string
-	KafkaGroupID string
+	NATSURL      string
+	// EventMaxAttempts is how many times a consumer tries an event before
+	// dead-lettering it.
+	EventMaxAttempts int
+	// EventSchemaVersion is the event schema version producers write.
+	EventSchemaVersion int
+
+	// Outbox
+	OutboxPollInterval time.Duration
+	OutboxRetention    time.Duration
+
+	// Encryption at rest
+	EncryptionKeyManager string
+	LocalKeyFile         string
+	KMSKeyID             string
+	KeyRotationInterval  time.Duration
+	MasterKeyMaxAge      time.Duration
+
+	// Retention
+	RetentionPruneInterval time.Duration
+
+	// Anomaly detection
+	AnomalyWindow           time.Duration
+	AnomalyMaxModifications int
+	AnomalyMaxDeletions     int
+	AnomalyMaxEntropyJumps  int
 }
 
 func LoadConfig() (*Config, error) {
@@ -52,13 +88,46 @@ func LoadConfig() (*Config, error) {
 	config.AWSSecretAccessKey = getEnvString("AWS_SECRET_ACCESS_KEY", "")
 	config.AWSBucketName = getEnvString("AWS_BUCKET_NAME", "")
 
+	// Storage configuration
+	config.StorageBackend = getEnvString("STORAGE_BACKEND", "s3")
+	config.LocalStoragePath = getEnvString("LOCAL_STORAGE_PATH", "data/blobs")
+
 	// Service configuration
 	config.JWTSecret = getEnvString("JWT_SECRET", "")
 	config.AuthServicePort = getEnvInt("AUTH_SERVICE_PORT", 50051)
+	config.GatewayServicePort = getEnvInt("GATEWAY_SERVICE_PORT", 50052)
+	config.SyncServicePort = getEnvInt("SYNC_SERVICE_PORT", 50053)
+	config.AdminHost = getEnvString("ADMIN_HOST", "127.0.0.1")
+	config.AuthAdminPort = getEnvInt("AUTH_ADMIN_PORT", 50061)
+	config.SyncAdminPort = getEnvInt("SYNC_ADMIN_PORT", 50063)
 
-	// Kafka configuration
+	// Event bus configuration
+	config.EventBus = getEnvString("EVENT_BUS", "kafka")
+	config.EventGroupID = getEnvString("EVENT_GROUP_ID", getEnvString("KAFKA_GROUP_ID", "file_sync_group"))
 	config.KafkaBrokers = strings.Split(getEnvString("KAFKA_BROKERS", "localhost:9092"), ",")
-	config.KafkaGroupID = getEnvString("KAFKA_GROUP_ID", "file_sync_group")
+	config.NATSURL = getEnvString("NATS_URL", "nats://localhost:4222")
+	config.EventMaxAttempts = getEnvInt("EVENT_MAX_ATTEMPTS", 5)
+	config.EventSchemaVersion = getEnvInt("EVENT_SCHEMA_VERSION", 2)
+
+	// Outbox configuration
+	config.OutboxPollInterval = getEnvDuration("OUTBOX_POLL_INTERVAL", 200*time.Millisecond)
+	config.OutboxRetention = getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour)
+
+	// Encryption at rest configuration
+	config.EncryptionKeyManager = getEnvString("ENCRYPTION_KEY_MANAGER", "none")
+	config.LocalKeyFile = getEnvString("LOCAL_KEY_FILE", "keys/master.json")
+	config.KMSKeyID = getEnvString("KMS_KEY_ID", "")
+	config.KeyRotationInterval = getEnvDuration("KEY_ROTATION_INTERVAL", 24*time.Hour)
+	config.MasterKeyMaxAge = getEnvDuration("MASTER_KEY_MAX_AGE", 90*24*time.Hour)
+
+	// Retention configuration
+	config.RetentionPruneInterval = getEnvDuration("RETENTION_PRUNE_INTERVAL", time.Hour)
+
+	// Anomaly detection configuration
+	config.AnomalyWindow = getEnvDuration("ANOMALY_WINDOW", time.Minute)
+	config.AnomalyMaxModifications = getEnvInt("ANOMALY_MAX_MODIFICATIONS", 300)
+	config.AnomalyMaxDeletions = getEnvInt("ANOMALY_MAX_DELETIONS", 100)
+	config.AnomalyMaxEntropyJumps = getEnvInt("ANOMALY_MAX_ENTROPY_JUMPS", 20)
 
 	if err := config.validate(); err != nil {
 		return nil, err
@@ -67,6 +136,67 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// AgentConfig configures a sync agent, the client daemon that mirrors a
+// local folder.
+type AgentConfig struct {
+	// SyncRoot is the folder the agent mirrors.
+	SyncRoot string
+	// StatePath is the agent's state database, by default in SyncRoot.
+	StatePath string
+	// DeviceID identifies the agent to the servers. An agent without one
+	// makes one up and keeps it in its state database.
+	DeviceID string
+
+	Email    string
+	Password string
+
+	AuthAddr    string
+	GatewayAddr string
+	SyncAddr    string
+
+	// ScanInterval is how often the agent rescans the folder for changes
+	// its watcher missed.
+	ScanInterval time.Duration
+	// ScanHashRate caps how many bytes per second a scan reads to hash
+	// files; 0 leaves it uncapped.
+	ScanHashRate int
+}
+
+func LoadAgentConfig() (*AgentConfig, error) {
+	_ = godotenv.Load()
+
+	config := &AgentConfig{}
+
+	config.SyncRoot = getEnvString("SYNC_ROOT", "")
+	config.StatePath = getEnvString("SYNC_STATE_PATH", "")
+	config.DeviceID = getEnvString("SYNC_DEVICE_ID", "")
+
+	config.Email = getEnvString("SYNC_EMAIL", "")
+	config.Password = getEnvString("SYNC_PASSWORD", "")
+
+	config.AuthAddr = getEnvString("AUTH_SERVICE_ADDR", "localhost:50051")
+	config.GatewayAddr = getEnvString("GATEWAY_SERVICE_ADDR", "localhost:50052")
+	config.SyncAddr = getEnvString("SYNC_SERVICE_ADDR", "localhost:50053")
+
+	config.ScanInterval = getEnvDuration("SYNC_SCAN_INTERVAL", 15*time.Minute)
+	config.ScanHashRate = getEnvInt("SYNC_SCAN_HASH_RATE", 32*1024*1024)
+
+	if config.SyncRoot == "" {
+		return nil, fmt.Errorf("SYNC_ROOT is required")
+	}
+	if config.Email == "" || config.Password == "" {
+		return nil, fmt.Errorf("SYNC_EMAIL and SYNC_PASSWORD are required")
+	}
+	if config.ScanInterval <= 0 {
+		return nil, fmt.Errorf("SYNC_SCAN_INTERVAL must be positive")
+	}
+	if config.StatePath == "" {
+		config.StatePath = filepath.Join(config.SyncRoot, ".syncd", "state.db")
+	}
+
+	return config, nil
+}
+
 // Helper functions for environment variables
 func getEnvString(key string, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -93,20 +223,51 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
 func (c *Config) validate() error {
 	// Validate required fields
 	if c.JWTSecret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
 	}
 
-	if c.AWSAccessKeyID == "" || c.AWSSecretAccessKey == "" || c.AWSBucketName == "" {
-		return fmt.Errorf("AWS credentials and bucket name are required")
+	switch c.StorageBackend {
+	case "s3":
+		if c.AWSAccessKeyID == "" || c.AWSSecretAccessKey == "" || c.AWSBucketName == "" {
+			return fmt.Errorf("AWS credentials and bucket name are required")
+		}
+	case "local", "memory":
+	default:
+		return fmt.Errorf("unsupported STORAGE_BACKEND: %s", c.StorageBackend)
 	}
 
 	if c.DBPassword == "" {
 		return fmt.Errorf("DB_PASSWORD is required")
 	}
 
+	switch c.EventBus {
+	case "kafka", "nats", "memory":
+	default:
+		return fmt.Errorf("unsupported EVENT_BUS: %s", c.EventBus)
+	}
+
+	switch c.EncryptionKeyManager {
+	case "none", "local":
+	case "aws-kms":
+		if c.KMSKeyID == "" {
+			return fmt.Errorf("KMS_KEY_ID is required when ENCRYPTION_KEY_MANAGER is aws-kms")
+		}
+	default:
+		return fmt.Errorf("unsupported ENCRYPTION_KEY_MANAGER: %s", c.EncryptionKeyManager)
+	}
+
 	return nil
 }
 
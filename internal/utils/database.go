@@ -36,15 +36,80 @@ func InitDistributedDB(config *DBConfig) *gorm.DB {
 		}
 	}
 
+	if err := numberVersions(db); err != nil {
+		log.Fatalf("Failed to number file versions: %v", err)
+	}
+
 	// Auto-migrate the models
-	err = db.AutoMigrate(&models.User{}, &models.File{}, &models.FileVersion{})
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.UserKey{},
+		&models.File{},
+		&models.FileKey{},
+		&models.FileVersion{},
+		&models.Chunk{},
+		&models.ChunkOwner{},
+		&models.VersionChunk{},
+		&models.DataKey{},
+		&models.RetentionPolicy{},
+		&models.RestoreJob{},
+		&models.DeviceAlert{},
+		&models.DeviceActivity{},
+		&models.FileEntropy{},
+		&models.HeldChange{},
+		&models.OutboxEvent{},
+		&models.ProcessedEvent{},
+		&models.DeadLetter{},
+		&models.ChangeEntry{},
+		&models.JournalHead{},
+	)
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Device IDs are only unique per user, so the paused alert index
+	// covers both.
+	if db.Migrator().HasIndex(&models.DeviceAlert{}, "idx_device_alert_paused") {
+		if err := db.Migrator().DropIndex(&models.DeviceAlert{}, "idx_device_alert_paused"); err != nil {
+			log.Fatalf("Failed to drop device alert index: %v", err)
+		}
+	}
+
+	// Users own the chunks of their files stored before ownership was
+	// recorded.
+	if err := db.Exec(`INSERT INTO chunk_owners (chunk_hash, user_id, created_at)
+		SELECT DISTINCT version_chunks.chunk_hash, files.owner_id, CURRENT_TIMESTAMP
+		FROM version_chunks
+		JOIN file_versions ON file_versions.id = version_chunks.version_id
+		JOIN files ON files.id = file_versions.file_id
+		ON CONFLICT DO NOTHING`).Error; err != nil {
+		log.Fatalf("Failed to record chunk owners: %v", err)
+	}
+
+	if err := db.Exec(`UPDATE files SET current_version_id = (
+		SELECT id FROM file_versions WHERE file_versions.file_id = files.id
+		ORDER BY version_num DESC LIMIT 1
+	) WHERE current_version_id IS NULL`).Error; err != nil {
+		log.Fatalf("Failed to set current file versions: %v", err)
+	}
+
 	return db
 }
 
+// numberVersions numbers the versions of files that were stored before
+// versions were numbered, oldest first, so that the unique index on
+// (file_id, version_num) can be created.
+func numberVersions(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.FileVersion{}) {
+		return nil
+	}
+	return db.Exec(`UPDATE file_versions SET version_num = numbered.num FROM (
+		SELECT id, ROW_NUMBER() OVER (PARTITION BY file_id ORDER BY created_at, id) AS num
+		FROM file_versions
+		WHERE file_id IN (SELECT file_id FROM file_versions WHERE version_num = 0)
+	) AS numbered WHERE file_versions.id = numbered.id`).Error
+}
+
 func InitDB(config *Config) *gorm.DB {
 	return InitDistributedDB(&DBConfig{
 		Master: config,
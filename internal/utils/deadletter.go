@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// Headers a dead-lettered message carries besides its original ones. They
+// all start with DeadLetterHeaderPrefix.
+const (
+	DeadLetterHeaderPrefix = "Dead-Letter-"
+
+	DeadLetterTopicHeader     = "Dead-Letter-Topic"
+	DeadLetterGroupHeader     = "Dead-Letter-Group"
+	DeadLetterPartitionHeader = "Dead-Letter-Partition"
+	DeadLetterOffsetHeader    = "Dead-Letter-Offset"
+	DeadLetterAttemptsHeader  = "Dead-Letter-Attempts"
+	DeadLetterErrorHeader     = "Dead-Letter-Error"
+	DeadLetterFailedAtHeader  = "Dead-Letter-Failed-At"
+)
+
+// DeadLetterTopic returns the topic that messages of topic are moved to
+// when they cannot be handled.
+func DeadLetterTopic(topic string) string {
+	return topic + ".dead-letter"
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a message
+// that does not decode. Such messages are dead-lettered right away.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// WithDeadLetter retries a failing message up to maxAttempts deliveries,
+// then publishes it to its dead-letter topic with the failure recorded in
+// its headers and acknowledges it, so one bad message cannot hold up the
+// rest. If dead-lettering fails the message is redelivered.
+func WithDeadLetter(bus EventBus, group string, maxAttempts int, handler Handler) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		err := handler(ctx, msg)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if !errors.As(err, &permanent) && msg.Attempt < maxAttempts {
+			log.Printf("Attempt %d of %d for message %d on %s failed: %v", msg.Attempt, maxAttempts, msg.Offset, msg.Topic, err)
+			return err
+		}
+
+		headers := copyHeaders(msg.Headers)
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[DeadLetterTopicHeader] = msg.Topic
+		headers[DeadLetterGroupHeader] = group
+		headers[DeadLetterPartitionHeader] = strconv.Itoa(msg.Partition)
+		headers[DeadLetterOffsetHeader] = strconv.FormatInt(msg.Offset, 10)
+		headers[DeadLetterAttemptsHeader] = strconv.Itoa(msg.Attempt)
+		headers[DeadLetterErrorHeader] = err.Error()
+		headers[DeadLetterFailedAtHeader] = time.Now().UTC().Format(time.RFC3339Nano)
+
+		if err := bus.Publish(ctx, &Message{
+			Topic:   DeadLetterTopic(msg.Topic),
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: headers,
+		}); err != nil {
+			return fmt.Errorf("failed to dead-letter message: %v", err)
+		}
+
+		log.Printf("Dead-lettered message %d on %s after %d attempts: %v", msg.Offset, msg.Topic, msg.Attempt, err)
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrEventBusClosed = errors.New("event bus closed")
+
+// EventIDHeader carries the ID consumers deduplicate events by.
+const EventIDHeader = "Event-ID"
+
+// A rejected message is handed to its group again after a delay that
+// doubles with every attempt, up to maxRedeliveryDelay.
+const (
+	redeliveryDelay    = 500 * time.Millisecond
+	maxRedeliveryDelay = 30 * time.Second
+)
+
+// Message is an event on a topic. Messages with the same key are delivered
+// in the order they were published.
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+
+	// Set on delivery. Offset is the message's position in its partition
+	// and Attempt counts deliveries to the group, starting at 1.
+	Partition int
+	Offset    int64
+	Timestamp time.Time
+	Attempt   int
+}
+
+type StartPosition int
+
+const (
+	// StartLatest makes a new group receive only messages published after
+	// it first subscribes.
+	StartLatest StartPosition = iota
+	// StartEarliest makes a new group receive every retained message.
+	StartEarliest
+)
+
+type SubscribeOptions struct {
+	// Group is required. Each group receives every message once;
+	// subscribers that share a group split its messages between them.
+	Group string
+	// Start only applies the first time a group subscribes. Later
+	// subscriptions resume after the group's last acknowledged message.
+	Start StartPosition
+}
+
+// Handler processes a delivered message. Returning nil acknowledges it;
+// returning an error redelivers it to the group after a backoff delay.
+type Handler func(ctx context.Context, msg *Message) error
+
+// EventBus carries events between services.
+type EventBus interface {
+	Publish(ctx context.Context, msg *Message) error
+	// Subscribe delivers the topic's messages to the handler, one at a
+	// time, until the context is cancelled or the bus is closed.
+	Subscribe(ctx context.Context, topic string, opts SubscribeOptions, handler Handler) error
+	Close() error
+}
+
+// NewEventBus builds the event bus selected by the configuration.
+func NewEventBus(cfg *Config) (EventBus, error) {
+	switch cfg.EventBus {
+	case "kafka":
+		return NewKafkaEventBus(cfg.KafkaBrokers), nil
+	case "nats":
+		return NewNATSEventBus(cfg.NATSURL)
+	case "memory":
+		return NewMemoryEventBus(), nil
+	default:
+		return nil, fmt.Errorf("unsupported event bus: %s", cfg.EventBus)
+	}
+}
+
+// deliver hands a message to the handler until it is acknowledged or the
+// context is cancelled.
+func deliver(ctx context.Context, msg Message, handler Handler) error {
+	for attempt := 1; ; attempt++ {
+		msg.Attempt = attempt
+		if err := handler(ctx, &msg); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoffDelay(attempt)):
+		}
+	}
+}
+
+// backoffDelay returns how long to wait before redelivering a message
+// that failed its given attempt.
+func backoffDelay(attempt int) time.Duration {
+	delay := redeliveryDelay
+	for i := 1; i < attempt && delay < maxRedeliveryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRedeliveryDelay {
+		delay = maxRedeliveryDelay
+	}
+	return delay
+}
+
+func copyHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(headers))
+	for k, v := range headers {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchTimeout is how long the writer waits for more messages to
+// send along with one. Publish sends a message at a time and waits for it,
+// so the writer's default of a second would be added to every event.
+const kafkaBatchTimeout = 5 * time.Millisecond
+
+// KafkaEventBus maps topics and groups onto Kafka topics and consumer
+// groups. Messages are partitioned by key.
+type KafkaEventBus struct {
+	brokers   []string
+	writer    *kafka.Writer
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewKafkaEventBus(brokers []string) *KafkaEventBus {
+	return &KafkaEventBus{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: kafkaBatchTimeout,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+func (k *KafkaEventBus) Publish(ctx context.Context, msg *Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for key, value := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	err := k.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     []byte(msg.Key),
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write message: %v", err)
+	}
+	return nil
+}
+
+// Subscribe joins the consumer group and commits each message once the
+// handler acknowledges it. A rejected message is retried in place, which
+// holds back the rest of its partition.
+func (k *KafkaEventBus) Subscribe(ctx context.Context, topic string, opts SubscribeOptions, handler Handler) error {
+	if opts.Group == "" {
+		return fmt.Errorf("a consumer group is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-k.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	startOffset := kafka.LastOffset
+	if opts.Start == StartEarliest {
+		startOffset = kafka.FirstOffset
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     k.brokers,
+		Topic:       topic,
+		GroupID:     opts.Group,
+		StartOffset: startOffset,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Failed to read Kafka message from %s: %v", topic, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		headers := make(map[string]string, len(msg.Headers))
+		for _, header := range msg.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+
+		err = deliver(ctx, Message{
+			Topic:     msg.Topic,
+			Key:       string(msg.Key),
+			Value:     msg.Value,
+			Headers:   headers,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Timestamp: msg.Time,
+		}, handler)
+		if err != nil {
+			return nil
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to commit message: %v", err)
+		}
+	}
+}
+
+func (k *KafkaEventBus) Close() error {
+	k.closeOnce.Do(func() { close(k.done) })
+	if err := k.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close writer: %v", err)
+	}
+	return nil
+}
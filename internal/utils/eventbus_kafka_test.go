@@ -0,0 +1,23 @@
+package utils_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/eventbustest"
+)
+
+// TestKafkaEventBus runs against the comma-separated brokers in
+// KAFKA_TEST_BROKERS, which must allow topics to be created on first use.
+func TestKafkaEventBus(t *testing.T) {
+	brokers := os.Getenv("KAFKA_TEST_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_TEST_BROKERS not set")
+	}
+
+	eventbustest.Run(t, func(t *testing.T) utils.EventBus {
+		return utils.NewKafkaEventBus(strings.Split(brokers, ","))
+	})
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryRetention is how many messages a topic keeps. Like a broker's
+// retention, it does not depend on which groups have handled them.
+const memoryRetention = 100000
+
+type memoryTopic struct {
+	// messages[i] has offset base+i.
+	messages []Message
+	base     int64
+	groups   map[string]*memoryGroup
+	// wake is closed and replaced whenever subscribers may have work.
+	wake chan struct{}
+}
+
+type memoryGroup struct {
+	next int64
+	busy bool
+}
+
+// MemoryEventBus is an in-process bus for tests and single-node
+// deployments. Each group handles one message at a time, so delivery is
+// in publish order across all keys.
+type MemoryEventBus struct {
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+	closed bool
+	done   chan struct{}
+}
+
+func NewMemoryEventBus() *MemoryEventBus {
+	return &MemoryEventBus{
+		topics: make(map[string]*memoryTopic),
+		done:   make(chan struct{}),
+	}
+}
+
+func (m *MemoryEventBus) Publish(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrEventBusClosed
+	}
+
+	topic := m.topic(msg.Topic)
+	topic.messages = append(topic.messages, Message{
+		Topic:     msg.Topic,
+		Key:       msg.Key,
+		Value:     append([]byte(nil), msg.Value...),
+		Headers:   copyHeaders(msg.Headers),
+		Offset:    topic.base + int64(len(topic.messages)),
+		Timestamp: time.Now(),
+	})
+	topic.compact()
+	topic.notify()
+	return nil
+}
+
+func (m *MemoryEventBus) Subscribe(ctx context.Context, name string, opts SubscribeOptions, handler Handler) error {
+	if opts.Group == "" {
+		return fmt.Errorf("a consumer group is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	m.mu.Lock()
+	topic := m.topic(name)
+	group, ok := topic.groups[opts.Group]
+	if !ok {
+		group = &memoryGroup{next: topic.base}
+		if opts.Start == StartLatest {
+			group.next = topic.base + int64(len(topic.messages))
+		}
+		topic.groups[opts.Group] = group
+	}
+	m.mu.Unlock()
+
+	for {
+		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			return nil
+		}
+		if group.next < topic.base {
+			group.next = topic.base
+		}
+
+		if group.busy || group.next >= topic.base+int64(len(topic.messages)) {
+			wake := topic.wake
+			m.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-wake:
+			}
+			continue
+		}
+
+		msg := topic.messages[group.next-topic.base]
+		msg.Headers = copyHeaders(msg.Headers)
+		group.busy = true
+		m.mu.Unlock()
+
+		err := deliver(ctx, msg, handler)
+
+		m.mu.Lock()
+		group.busy = false
+		if err == nil && group.next == msg.Offset {
+			group.next++
+		}
+		topic.notify()
+		m.mu.Unlock()
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func (m *MemoryEventBus) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+	return nil
+}
+
+func (m *MemoryEventBus) topic(name string) *memoryTopic {
+	topic, ok := m.topics[name]
+	if !ok {
+		topic = &memoryTopic{
+			groups: make(map[string]*memoryGroup),
+			wake:   make(chan struct{}),
+		}
+		m.topics[name] = topic
+	}
+	return topic
+}
+
+func (t *memoryTopic) notify() {
+	close(t.wake)
+	t.wake = make(chan struct{})
+}
+
+// compact drops the oldest messages beyond the retention limit.
+func (t *memoryTopic) compact() {
+	if len(t.messages) <= memoryRetention {
+		return
+	}
+	dropped := len(t.messages) - memoryRetention
+	t.messages = t.messages[dropped:]
+	t.base += int64(dropped)
+}
@@ -0,0 +1,14 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/eventbustest"
+)
+
+func TestMemoryEventBus(t *testing.T) {
+	eventbustest.Run(t, func(t *testing.T) utils.EventBus {
+		return utils.NewMemoryEventBus()
+	})
+}
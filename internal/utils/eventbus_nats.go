@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsKeyHeader carries the message key, which JetStream has no field for.
+const natsKeyHeader = "Event-Key"
+
+// NATSEventBus maps each topic onto a JetStream stream of the same name
+// and each group onto a durable consumer. A group has one message in
+// flight at a time, so delivery is in publish order across all keys.
+type NATSEventBus struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+
+	mu      sync.Mutex
+	streams map[string]bool
+	done    chan struct{}
+	closed  bool
+}
+
+func NewNATSEventBus(url string) (*NATSEventBus, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %v", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %v", err)
+	}
+
+	return &NATSEventBus{
+		conn:    conn,
+		js:      js,
+		streams: make(map[string]bool),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+func (n *NATSEventBus) Publish(ctx context.Context, msg *Message) error {
+	if err := n.ensureStream(ctx, msg.Topic); err != nil {
+		return err
+	}
+
+	out := nats.NewMsg(msg.Topic)
+	out.Data = msg.Value
+	for key, value := range msg.Headers {
+		out.Header.Set(key, value)
+	}
+	out.Header.Set(natsKeyHeader, msg.Key)
+
+	if _, err := n.js.PublishMsg(ctx, out); err != nil {
+		return fmt.Errorf("failed to publish message: %v", err)
+	}
+	return nil
+}
+
+// Subscribe pulls from the group's durable consumer. A rejected message is
+// negatively acknowledged and redelivered by the server.
+func (n *NATSEventBus) Subscribe(ctx context.Context, topic string, opts SubscribeOptions, handler Handler) error {
+	if opts.Group == "" {
+		return fmt.Errorf("a consumer group is required")
+	}
+	if err := n.ensureStream(ctx, topic); err != nil {
+		return err
+	}
+
+	deliverPolicy := jetstream.DeliverNewPolicy
+	if opts.Start == StartEarliest {
+		deliverPolicy = jetstream.DeliverAllPolicy
+	}
+
+	// The start position only applies to a new group, so an existing
+	// consumer is reused as it is.
+	stream, durable := natsStreamName(topic), natsStreamName(opts.Group)
+	consumer, err := n.js.Consumer(ctx, stream, durable)
+	if errors.Is(err, jetstream.ErrConsumerNotFound) {
+		consumer, err = n.js.CreateConsumer(ctx, stream, jetstream.ConsumerConfig{
+			Durable:       durable,
+			FilterSubject: topic,
+			DeliverPolicy: deliverPolicy,
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			MaxAckPending: 1,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open consumer %s: %v", durable, err)
+	}
+
+	messages, err := consumer.Messages()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %v", err)
+	}
+	defer messages.Stop()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-n.done:
+		}
+		messages.Stop()
+	}()
+
+	for {
+		msg, err := messages.Next()
+		if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to read NATS message from %s: %v", topic, err)
+			continue
+		}
+
+		metadata, err := msg.Metadata()
+		if err != nil {
+			log.Printf("Skipping NATS message without metadata on %s: %v", topic, err)
+			msg.Term()
+			continue
+		}
+
+		headers := make(map[string]string, len(msg.Headers()))
+		for key := range msg.Headers() {
+			if key != natsKeyHeader {
+				headers[key] = msg.Headers().Get(key)
+			}
+		}
+
+		delivered := Message{
+			Topic:     topic,
+			Key:       msg.Headers().Get(natsKeyHeader),
+			Value:     msg.Data(),
+			Headers:   headers,
+			Offset:    int64(metadata.Sequence.Stream),
+			Timestamp: metadata.Timestamp,
+			Attempt:   int(metadata.NumDelivered),
+		}
+
+		if err := handler(ctx, &delivered); err != nil {
+			if err := msg.NakWithDelay(backoffDelay(delivered.Attempt)); err != nil {
+				log.Printf("Failed to reject NATS message %d on %s: %v", delivered.Offset, topic, err)
+			}
+			continue
+		}
+		if err := msg.DoubleAck(ctx); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to acknowledge message: %v", err)
+		}
+	}
+}
+
+func (n *NATSEventBus) Close() error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.done)
+	}
+	n.mu.Unlock()
+
+	return n.conn.Drain()
+}
+
+// ensureStream creates the topic's stream the first time it is used.
+func (n *NATSEventBus) ensureStream(ctx context.Context, topic string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return ErrEventBusClosed
+	}
+	if n.streams[topic] {
+		return nil
+	}
+
+	_, err := n.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     natsStreamName(topic),
+		Subjects: []string{topic},
+		Storage:  jetstream.FileStorage,
+		MaxAge:   7 * 24 * time.Hour,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream %s: %v", topic, err)
+	}
+
+	n.streams[topic] = true
+	return nil
+}
+
+// natsStreamName replaces the characters JetStream does not allow in
+// stream and consumer names.
+func natsStreamName(name string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(name)
+}
@@ -0,0 +1,26 @@
+package utils_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/eventbustest"
+)
+
+// TestNATSEventBus runs against the server at NATS_TEST_URL, which must
+// have JetStream enabled.
+func TestNATSEventBus(t *testing.T) {
+	url := os.Getenv("NATS_TEST_URL")
+	if url == "" {
+		t.Skip("NATS_TEST_URL not set")
+	}
+
+	eventbustest.Run(t, func(t *testing.T) utils.EventBus {
+		bus, err := utils.NewNATSEventBus(url)
+		if err != nil {
+			t.Fatalf("connect to NATS: %v", err)
+		}
+		return bus
+	})
+}
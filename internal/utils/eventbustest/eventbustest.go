@@ -0,0 +1,319 @@
+// Package eventbustest is the conformance suite every utils.EventBus
+// driver must pass. A driver's tests call Run with a constructor that
+// returns a connected bus; each test uses its own topic, so the bus may be
+// shared with other data.
+package eventbustest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// timeout bounds every wait for a delivery.
+const timeout = 30 * time.Second
+
+func Run(t *testing.T, newBus func(t *testing.T) utils.EventBus) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, bus utils.EventBus)
+	}{
+		{"PublishSubscribe", testPublishSubscribe},
+		{"StartLatest", testStartLatest},
+		{"Groups", testGroups},
+		{"SharedGroup", testSharedGroup},
+		{"KeyOrder", testKeyOrder},
+		{"Redelivery", testRedelivery},
+		{"Resume", testResume},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newBus(t)
+			t.Cleanup(func() { bus.Close() })
+			tt.fn(t, bus)
+		})
+	}
+}
+
+func newTopic() string {
+	return "conformance-" + uuid.New().String()
+}
+
+func publish(t *testing.T, bus utils.EventBus, topic, key, value string) {
+	t.Helper()
+	err := bus.Publish(context.Background(), &utils.Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   []byte(value),
+		Headers: map[string]string{"value": value},
+	})
+	if err != nil {
+		t.Fatalf("Publish(%q) failed: %v", value, err)
+	}
+}
+
+// subscription collects the values delivered to one subscriber.
+type subscription struct {
+	mu       gosync.Mutex
+	received []*utils.Message
+	arrived  chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// subscribe starts a subscriber that acknowledges every message the
+// reject function returns false for.
+func subscribe(t *testing.T, bus utils.EventBus, topic string, opts utils.SubscribeOptions, reject func(*utils.Message) bool) *subscription {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &subscription{
+		arrived: make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(sub.done)
+		err := bus.Subscribe(ctx, topic, opts, func(ctx context.Context, msg *utils.Message) error {
+			copied := *msg
+			sub.mu.Lock()
+			sub.received = append(sub.received, &copied)
+			sub.mu.Unlock()
+
+			select {
+			case sub.arrived <- struct{}{}:
+			default:
+			}
+
+			if reject != nil && reject(msg) {
+				return errors.New("rejected")
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, utils.ErrEventBusClosed) {
+			t.Errorf("Subscribe(%q) failed: %v", topic, err)
+		}
+	}()
+
+	t.Cleanup(sub.stop)
+	return sub
+}
+
+func (s *subscription) stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *subscription) messages() []*utils.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*utils.Message(nil), s.received...)
+}
+
+func (s *subscription) values() []string {
+	var values []string
+	for _, msg := range s.messages() {
+		values = append(values, string(msg.Value))
+	}
+	return values
+}
+
+// waitFor waits until the subscriptions together have received at least n
+// messages.
+func waitFor(t *testing.T, n int, subs ...*subscription) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		total := 0
+		for _, sub := range subs {
+			total += len(sub.messages())
+		}
+		if total >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d messages, want %d", total, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// settle gives a bus time to deliver anything it should not have.
+func settle() {
+	time.Sleep(time.Second)
+}
+
+func testPublishSubscribe(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	for i := 0; i < 3; i++ {
+		publish(t, bus, topic, "k", fmt.Sprint(i))
+	}
+
+	sub := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "g", Start: utils.StartEarliest}, nil)
+	waitFor(t, 3, sub)
+
+	for i, msg := range sub.messages()[:3] {
+		if got, want := string(msg.Value), fmt.Sprint(i); got != want {
+			t.Errorf("message %d = %q, want %q", i, got, want)
+		}
+		if msg.Topic != topic || msg.Key != "k" {
+			t.Errorf("message %d has topic %q key %q, want %q %q", i, msg.Topic, msg.Key, topic, "k")
+		}
+		if msg.Headers["value"] != string(msg.Value) {
+			t.Errorf("message %d has headers %v", i, msg.Headers)
+		}
+		if msg.Attempt != 1 {
+			t.Errorf("message %d has attempt %d, want 1", i, msg.Attempt)
+		}
+		if i > 0 && msg.Offset <= sub.messages()[i-1].Offset {
+			t.Errorf("message %d has offset %d, not after %d", i, msg.Offset, sub.messages()[i-1].Offset)
+		}
+	}
+}
+
+func testStartLatest(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	publish(t, bus, topic, "k", "old")
+
+	sub := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "g", Start: utils.StartLatest}, nil)
+
+	// The group may take a moment to join, so keep publishing until it
+	// receives something.
+	deadline := time.Now().Add(timeout)
+	for len(sub.messages()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("received nothing after subscribing")
+		}
+		publish(t, bus, topic, "k", "new")
+		select {
+		case <-sub.arrived:
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	for _, value := range sub.values() {
+		if value != "new" {
+			t.Fatalf("received %q published before the group subscribed", value)
+		}
+	}
+}
+
+func testGroups(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	for i := 0; i < 5; i++ {
+		publish(t, bus, topic, "k", fmt.Sprint(i))
+	}
+
+	first := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "first", Start: utils.StartEarliest}, nil)
+	second := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "second", Start: utils.StartEarliest}, nil)
+	waitFor(t, 5, first)
+	waitFor(t, 5, second)
+	settle()
+
+	for _, sub := range []*subscription{first, second} {
+		if got := strings.Join(sub.values(), ","); got != "0,1,2,3,4" {
+			t.Errorf("group received %s, want 0,1,2,3,4", got)
+		}
+	}
+}
+
+func testSharedGroup(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	const count = 20
+	for i := 0; i < count; i++ {
+		publish(t, bus, topic, fmt.Sprint("k", i%4), fmt.Sprint(i))
+	}
+
+	opts := utils.SubscribeOptions{Group: "shared", Start: utils.StartEarliest}
+	first := subscribe(t, bus, topic, opts, nil)
+	second := subscribe(t, bus, topic, opts, nil)
+	waitFor(t, count, first, second)
+	settle()
+
+	seen := make(map[string]int)
+	for _, sub := range []*subscription{first, second} {
+		for _, value := range sub.values() {
+			seen[value]++
+		}
+	}
+	for i := 0; i < count; i++ {
+		if n := seen[fmt.Sprint(i)]; n != 1 {
+			t.Errorf("message %d delivered %d times, want 1", i, n)
+		}
+	}
+}
+
+func testKeyOrder(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	const count = 30
+	for i := 0; i < count; i++ {
+		publish(t, bus, topic, fmt.Sprint("k", i%3), fmt.Sprint(i))
+	}
+
+	sub := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "g", Start: utils.StartEarliest}, nil)
+	waitFor(t, count, sub)
+
+	last := make(map[string]int)
+	for _, msg := range sub.messages() {
+		var n int
+		fmt.Sscan(string(msg.Value), &n)
+		if prev, ok := last[msg.Key]; ok && n <= prev {
+			t.Errorf("key %s delivered %d after %d", msg.Key, n, prev)
+		}
+		last[msg.Key] = n
+	}
+}
+
+func testRedelivery(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	publish(t, bus, topic, "k", "first")
+	publish(t, bus, topic, "k", "second")
+
+	sub := subscribe(t, bus, topic, utils.SubscribeOptions{Group: "g", Start: utils.StartEarliest}, func(msg *utils.Message) bool {
+		return string(msg.Value) == "first" && msg.Attempt < 2
+	})
+	waitFor(t, 3, sub)
+
+	messages := sub.messages()
+	if got := strings.Join(sub.values()[:3], ","); got != "first,first,second" {
+		t.Fatalf("received %s, want first,first,second", got)
+	}
+	if messages[0].Attempt != 1 || messages[1].Attempt != 2 {
+		t.Errorf("attempts = %d, %d, want 1, 2", messages[0].Attempt, messages[1].Attempt)
+	}
+	if messages[0].Offset != messages[1].Offset {
+		t.Errorf("redelivered offset %d, want %d", messages[1].Offset, messages[0].Offset)
+	}
+}
+
+func testResume(t *testing.T, bus utils.EventBus) {
+	topic := newTopic()
+	opts := utils.SubscribeOptions{Group: "g", Start: utils.StartEarliest}
+	publish(t, bus, topic, "k", "0")
+	publish(t, bus, topic, "k", "1")
+
+	sub := subscribe(t, bus, topic, opts, nil)
+	waitFor(t, 2, sub)
+	sub.stop()
+
+	publish(t, bus, topic, "k", "2")
+
+	resumed := subscribe(t, bus, topic, opts, nil)
+	waitFor(t, 1, resumed)
+	settle()
+
+	if got := strings.Join(resumed.values(), ","); got != "2" {
+		t.Errorf("resumed group received %s, want 2", got)
+	}
+}
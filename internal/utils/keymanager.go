@@ -0,0 +1,354 @@
+package utils
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/google/uuid"
+)
+
+const dataKeySize = 32
+
+// KeyManager wraps and unwraps the per-object data keys used for
+// encryption at rest.
+type KeyManager interface {
+	// KeyID identifies the key new data keys are wrapped with.
+	KeyID() string
+	// GenerateDataKey returns a fresh data key and its wrapped form.
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, keyID string, err error)
+	Wrap(ctx context.Context, plaintext []byte) (wrapped []byte, keyID string, err error)
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// NewKeyManager builds the key manager selected by the configuration. It
+// returns nil when encryption at rest is disabled.
+func NewKeyManager(ctx context.Context, cfg *Config) (KeyManager, error) {
+	switch cfg.EncryptionKeyManager {
+	case "local":
+		return NewLocalKeyManager(cfg.LocalKeyFile)
+	case "aws-kms":
+		return NewKMSKeyManager(ctx, cfg.AWSRegion, cfg.KMSKeyID)
+	default:
+		return nil, nil
+	}
+}
+
+// MasterKeyRotator is implemented by key managers that keep their own
+// master keys and so have to rotate them themselves.
+type MasterKeyRotator interface {
+	// RotateOlderThan makes a new master key current if the current one
+	// is older than maxAge, and reports whether it did.
+	RotateOlderThan(maxAge time.Duration) (bool, error)
+}
+
+type localKeyFile struct {
+	CurrentKeyID string               `json:"current_key_id"`
+	Keys         map[string]string    `json:"keys"`
+	Created      map[string]time.Time `json:"created,omitempty"`
+}
+
+// LocalKeyManager keeps master keys in a JSON file on disk. Old keys stay
+// in the file so data keys wrapped with them can still be unwrapped until
+// they are re-wrapped.
+type LocalKeyManager struct {
+	mu      sync.RWMutex
+	path    string
+	current string
+	keys    map[string][]byte
+	created map[string]time.Time
+}
+
+func NewLocalKeyManager(path string) (*LocalKeyManager, error) {
+	km := &LocalKeyManager{
+		path:    path,
+		keys:    make(map[string][]byte),
+		created: make(map[string]time.Time),
+	}
+
+	err := km.load()
+	if os.IsNotExist(err) {
+		// Instances starting together create the file once.
+		if _, err := km.rotateIf(func() bool { return km.current == "" }); err != nil {
+			return nil, err
+		}
+		return km, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// load reads the key file, picking up keys added by other instances that
+// share it.
+func (km *LocalKeyManager) load() error {
+	data, err := os.ReadFile(km.path)
+	if os.IsNotExist(err) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	var file localKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse key file: %v", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != dataKeySize {
+			return fmt.Errorf("invalid master key %s in key file", id)
+		}
+		keys[id] = key
+	}
+
+	if _, ok := keys[file.CurrentKeyID]; !ok {
+		return fmt.Errorf("current key %s not found in key file", file.CurrentKeyID)
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	for id, key := range keys {
+		km.keys[id] = key
+	}
+	for id, created := range file.Created {
+		km.created[id] = created
+	}
+	km.current = file.CurrentKeyID
+	return nil
+}
+
+// Rotate adds a new master key and makes it current. Existing data keys
+// keep working and are moved over by the key rotation job.
+func (km *LocalKeyManager) Rotate() error {
+	_, err := km.rotateIf(func() bool { return true })
+	return err
+}
+
+// RotateOlderThan rotates the master key once it reaches maxAge. Keys from
+// files written before creation times were recorded count as expired.
+func (km *LocalKeyManager) RotateOlderThan(maxAge time.Duration) (bool, error) {
+	return km.rotateIf(func() bool {
+		created, ok := km.created[km.current]
+		return !ok || time.Since(created) >= maxAge
+	})
+}
+
+// rotateIf adds a new master key if due reports it is needed once the file
+// is locked and reloaded. Instances sharing the file rotate one at a time
+// and each keeps the keys the others added, so no key is ever dropped.
+func (km *LocalKeyManager) rotateIf(due func() bool) (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(km.path), 0o700); err != nil {
+		return false, fmt.Errorf("failed to create key directory: %v", err)
+	}
+	unlock, err := lockFile(km.path + ".lock")
+	if err != nil {
+		return false, fmt.Errorf("failed to lock key file: %v", err)
+	}
+	defer unlock()
+
+	// Another instance may have rotated while this one waited.
+	if err := km.load(); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return false, fmt.Errorf("failed to generate master key: %v", err)
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if !due() {
+		return false, nil
+	}
+
+	id := uuid.New().String()
+	km.keys[id] = key
+	km.created[id] = time.Now().UTC()
+	km.current = id
+
+	file := localKeyFile{
+		CurrentKeyID: id,
+		Keys:         make(map[string]string, len(km.keys)),
+		Created:      km.created,
+	}
+	for keyID, k := range km.keys {
+		file.Keys[keyID] = base64.StdEncoding.EncodeToString(k)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return false, err
+	}
+
+	tmp := km.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return false, fmt.Errorf("failed to write key file: %v", err)
+	}
+	if err := os.Rename(tmp, km.path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (km *LocalKeyManager) KeyID() string {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.current
+}
+
+func (km *LocalKeyManager) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	plaintext := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	wrapped, keyID, err := km.Wrap(ctx, plaintext)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return plaintext, wrapped, keyID, nil
+}
+
+func (km *LocalKeyManager) Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error) {
+	km.mu.RLock()
+	keyID := km.current
+	master := km.keys[keyID]
+	km.mu.RUnlock()
+
+	wrapped, err := sealAESGCM(master, plaintext)
+	if err != nil {
+		return nil, "", err
+	}
+	return wrapped, keyID, nil
+}
+
+func (km *LocalKeyManager) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	km.mu.RLock()
+	master, ok := km.keys[keyID]
+	km.mu.RUnlock()
+
+	if !ok {
+		// The key may have been added by another instance.
+		if err := km.load(); err != nil {
+			return nil, err
+		}
+		km.mu.RLock()
+		master, ok = km.keys[keyID]
+		km.mu.RUnlock()
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown master key: %s", keyID)
+	}
+	return openAESGCM(master, wrapped)
+}
+
+// KMSKeyManager wraps data keys with an AWS KMS key. KMS rotates the key
+// material behind a key ID itself; pointing KMS_KEY_ID at a new key makes
+// the rotation job re-wrap every data key with it.
+type KMSKeyManager struct {
+	client *kms.Client
+	keyID  string
+}
+
+func NewKMSKeyManager(ctx context.Context, region, keyID string) (*KMSKeyManager, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config: %v", err)
+	}
+
+	return &KMSKeyManager{
+		client: kms.NewFromConfig(cfg),
+		keyID:  keyID,
+	}, nil
+}
+
+func (km *KMSKeyManager) KeyID() string {
+	return km.keyID
+}
+
+func (km *KMSKeyManager) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	result, err := km.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(km.keyID),
+		KeySpec: types.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+	return result.Plaintext, result.CiphertextBlob, km.keyID, nil
+}
+
+func (km *KMSKeyManager) Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error) {
+	result, err := km.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(km.keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data key: %v", err)
+	}
+	return result.CiphertextBlob, km.keyID, nil
+}
+
+func (km *KMSKeyManager) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	result, err := km.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+	return result.Plaintext, nil
+}
+
+// sealAESGCM encrypts data and prepends the random nonce.
+func sealAESGCM(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func openAESGCM(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package utils
+
+import "sync"
+
+// keyFileLock stands in for a file lock where none is available. It only
+// orders rotations within one process, so on these systems a key file
+// must not be shared by several instances.
+var keyFileLock sync.Mutex
+
+func lockFile(path string) (func(), error) {
+	keyFileLock.Lock()
+	return keyFileLock.Unlock, nil
+}
@@ -0,0 +1,90 @@
+package utils_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+)
+
+// Instances sharing a key file that rotate at once each keep the keys the
+// others added, and only one rotates an expired key.
+func TestLocalKeyManagerSharedRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "master.json")
+
+	const instances = 8
+	managers := make([]*utils.LocalKeyManager, instances)
+	for i := range managers {
+		keys, err := utils.NewLocalKeyManager(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		managers[i] = keys
+	}
+	for _, keys := range managers[1:] {
+		if keys.KeyID() != managers[0].KeyID() {
+			t.Fatal("instances created different first keys")
+		}
+	}
+
+	// Each instance rotates and wraps a data key with its new master key.
+	var wg sync.WaitGroup
+	wrapped := make([][]byte, instances)
+	keyIDs := make([]string, instances)
+	for i, keys := range managers {
+		wg.Add(1)
+		go func(i int, keys *utils.LocalKeyManager) {
+			defer wg.Done()
+			if err := keys.Rotate(); err != nil {
+				t.Error(err)
+				return
+			}
+			var err error
+			wrapped[i], keyIDs[i], err = keys.Wrap(ctx, []byte("0123456789abcdef0123456789abcdef"))
+			if err != nil {
+				t.Error(err)
+			}
+		}(i, keys)
+	}
+	wg.Wait()
+
+	// A restarted instance can unwrap all of them.
+	restarted, err := utils.NewLocalKeyManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range managers {
+		if _, err := restarted.Unwrap(ctx, keyIDs[i], wrapped[i]); err != nil {
+			t.Fatalf("key %s of instance %d lost: %v", keyIDs[i], i, err)
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	rotations := make(chan bool, instances)
+	for _, keys := range managers {
+		wg.Add(1)
+		go func(keys *utils.LocalKeyManager) {
+			defer wg.Done()
+			rotated, err := keys.RotateOlderThan(100 * time.Millisecond)
+			if err != nil {
+				t.Error(err)
+			}
+			rotations <- rotated
+		}(keys)
+	}
+	wg.Wait()
+	close(rotations)
+	count := 0
+	for rotated := range rotations {
+		if rotated {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("%d instances rotated the expired key, want 1", count)
+	}
+}
@@ -0,0 +1,46 @@
+package utils_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/utils/blobstoretest"
+)
+
+// TestS3BlobStore runs against the bucket named by S3_TEST_BUCKET, which
+// must be empty and used for nothing else: every test empties it again.
+func TestS3BlobStore(t *testing.T) {
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		t.Skip("S3_TEST_BUCKET not set")
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	blobstoretest.Run(t, func(t *testing.T) utils.BlobStore {
+		ctx := context.Background()
+		store, err := utils.NewS3Client(ctx, region, bucket)
+		if err != nil {
+			t.Fatalf("create S3 client: %v", err)
+		}
+
+		empty := func() {
+			blobs, err := store.List(ctx, "")
+			if err != nil {
+				t.Fatalf("list bucket: %v", err)
+			}
+			for _, blob := range blobs {
+				if err := store.Delete(ctx, blob.Key); err != nil {
+					t.Fatalf("delete %s: %v", blob.Key, err)
+				}
+			}
+		}
+		empty()
+		t.Cleanup(empty)
+		return store
+	})
+}
@@ -0,0 +1,134 @@
+// Package vclock implements version vectors, which record how many
+// changes each device has made to a file, and uses them to tell a client
+// how its copy relates to the server's.
+package vclock
+
+// Vector maps a device ID to the number of changes the device has made.
+// Missing devices count as zero.
+type Vector map[string]uint64
+
+type Ordering int
+
+const (
+	Equal Ordering = iota
+	// Before means the first vector happened before the second one.
+	Before
+	// After means the first vector happened after the second one.
+	After
+	// Concurrent means neither vector has seen all changes of the other.
+	Concurrent
+)
+
+// Copy returns an independent copy of v.
+func (v Vector) Copy() Vector {
+	c := make(Vector, len(v))
+	for device, n := range v {
+		if n > 0 {
+			c[device] = n
+		}
+	}
+	return c
+}
+
+// Increment returns a copy of v with one more change by device.
+func (v Vector) Increment(device string) Vector {
+	c := v.Copy()
+	c[device]++
+	return c
+}
+
+// Merge returns the smallest vector that has seen every change of a and b.
+func Merge(a, b Vector) Vector {
+	c := a.Copy()
+	for device, n := range b {
+		if n > c[device] {
+			c[device] = n
+		}
+	}
+	return c
+}
+
+// Compare orders a relative to b.
+func Compare(a, b Vector) Ordering {
+	aAhead, bAhead := false, false
+	for device, n := range a {
+		if n > b[device] {
+			aAhead = true
+		}
+	}
+	for device, n := range b {
+		if n > a[device] {
+			bAhead = true
+		}
+	}
+
+	switch {
+	case aAhead && bAhead:
+		return Concurrent
+	case aAhead:
+		return After
+	case bAhead:
+		return Before
+	default:
+		return Equal
+	}
+}
+
+// Descends reports whether a has seen every change of b.
+func Descends(a, b Vector) bool {
+	o := Compare(a, b)
+	return o == Equal || o == After
+}
+
+type Status int
+
+const (
+	// InSync means the client has the server's current version.
+	InSync Status = iota
+	// FastForward means the client's copy builds on the server's current
+	// version and can be uploaded as is.
+	FastForward
+	// Behind means the server has changes the client has not seen, and the
+	// client has none of its own.
+	Behind
+	// Conflict means both sides made changes the other has not seen.
+	Conflict
+)
+
+func (s Status) String() string {
+	switch s {
+	case InSync:
+		return "in-sync"
+	case FastForward:
+		return "fast-forward"
+	case Behind:
+		return "behind"
+	case Conflict:
+		return "conflict"
+	}
+	return "unknown"
+}
+
+// Classify compares the vector of the server's current version with the
+// vector the client's copy is based on. localChanges reports whether the
+// client has changed its copy since.
+func Classify(server, client Vector, localChanges bool) Status {
+	switch Compare(client, server) {
+	case Equal:
+		if localChanges {
+			return FastForward
+		}
+		return InSync
+	case Before:
+		if localChanges {
+			return Conflict
+		}
+		return Behind
+	case After:
+		// The client has seen changes the server has lost, for example to
+		// pruning; its copy is the newest there is.
+		return FastForward
+	default:
+		return Conflict
+	}
+}
@@ -0,0 +1,126 @@
+package vclock
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector
+		want Ordering
+	}{
+		{"both empty", nil, Vector{}, Equal},
+		{"equal", Vector{"a": 1, "b": 2}, Vector{"a": 1, "b": 2}, Equal},
+		{"zero entries are missing", Vector{"a": 1, "b": 0}, Vector{"a": 1}, Equal},
+		{"before", Vector{"a": 1}, Vector{"a": 2}, Before},
+		{"before on another device", Vector{"a": 1}, Vector{"a": 1, "b": 1}, Before},
+		{"after", Vector{"a": 3, "b": 1}, Vector{"a": 2, "b": 1}, After},
+		{"after empty", Vector{"a": 1}, nil, After},
+		{"concurrent", Vector{"a": 2, "b": 1}, Vector{"a": 1, "b": 2}, Concurrent},
+		{"concurrent on different devices", Vector{"a": 1}, Vector{"b": 1}, Concurrent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(tt.a, tt.b); got != tt.want {
+				t.Fatalf("Compare(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescends(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector
+		want bool
+	}{
+		{"equal", Vector{"a": 1}, Vector{"a": 1}, true},
+		{"ahead", Vector{"a": 2, "b": 1}, Vector{"a": 1}, true},
+		{"anything descends from empty", Vector{"a": 1}, nil, true},
+		{"behind", Vector{"a": 1}, Vector{"a": 2}, false},
+		{"concurrent", Vector{"a": 1}, Vector{"b": 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Descends(tt.a, tt.b); got != tt.want {
+				t.Fatalf("Descends(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector
+		want Vector
+	}{
+		{"empty", nil, nil, Vector{}},
+		{"one side", Vector{"a": 1}, nil, Vector{"a": 1}},
+		{"maximum per device", Vector{"a": 3, "b": 1}, Vector{"a": 1, "b": 2, "c": 1}, Vector{"a": 3, "b": 2, "c": 1}},
+		{"zero entries dropped", Vector{"a": 0}, Vector{"b": 1}, Vector{"b": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Merge(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Merge(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if !Descends(got, tt.a) || !Descends(got, tt.b) {
+				t.Fatalf("Merge(%v, %v) = %v has not seen both", tt.a, tt.b, got)
+			}
+		})
+	}
+
+	// Merging never changes its arguments.
+	a := Vector{"a": 1}
+	Merge(a, Vector{"a": 5})
+	if a["a"] != 1 {
+		t.Fatalf("Merge modified its argument: %v", a)
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	v := Vector{"a": 1}
+	got := v.Increment("b")
+	if !reflect.DeepEqual(got, Vector{"a": 1, "b": 1}) {
+		t.Fatalf("Increment = %v", got)
+	}
+	if Compare(got, v) != After {
+		t.Fatalf("incremented vector is not after the original")
+	}
+	if len(v) != 1 {
+		t.Fatalf("Increment modified its receiver: %v", v)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	server := Vector{"a": 2, "b": 1}
+
+	tests := []struct {
+		name         string
+		client       Vector
+		localChanges bool
+		want         Status
+	}{
+		{"same version", Vector{"a": 2, "b": 1}, false, InSync},
+		{"edited current version", Vector{"a": 2, "b": 1}, true, FastForward},
+		{"older version", Vector{"a": 1, "b": 1}, false, Behind},
+		{"edited older version", Vector{"a": 1, "b": 1}, true, Conflict},
+		{"newer than server", Vector{"a": 3, "b": 1}, false, FastForward},
+		{"concurrent", Vector{"a": 1, "b": 1, "c": 1}, false, Conflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(server, tt.client, tt.localChanges); got != tt.want {
+				t.Fatalf("Classify(%v, %v, %v) = %v, want %v", server, tt.client, tt.localChanges, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package versioning
+
+import (
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Append adds a version to a file and makes it the file's current version.
+// A vector set on the version is the vector of the copy it was made from;
+// without one the version builds on the current version. A version whose
+// base has not seen the current version was made concurrently with it: it
+// keeps only the changes of its base and is marked as conflicting.
+// The file row stays locked until the transaction ends, so concurrent
+// uploads of one file are numbered 1, 2, 3... in the order they commit.
+// Numbers are never reused, because the latest version is never pruned.
+func Append(tx *gorm.DB, version *models.FileVersion) error {
+	var file models.File
+	if err := tx.Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "current_version_id").
+		First(&file, "id = ?", version.FileID).Error; err != nil {
+		return err
+	}
+
+	var current models.FileVersion
+	if file.CurrentVersionID != nil {
+		if err := tx.Select("id", "vector").
+			First(&current, "id = ?", *file.CurrentVersionID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+	}
+
+	// The new version has seen everything its base has and one more
+	// change by its device. A base that has seen the current version
+	// takes in whatever else the current version has.
+	base := version.Vector
+	if len(base) == 0 || vclock.Descends(base, current.Vector) {
+		base = vclock.Merge(current.Vector, base)
+	} else {
+		version.ConflictsWith = &current.ID
+	}
+	version.Vector = base.Increment(version.DeviceID)
+
+	var latest int
+	if err := tx.Model(&models.FileVersion{}).
+		Where("file_id = ?", version.FileID).
+		Select("COALESCE(MAX(version_num), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	version.VersionNum = latest + 1
+	if err := tx.Create(version).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&models.File{}).
+		Where("id = ?", version.FileID).
+		Update("current_version_id", version.ID).Error
+}
+
+// Ordered sorts versions oldest first. Use it for every query or preload
+// of versions so that results do not depend on the database's row order.
+func Ordered(db *gorm.DB) *gorm.DB {
+	return db.Order("version_num ASC").Order("created_at ASC").Order("id ASC")
+}
+
+// PreloadVersions loads a file's versions oldest first.
+func PreloadVersions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Versions", Ordered)
+}
+
+// Current returns the current version among a file's loaded versions, or
+// nil when the file has none.
+func Current(file *models.File) *models.FileVersion {
+	if len(file.Versions) == 0 {
+		return nil
+	}
+	if file.CurrentVersionID != nil {
+		for i := range file.Versions {
+			if file.Versions[i].ID == *file.CurrentVersionID {
+				return &file.Versions[i]
+			}
+		}
+	}
+
+	latest := &file.Versions[0]
+	for i := range file.Versions {
+		if file.Versions[i].VersionNum > latest.VersionNum {
+			latest = &file.Versions[i]
+		}
+	}
+	return latest
+}
@@ -0,0 +1,165 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/models"
+	"github.com/Shubham-Thakur06/go-distributed-file-syncing-service/internal/vclock"
+)
+
+// newTestDB opens the PostgreSQL database named by TEST_DATABASE_URL, or a
+// SQLite database whose transactions take the write lock up front, which
+// serializes them the way row locks do in PostgreSQL.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dialector := sqlite.Open(filepath.Join(t.TempDir(), "versions.db") + "?_txlock=immediate&_pragma=busy_timeout(10000)")
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		dialector = postgres.Open(dsn)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.File{}, &models.FileVersion{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newTestFile(t *testing.T, db *gorm.DB) *models.File {
+	t.Helper()
+	user := &models.User{Email: t.Name() + "@example.com", Username: t.Name(), Password: "x"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	file := &models.File{Name: "report.txt", Path: "report.txt", OwnerID: user.ID}
+	if err := db.Create(file).Error; err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestAppendConcurrentDevices(t *testing.T) {
+	db := newTestDB(t)
+	file := newTestFile(t, db)
+
+	const devices = 8
+	const uploads = 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, devices*uploads)
+	for d := 0; d < devices; d++ {
+		wg.Add(1)
+		go func(device string) {
+			defer wg.Done()
+			for i := 0; i < uploads; i++ {
+				errs <- db.Transaction(func(tx *gorm.DB) error {
+					return Append(tx, &models.FileVersion{
+						FileID:   file.ID,
+						Hash:     fmt.Sprintf("%s-%d", device, i),
+						S3Key:    "unused",
+						DeviceID: device,
+					})
+				})
+			}
+		}(fmt.Sprintf("device-%d", d))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	var versions []models.FileVersion
+	if err := Ordered(db).Find(&versions, "file_id = ?", file.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != devices*uploads {
+		t.Fatalf("got %d versions, want %d", len(versions), devices*uploads)
+	}
+
+	// Versions are numbered without gaps or duplicates, and each one has
+	// seen every version before it.
+	for i, v := range versions {
+		if v.VersionNum != i+1 {
+			t.Fatalf("version %d is numbered %d", i+1, v.VersionNum)
+		}
+		total := 0
+		for _, count := range v.Vector {
+			total += int(count)
+		}
+		if total != i+1 {
+			t.Fatalf("version %d has vector %v, which has seen %d changes", i+1, v.Vector, total)
+		}
+	}
+
+	if err := db.First(file, "id = ?", file.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	last := versions[len(versions)-1]
+	if file.CurrentVersionID == nil || *file.CurrentVersionID != last.ID {
+		t.Fatalf("current version is %v, want %s", file.CurrentVersionID, last.ID)
+	}
+	for d := 0; d < devices; d++ {
+		if got := last.Vector[fmt.Sprintf("device-%d", d)]; got != uploads {
+			t.Fatalf("device-%d has %d changes in the final vector, want %d", d, got, uploads)
+		}
+	}
+}
+
+func TestAppendFromBase(t *testing.T) {
+	db := newTestDB(t)
+	file := newTestFile(t, db)
+
+	appendFrom := func(device string, base vclock.Vector) *models.FileVersion {
+		t.Helper()
+		version := &models.FileVersion{FileID: file.ID, Hash: device, S3Key: "unused", DeviceID: device, Vector: base}
+		if err := db.Transaction(func(tx *gorm.DB) error { return Append(tx, version) }); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		return version
+	}
+
+	first := appendFrom("laptop", nil)
+	if first.ConflictsWith != nil || !reflect.DeepEqual(first.Vector, vclock.Vector{"laptop": 1}) {
+		t.Fatalf("first version: vector %v, conflicts with %v", first.Vector, first.ConflictsWith)
+	}
+
+	// The phone edits the first version, so builds on it.
+	second := appendFrom("phone", first.Vector)
+	if second.ConflictsWith != nil || !reflect.DeepEqual(second.Vector, vclock.Vector{"laptop": 1, "phone": 1}) {
+		t.Fatalf("second version: vector %v, conflicts with %v", second.Vector, second.ConflictsWith)
+	}
+
+	// The laptop also edited the first version, without seeing the
+	// phone's change.
+	third := appendFrom("laptop", first.Vector)
+	if third.ConflictsWith == nil || *third.ConflictsWith != second.ID {
+		t.Fatalf("third version conflicts with %v, want %s", third.ConflictsWith, second.ID)
+	}
+	if !reflect.DeepEqual(third.Vector, vclock.Vector{"laptop": 2}) {
+		t.Fatalf("third version has vector %v, want only the laptop's changes", third.Vector)
+	}
+	if vclock.Compare(third.Vector, second.Vector) != vclock.Concurrent {
+		t.Fatalf("conflicting versions are not concurrent: %v and %v", third.Vector, second.Vector)
+	}
+
+	// Uploads without a base build on the current version.
+	fourth := appendFrom("tablet", nil)
+	if fourth.ConflictsWith != nil || !vclock.Descends(fourth.Vector, third.Vector) {
+		t.Fatalf("fourth version: vector %v, conflicts with %v", fourth.Vector, fourth.ConflictsWith)
+	}
+}